@@ -7,7 +7,7 @@ module github.com/SpecializedGeneralist/whatsnew
 go 1.17
 
 require (
-	github.com/PuerkitoBio/goquery v1.8.0 // indirect
+	github.com/PuerkitoBio/goquery v1.8.0
 	github.com/SpecializedGeneralist/gdelt v0.3.0
 	github.com/SpecializedGeneralist/hnsw-grpc-server v1.1.0
 	github.com/SpecializedGeneralist/translator v0.0.0-20210830140609-8eb1a3cba7da
@@ -20,7 +20,7 @@ require (
 	github.com/grpc-ecosystem/grpc-gateway/v2 v2.6.0
 	github.com/jackc/pgconn v1.10.0
 	github.com/jackc/pgtype v1.8.1
-	github.com/jackc/pgx/v4 v4.13.0 // indirect
+	github.com/jackc/pgx/v4 v4.13.0
 	github.com/jaytaylor/html2text v0.0.0-20211105163654-bc68cce691ba // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/mattn/go-runewidth v0.0.13 // indirect
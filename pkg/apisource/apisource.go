@@ -0,0 +1,367 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package apisource reads the items of generic JSON HTTP APIs, as
+// described by models.APISource.
+package apisource
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/jsonpath"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxResponseSize is the maximum size of a response body.
+const maxResponseSize = 10 * 1024 * 1024
+
+// Item is an item extracted from an API response.
+type Item struct {
+	URL         string
+	Title       string
+	Description string
+	PublishedAt *time.Time
+}
+
+// PageFunc is called with the items of each page. Returning false stops
+// the pagination.
+type PageFunc func(items []Item) (bool, error)
+
+// Fetcher performs the requests to APISources.
+type Fetcher struct {
+	client         *http.Client
+	requestTimeout time.Duration
+	userAgent      string
+}
+
+// NewFetcher creates a new Fetcher.
+func NewFetcher(requestTimeout time.Duration, userAgent string) *Fetcher {
+	return &Fetcher{
+		client:         &http.Client{},
+		requestTimeout: requestTimeout,
+		userAgent:      userAgent,
+	}
+}
+
+// mapping holds the compiled JSONPaths of an APISource.
+type mapping struct {
+	items       jsonpath.Path
+	url         jsonpath.Path
+	title       jsonpath.Path
+	description jsonpath.Path
+	publishedAt jsonpath.Path
+	nextCursor  jsonpath.Path
+}
+
+// Fetch requests the pages of the APISource, up to its MaxPages, calling fn
+// with the items of each one. The pagination also stops on the first
+// page without items, or without a next cursor.
+func (f *Fetcher) Fetch(ctx context.Context, src *models.APISource, fn PageFunc) error {
+	m, err := compileMapping(src)
+	if err != nil {
+		return err
+	}
+	headers, err := src.HeadersAsMap()
+	if err != nil {
+		return err
+	}
+
+	maxPages := src.MaxPages
+	if maxPages < 1 {
+		maxPages = 1
+	}
+
+	position := src.PaginationStart
+	cursor := ""
+	for page := 0; page < maxPages; page++ {
+		reqURL, err := pageURL(src, position, cursor, page)
+		if err != nil {
+			return err
+		}
+
+		doc, err := f.get(ctx, src, reqURL, headers)
+		if err != nil {
+			return err
+		}
+
+		items, err := extractItems(doc, m, reqURL, src.PublishedAtLayout)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		next, err := fn(items)
+		if err != nil || !next {
+			return err
+		}
+
+		switch src.Pagination {
+		case models.NoPagination, "":
+			return nil
+		case models.PagePagination:
+			position++
+		case models.OffsetPagination:
+			position += len(items)
+		case models.CursorPagination:
+			var ok bool
+			cursor, ok = m.nextCursor.GetString(doc)
+			if !ok || len(cursor) == 0 {
+				return nil
+			}
+		default:
+			return fmt.Errorf("invalid APISource pagination %#v", src.Pagination)
+		}
+	}
+	return nil
+}
+
+// Validate reports whether the APISource is well-formed: its JSONPaths must
+// be valid, and its method, pagination and pagination parameter must be
+// consistent. It does not perform any request.
+func Validate(src *models.APISource) error {
+	if _, err := compileMapping(src); err != nil {
+		return err
+	}
+	if _, err := url.Parse(src.URL); err != nil || len(src.URL) == 0 {
+		return fmt.Errorf("invalid APISource URL %#v", src.URL)
+	}
+	switch strings.ToUpper(src.Method) {
+	case "", http.MethodGet, http.MethodPost:
+	default:
+		return fmt.Errorf("invalid APISource method %#v", src.Method)
+	}
+	switch src.Pagination {
+	case models.NoPagination, "":
+	case models.PagePagination, models.OffsetPagination, models.CursorPagination:
+		if len(src.PaginationParam) == 0 {
+			return fmt.Errorf("the pagination parameter of APISource %d is missing", src.ID)
+		}
+	default:
+		return fmt.Errorf("invalid APISource pagination %#v", src.Pagination)
+	}
+	if _, err := src.HeadersAsMap(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func compileMapping(src *models.APISource) (m mapping, err error) {
+	paths := []struct {
+		dst  *jsonpath.Path
+		expr string
+	}{
+		{&m.items, src.ItemsPath},
+		{&m.url, src.URLPath},
+		{&m.title, src.TitlePath},
+		{&m.description, src.DescriptionPath},
+		{&m.publishedAt, src.PublishedAtPath},
+		{&m.nextCursor, src.NextCursorPath},
+	}
+	for _, p := range paths {
+		*p.dst, err = jsonpath.Compile(p.expr)
+		if err != nil {
+			return mapping{}, err
+		}
+	}
+	if m.url.IsEmpty() {
+		return mapping{}, fmt.Errorf("the URL path of APISource %d is missing", src.ID)
+	}
+	if src.Pagination == models.CursorPagination && m.nextCursor.IsEmpty() {
+		return mapping{}, fmt.Errorf("the next cursor path of APISource %d is missing", src.ID)
+	}
+	return m, nil
+}
+
+// pageURL returns the URL for requesting a page, setting the pagination
+// query parameter. The first page with cursor pagination is requested
+// without any cursor.
+func pageURL(src *models.APISource, position int, cursor string, page int) (*url.URL, error) {
+	u, err := url.Parse(src.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid APISource URL %#v: %w", src.URL, err)
+	}
+
+	var value string
+	switch src.Pagination {
+	case models.PagePagination, models.OffsetPagination:
+		value = strconv.Itoa(position)
+	case models.CursorPagination:
+		if page == 0 {
+			return u, nil
+		}
+		value = cursor
+	default:
+		return u, nil
+	}
+
+	if len(src.PaginationParam) == 0 {
+		return nil, fmt.Errorf("the pagination parameter of APISource %d is missing", src.ID)
+	}
+	q := u.Query()
+	q.Set(src.PaginationParam, value)
+	u.RawQuery = q.Encode()
+	return u, nil
+}
+
+func (f *Fetcher) get(
+	ctx context.Context,
+	src *models.APISource,
+	u *url.URL,
+	headers map[string]string,
+) (_ interface{}, err error) {
+	ctx, cancel := context.WithTimeout(ctx, f.requestTimeout)
+	defer cancel()
+
+	method := strings.ToUpper(src.Method)
+	if len(method) == 0 {
+		method = http.MethodGet
+	}
+	if method != http.MethodGet && method != http.MethodPost {
+		return nil, fmt.Errorf("invalid APISource method %#v", src.Method)
+	}
+
+	var body io.Reader
+	if method == http.MethodPost && src.Body.Valid {
+		body = strings.NewReader(src.Body.String)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request for %#v: %w", u.String(), err)
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing request for %#v: %w", u.String(), err)
+	}
+	defer func() {
+		if e := resp.Body.Close(); e != nil && err == nil {
+			err = fmt.Errorf("error closing response body: %w", e)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request for %#v returned status code %d", u.String(), resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body for %#v: %w", u.String(), err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	err = dec.Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding JSON response for %#v: %w", u.String(), err)
+	}
+	return doc, nil
+}
+
+// extractItems extracts the items from a response. Items without a URL
+// are ignored. Relative URLs are resolved against the request URL.
+func extractItems(doc interface{}, m mapping, base *url.URL, layout string) ([]Item, error) {
+	v, ok := m.items.Get(doc)
+	if !ok || v == nil {
+		return nil, nil
+	}
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the items path %#v does not select an array", m.items.String())
+	}
+
+	items := make([]Item, 0, len(list))
+	for _, raw := range list {
+		rawURL, _ := m.url.GetString(raw)
+		rawURL = strings.TrimSpace(rawURL)
+		if len(rawURL) == 0 {
+			continue
+		}
+		u, err := base.Parse(rawURL)
+		if err != nil {
+			continue
+		}
+
+		item := Item{URL: u.String()}
+		if !m.title.IsEmpty() {
+			item.Title, _ = m.title.GetString(raw)
+			item.Title = strings.TrimSpace(item.Title)
+		}
+		if !m.description.IsEmpty() {
+			item.Description, _ = m.description.GetString(raw)
+			item.Description = strings.TrimSpace(item.Description)
+		}
+		if !m.publishedAt.IsEmpty() {
+			if s, ok := m.publishedAt.GetString(raw); ok {
+				item.PublishedAt = parseTime(s, layout)
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// timeLayouts are the layouts tried when no explicit layout is given.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+func parseTime(s, layout string) *time.Time {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return nil
+	}
+
+	switch layout {
+	case "unix", "unix_ms":
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil
+		}
+		if layout == "unix_ms" {
+			n /= 1000
+		}
+		t := time.Unix(0, int64(n*float64(time.Second))).UTC()
+		return &t
+	case "":
+		for _, l := range timeLayouts {
+			if t, err := time.Parse(l, s); err == nil {
+				t = t.UTC()
+				return &t
+			}
+		}
+		return nil
+	default:
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return nil
+		}
+		t = t.UTC()
+		return &t
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package apisource
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetcher_Fetch(t *testing.T) {
+	t.Parallel()
+
+	fetcher := NewFetcher(5*time.Second, "test-agent")
+
+	t.Run("POST with headers, body and item mapping", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "test-agent", r.Header.Get("User-Agent"))
+			assert.Equal(t, "Bearer xyz", r.Header.Get("Authorization"))
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"type":"press"}`, string(body))
+			_, _ = io.WriteString(w, `{"result": {"docs": [
+				{"link": "/news/1", "headline": " First ", "summary": "One", "date": "2021-11-20T10:00:00+01:00"},
+				{"headline": "Without link"},
+				{"link": "https://other.example.com/2", "headline": "Second", "date": "not a date"}
+			]}}`)
+		}))
+		t.Cleanup(server.Close)
+
+		src := &models.APISource{
+			URL:             server.URL + "/api",
+			Method:          "post",
+			Body:            sql.NullString{String: `{"type":"press"}`, Valid: true},
+			ItemsPath:       "$.result.docs",
+			URLPath:         "link",
+			TitlePath:       "headline",
+			DescriptionPath: "summary",
+			PublishedAtPath: "date",
+			Pagination:      models.NoPagination,
+		}
+		require.NoError(t, src.SetHeaders(map[string]string{"Authorization": "Bearer xyz"}))
+
+		var pages [][]Item
+		err := fetcher.Fetch(context.Background(), src, func(items []Item) (bool, error) {
+			pages = append(pages, items)
+			return true, nil
+		})
+		require.NoError(t, err)
+
+		published := time.Date(2021, 11, 20, 9, 0, 0, 0, time.UTC)
+		assert.Equal(t, [][]Item{{
+			{URL: server.URL + "/news/1", Title: "First", Description: "One", PublishedAt: &published},
+			{URL: "https://other.example.com/2", Title: "Second"},
+		}}, pages)
+	})
+
+	t.Run("page pagination stops on empty page", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			page := r.URL.Query().Get("p")
+			if page == "3" {
+				_, _ = io.WriteString(w, `[]`)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `[{"url": "https://example.com/%s", "ts": 1637402400}]`, page)
+		}))
+		t.Cleanup(server.Close)
+
+		src := &models.APISource{
+			URL:               server.URL + "/api?lang=en",
+			URLPath:           "url",
+			PublishedAtPath:   "ts",
+			PublishedAtLayout: "unix",
+			Pagination:        models.PagePagination,
+			PaginationParam:   "p",
+			PaginationStart:   1,
+			MaxPages:          10,
+		}
+
+		var urls []string
+		err := fetcher.Fetch(context.Background(), src, func(items []Item) (bool, error) {
+			for _, item := range items {
+				urls = append(urls, item.URL)
+				require.NotNil(t, item.PublishedAt)
+				assert.Equal(t, time.Date(2021, 11, 20, 10, 0, 0, 0, time.UTC), *item.PublishedAt)
+			}
+			return true, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/1", "https://example.com/2"}, urls)
+	})
+
+	t.Run("offset pagination stops at max pages or when requested", func(t *testing.T) {
+		t.Parallel()
+		var offsets []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			offsets = append(offsets, r.URL.Query().Get("offset"))
+			_, _ = io.WriteString(w, `{"items": [{"u": "https://example.com/a"}, {"u": "https://example.com/b"}]}`)
+		}))
+		t.Cleanup(server.Close)
+
+		src := &models.APISource{
+			URL:             server.URL,
+			ItemsPath:       "items",
+			URLPath:         "u",
+			Pagination:      models.OffsetPagination,
+			PaginationParam: "offset",
+			MaxPages:        3,
+		}
+		err := fetcher.Fetch(context.Background(), src, func([]Item) (bool, error) { return true, nil })
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0", "2", "4"}, offsets)
+
+		offsets = nil
+		err = fetcher.Fetch(context.Background(), src, func([]Item) (bool, error) { return false, nil })
+		require.NoError(t, err)
+		assert.Equal(t, []string{"0"}, offsets)
+	})
+
+	t.Run("cursor pagination", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("cursor") {
+			case "":
+				_, _ = io.WriteString(w, `{"data": [{"url": "https://example.com/1"}], "next": "c2"}`)
+			case "c2":
+				_, _ = io.WriteString(w, `{"data": [{"url": "https://example.com/2"}], "next": null}`)
+			default:
+				t.Errorf("unexpected request %s", r.URL)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		src := &models.APISource{
+			URL:             server.URL,
+			ItemsPath:       "data",
+			URLPath:         "url",
+			Pagination:      models.CursorPagination,
+			PaginationParam: "cursor",
+			NextCursorPath:  "$.next",
+			MaxPages:        5,
+		}
+		var urls []string
+		err := fetcher.Fetch(context.Background(), src, func(items []Item) (bool, error) {
+			for _, item := range items {
+				urls = append(urls, item.URL)
+			}
+			return true, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/1", "https://example.com/2"}, urls)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/object":
+				_, _ = io.WriteString(w, `{"items": {"url": "https://example.com"}}`)
+			case "/invalid":
+				_, _ = io.WriteString(w, `<html></html>`)
+			default:
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		noop := func([]Item) (bool, error) { return true, nil }
+		sources := []*models.APISource{
+			{URL: server.URL + "/object", ItemsPath: "items", URLPath: "url"},
+			{URL: server.URL + "/invalid", URLPath: "url"},
+			{URL: server.URL + "/private", URLPath: "url"},
+			{URL: server.URL + "/object", ItemsPath: "items"},
+			{URL: server.URL + "/object", URLPath: "url", Method: "DELETE"},
+			{URL: server.URL + "/object", URLPath: "url", Pagination: models.CursorPagination},
+			{URL: server.URL + "/object", URLPath: "url", Pagination: models.PagePagination},
+		}
+		for _, src := range sources {
+			assert.Error(t, fetcher.Fetch(context.Background(), src, noop), src.URL)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := models.APISource{
+		URL:             "https://example.com/api",
+		URLPath:         "$.url",
+		Pagination:      models.CursorPagination,
+		PaginationParam: "cursor",
+		NextCursorPath:  "$.next",
+	}
+	assert.NoError(t, Validate(&valid))
+
+	testCases := []struct {
+		name   string
+		modify func(src *models.APISource)
+	}{
+		{"missing URL path", func(src *models.APISource) { src.URLPath = "" }},
+		{"invalid JSONPath", func(src *models.APISource) { src.TitlePath = "$.title[" }},
+		{"invalid method", func(src *models.APISource) { src.Method = "DELETE" }},
+		{"invalid pagination", func(src *models.APISource) { src.Pagination = "foo" }},
+		{"missing pagination parameter", func(src *models.APISource) { src.PaginationParam = "" }},
+		{"missing next cursor path", func(src *models.APISource) { src.NextCursorPath = "" }},
+		{"missing URL", func(src *models.APISource) { src.URL = "" }},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			src := valid
+			tc.modify(&src)
+			assert.Error(t, Validate(&src))
+		})
+	}
+}
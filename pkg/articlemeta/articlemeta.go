@@ -0,0 +1,247 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package articlemeta extracts the metadata of news articles from the
+// structured data of their web pages: JSON-LD blocks, OpenGraph and Twitter
+// Card meta tags, and the link to the AMP version of the page.
+package articlemeta
+
+import (
+	"encoding/json"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/sets"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Metadata is the metadata of an article.
+type Metadata struct {
+	Headline    string
+	Description string
+	// Body is the full text of the article, when it is provided (only by
+	// JSON-LD "articleBody").
+	Body          string
+	Image         string
+	Authors       []string
+	DatePublished *time.Time
+}
+
+// articleTypes are the schema.org types of the JSON-LD objects which
+// describe an article.
+var articleTypes = sets.NewStringSetWithElements(
+	"Article",
+	"NewsArticle",
+	"AnalysisNewsArticle",
+	"BackgroundNewsArticle",
+	"OpinionNewsArticle",
+	"ReportageNewsArticle",
+	"ReviewNewsArticle",
+	"BlogPosting",
+)
+
+// FromJSONLD returns the metadata of the first JSON-LD object of the
+// document whose type is an article (such as "NewsArticle") and which has a
+// headline. It returns nil if no such object is found. Invalid JSON-LD
+// blocks are ignored.
+func FromJSONLD(doc *goquery.Document) *Metadata {
+	var md *Metadata
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		var v interface{}
+		// Raw line breaks inside strings are invalid JSON, but common.
+		text := strings.NewReplacer("\n", " ", "\r", " ", "\t", " ").Replace(s.Text())
+		if err := json.Unmarshal([]byte(text), &v); err != nil {
+			return true
+		}
+		md = findArticle(v, 0)
+		return md == nil
+	})
+	return md
+}
+
+// maxJSONLDDepth limits the nesting of the JSON-LD values visited while
+// looking for an article.
+const maxJSONLDDepth = 5
+
+func findArticle(v interface{}, depth int) *Metadata {
+	if depth > maxJSONLDDepth {
+		return nil
+	}
+	switch vv := v.(type) {
+	case []interface{}:
+		for _, item := range vv {
+			if md := findArticle(item, depth+1); md != nil {
+				return md
+			}
+		}
+	case map[string]interface{}:
+		if isArticle(vv["@type"]) {
+			if md := newJSONLDMetadata(vv); len(md.Headline) > 0 {
+				return md
+			}
+		}
+		if graph, ok := vv["@graph"]; ok {
+			return findArticle(graph, depth+1)
+		}
+	}
+	return nil
+}
+
+func isArticle(t interface{}) bool {
+	switch tt := t.(type) {
+	case string:
+		return articleTypes.Has(tt)
+	case []interface{}:
+		for _, item := range tt {
+			if s, ok := item.(string); ok && articleTypes.Has(s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newJSONLDMetadata(obj map[string]interface{}) *Metadata {
+	md := &Metadata{
+		Headline:      stringValue(obj["headline"]),
+		Description:   stringValue(obj["description"]),
+		Body:          stringValue(obj["articleBody"]),
+		Image:         jsonLDURL(obj["image"]),
+		Authors:       jsonLDNames(obj["author"]),
+		DatePublished: parseTime(stringValue(obj["datePublished"])),
+	}
+	if len(md.Headline) == 0 {
+		md.Headline = stringValue(obj["name"])
+	}
+	return md
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
+
+// jsonLDURL returns the URL of an image, which can be a plain string, an
+// ImageObject, or a list of them (the first one is returned).
+func jsonLDURL(v interface{}) string {
+	switch vv := v.(type) {
+	case string:
+		return strings.TrimSpace(vv)
+	case map[string]interface{}:
+		return stringValue(vv["url"])
+	case []interface{}:
+		for _, item := range vv {
+			if u := jsonLDURL(item); len(u) > 0 {
+				return u
+			}
+		}
+	}
+	return ""
+}
+
+// jsonLDNames returns the names of the authors, each of which can be a
+// plain string or a Person (or Organization) object.
+func jsonLDNames(v interface{}) []string {
+	names := make([]string, 0)
+	seen := sets.NewStringSet()
+	var visit func(v interface{})
+	visit = func(v interface{}) {
+		switch vv := v.(type) {
+		case string:
+			if name := strings.TrimSpace(vv); len(name) > 0 && !seen.Has(name) {
+				seen.Add(name)
+				names = append(names, name)
+			}
+		case map[string]interface{}:
+			visit(stringValue(vv["name"]))
+		case []interface{}:
+			for _, item := range vv {
+				if _, nested := item.([]interface{}); !nested {
+					visit(item)
+				}
+			}
+		}
+	}
+	visit(v)
+	return names
+}
+
+// FromMetaTags returns the metadata from the OpenGraph meta tags of the
+// document, falling back to the Twitter Card ones for each missing value.
+// It returns nil if no title is found.
+func FromMetaTags(doc *goquery.Document) *Metadata {
+	md := &Metadata{
+		Headline:      metaContent(doc, "og:title", "twitter:title"),
+		Description:   metaContent(doc, "og:description", "twitter:description"),
+		Image:         metaContent(doc, "og:image", "og:image:url", "twitter:image", "twitter:image:src"),
+		Authors:       make([]string, 0),
+		DatePublished: parseTime(metaContent(doc, "article:published_time", "og:article:published_time")),
+	}
+	if len(md.Headline) == 0 {
+		return nil
+	}
+	if author := metaContent(doc, "article:author", "twitter:creator"); len(author) > 0 &&
+		!strings.HasPrefix(author, "http://") && !strings.HasPrefix(author, "https://") {
+		md.Authors = append(md.Authors, author)
+	}
+	return md
+}
+
+// metaContent returns the content of the first non-empty meta tag among
+// the given names. Both "property" and "name" attributes are considered,
+// since sites are inconsistent about them.
+func metaContent(doc *goquery.Document, names ...string) string {
+	for _, name := range names {
+		for _, attr := range []string{"property", "name"} {
+			content := doc.Find(`meta[`+attr+`="`+name+`"]`).First().AttrOr("content", "")
+			if content = strings.TrimSpace(content); len(content) > 0 {
+				return content
+			}
+		}
+	}
+	return ""
+}
+
+// AMPURL returns the absolute URL of the AMP version of the page, as
+// declared by a link with rel "amphtml", or an empty string if there is
+// none. Relative links are resolved against pageURL.
+func AMPURL(doc *goquery.Document, pageURL string) string {
+	href := strings.TrimSpace(doc.Find(`link[rel="amphtml"]`).First().AttrOr("href", ""))
+	if len(href) == 0 {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	u, err := base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.String() == base.String() {
+		return ""
+	}
+	return u.String()
+}
+
+// timeLayouts are the ISO 8601 formats commonly found in structured data.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+func parseTime(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+	return nil
+}
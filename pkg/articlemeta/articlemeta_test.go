@@ -0,0 +1,120 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package articlemeta
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseDocument(t *testing.T, html string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestFromJSONLD(t *testing.T) {
+	t.Parallel()
+
+	doc := parseDocument(t, `<html><head>
+<script type="application/ld+json">{"@type": "Organization", "name": "Example News"}</script>
+<script type="application/ld+json">{ invalid </script>
+<script type="application/ld+json">
+{
+  "@context": "https://schema.org",
+  "@graph": [
+    {"@type": "WebPage", "name": "Page"},
+    {
+      "@type": ["NewsArticle"],
+      "headline": "Storm hits the coast",
+      "description": "A strong storm
+hit the coast.",
+      "articleBody": "The storm arrived at night.",
+      "image": [{"@type": "ImageObject", "url": "https://example.com/storm.jpg"}],
+      "author": [{"@type": "Person", "name": "Jane Doe"}, "John Roe", {"@type": "Person", "name": "Jane Doe"}],
+      "datePublished": "2021-11-20T10:15:00+01:00"
+    }
+  ]
+}
+</script>
+</head><body></body></html>`)
+
+	md := FromJSONLD(doc)
+	require.NotNil(t, md)
+	published := time.Date(2021, 11, 20, 9, 15, 0, 0, time.UTC)
+	assert.Equal(t, &Metadata{
+		Headline:      "Storm hits the coast",
+		Description:   "A strong storm hit the coast.",
+		Body:          "The storm arrived at night.",
+		Image:         "https://example.com/storm.jpg",
+		Authors:       []string{"Jane Doe", "John Roe"},
+		DatePublished: &published,
+	}, md)
+}
+
+func TestFromJSONLD_NoArticle(t *testing.T) {
+	t.Parallel()
+
+	doc := parseDocument(t, `<html><head>
+<script type="application/ld+json">{"@type": "NewsArticle", "description": "No headline"}</script>
+<script type="application/ld+json">[{"@type": "BreadcrumbList"}]</script>
+</head></html>`)
+	assert.Nil(t, FromJSONLD(doc))
+}
+
+func TestFromMetaTags(t *testing.T) {
+	t.Parallel()
+
+	doc := parseDocument(t, `<html><head>
+<meta property="og:title" content=" Storm hits the coast ">
+<meta name="twitter:title" content="Storm (Twitter)">
+<meta name="twitter:description" content="A strong storm.">
+<meta name="twitter:image" content="https://example.com/storm.jpg">
+<meta property="article:author" content="https://example.com/authors/jane">
+<meta property="article:published_time" content="2021-11-20T09:15:00Z">
+</head></html>`)
+
+	md := FromMetaTags(doc)
+	require.NotNil(t, md)
+	published := time.Date(2021, 11, 20, 9, 15, 0, 0, time.UTC)
+	assert.Equal(t, &Metadata{
+		Headline:      "Storm hits the coast",
+		Description:   "A strong storm.",
+		Image:         "https://example.com/storm.jpg",
+		Authors:       []string{},
+		DatePublished: &published,
+	}, md)
+
+	assert.Nil(t, FromMetaTags(parseDocument(t, `<html><head><meta property="og:type" content="article"></head></html>`)))
+}
+
+func TestAMPURL(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"absolute", `<link rel="amphtml" href="https://amp.example.com/a">`, "https://amp.example.com/a"},
+		{"relative", `<link rel="amphtml" href="/amp/a">`, "https://example.com/amp/a"},
+		{"same page", `<link rel="amphtml" href="https://example.com/news/a">`, ""},
+		{"other scheme", `<link rel="amphtml" href="javascript:void(0)">`, ""},
+		{"missing", `<link rel="canonical" href="https://example.com/news/a">`, ""},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			doc := parseDocument(t, "<html><head>"+tc.html+"</head></html>")
+			assert.Equal(t, tc.want, AMPURL(doc, "https://example.com/news/a"))
+		})
+	}
+}
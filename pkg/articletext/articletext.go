@@ -0,0 +1,195 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package articletext provides the selection of the text of WebArticles
+// shared by all workers which process it.
+package articletext
+
+import (
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// Selector selects the text of WebArticles, according to the settings from
+// config.TextSelection.
+type Selector struct {
+	conf config.TextSelection
+}
+
+// Text is the text selected from a WebArticle.
+type Text struct {
+	// Source is the source the text comes from.
+	Source config.TextSource
+	// Language is the language code of the text.
+	Language string
+	// Chunks are the parts of the text, each one no longer than the
+	// configured chunk length. If the text is empty, there are no chunks.
+	Chunks []string
+}
+
+// NewSelector creates a new Selector.
+func NewSelector(conf config.TextSelection) *Selector {
+	return &Selector{conf: conf}
+}
+
+// IsEmpty reports whether the text is empty.
+func (t Text) IsEmpty() bool {
+	return len(t.Chunks) == 0
+}
+
+// String returns the whole text, joining all the chunks.
+func (t Text) String() string {
+	return strings.Join(t.Chunks, " ")
+}
+
+// Select returns the text from the first configured source which is not
+// empty for the given WebArticle. If all sources are empty, the returned
+// Text is empty too.
+func (s *Selector) Select(tx *gorm.DB, wa *models.WebArticle) (Text, error) {
+	return s.SelectFunc(tx, wa, nil)
+}
+
+// SelectFunc is like Select, but it also skips the texts for which the
+// function accept returns false. A nil accept function accepts any text.
+func (s *Selector) SelectFunc(tx *gorm.DB, wa *models.WebArticle, accept func(Text) bool) (Text, error) {
+	r := NewReader(tx, wa)
+
+	for _, source := range s.conf.Sources {
+		text, lang, err := r.Read(source)
+		if err != nil {
+			return Text{}, err
+		}
+		text = Truncate(normalizeSpace(text), s.conf.MaxLength)
+		if len(text) == 0 {
+			continue
+		}
+
+		t := Text{
+			Source:   source,
+			Language: lang,
+			Chunks:   Chunk(text, s.conf.ChunkLength),
+		}
+		if accept == nil || accept(t) {
+			return t, nil
+		}
+	}
+	return Text{}, nil
+}
+
+// A Reader reads the texts of a WebArticle from any source, fetching the
+// related records from the database only when needed.
+type Reader struct {
+	tx             *gorm.DB
+	wa             *models.WebArticle
+	content        *models.WebArticleContent
+	contentLoaded  bool
+	feedItem       *models.FeedItem
+	feedItemLoaded bool
+}
+
+// NewReader creates a new Reader for the given WebArticle.
+//
+// If wa.Content is already preloaded, it is used as it is.
+func NewReader(tx *gorm.DB, wa *models.WebArticle) *Reader {
+	return &Reader{
+		tx:            tx,
+		wa:            wa,
+		content:       wa.Content,
+		contentLoaded: wa.Content != nil,
+	}
+}
+
+// Read returns the text of the WebArticle from the given source, together
+// with its language code. The text is returned as it is, possibly empty.
+func (r *Reader) Read(source config.TextSource) (text, lang string, err error) {
+	wa := r.wa
+	switch source {
+	case config.TitleTextSource:
+		return wa.Title, wa.Language, nil
+	case config.TranslatedTitleTextSource:
+		if !wa.TranslatedTitle.Valid {
+			return "", "", nil
+		}
+		return wa.TranslatedTitle.String, wa.TranslationLanguage.String, nil
+	case config.TitleAndDescriptionTextSource:
+		content, err := r.getContent()
+		if err != nil || content == nil || !content.MetaDescription.Valid {
+			return "", "", err
+		}
+		return wa.Title + "\n" + content.MetaDescription.String, wa.Language, nil
+	case config.DescriptionTextSource:
+		content, err := r.getContent()
+		if err != nil || content == nil {
+			return "", "", err
+		}
+		return content.MetaDescription.String, wa.Language, nil
+	case config.BodyTextSource:
+		content, err := r.getContent()
+		if err != nil || content == nil {
+			return "", "", err
+		}
+		return content.Body, wa.Language, nil
+	case config.FeedDescriptionTextSource:
+		item, err := r.getFeedItem()
+		if err != nil || item == nil {
+			return "", "", err
+		}
+		return StripHTML(item.Description), wa.Language, nil
+	case config.FeedContentTextSource:
+		item, err := r.getFeedItem()
+		if err != nil || item == nil {
+			return "", "", err
+		}
+		return StripHTML(item.Content), wa.Language, nil
+	case config.TweetTextSource:
+		text, err := r.getTweetText()
+		return text, wa.Language, err
+	default:
+		return "", "", fmt.Errorf("invalid text source %#v", source)
+	}
+}
+
+func (r *Reader) getContent() (*models.WebArticleContent, error) {
+	if r.contentLoaded {
+		return r.content, nil
+	}
+	var content models.WebArticleContent
+	res := r.tx.Limit(1).Find(&content, "web_article_id = ?", r.wa.ID)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error fetching WebArticleContent of WebArticle %d: %w", r.wa.ID, res.Error)
+	}
+	r.contentLoaded = true
+	if res.RowsAffected > 0 {
+		r.content = &content
+	}
+	return r.content, nil
+}
+
+func (r *Reader) getFeedItem() (*models.FeedItem, error) {
+	if r.feedItemLoaded {
+		return r.feedItem, nil
+	}
+	var item models.FeedItem
+	res := r.tx.Select("description", "content").Limit(1).Find(&item, "web_resource_id = ?", r.wa.WebResourceID)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error fetching FeedItem of WebResource %d: %w", r.wa.WebResourceID, res.Error)
+	}
+	r.feedItemLoaded = true
+	if res.RowsAffected > 0 {
+		r.feedItem = &item
+	}
+	return r.feedItem, nil
+}
+
+func (r *Reader) getTweetText() (string, error) {
+	var tweet models.Tweet
+	res := r.tx.Select("text").Limit(1).Find(&tweet, "web_resource_id = ?", r.wa.WebResourceID)
+	if res.Error != nil {
+		return "", fmt.Errorf("error fetching Tweet of WebResource %d: %w", r.wa.WebResourceID, res.Error)
+	}
+	return tweet.Text, nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package articletext
+
+import (
+	"database/sql"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSelector_SelectFunc(t *testing.T) {
+	t.Parallel()
+
+	wa := &models.WebArticle{
+		Title:               " Titolo ",
+		Language:            "it",
+		TranslatedTitle:     sql.NullString{String: "Title", Valid: true},
+		TranslationLanguage: sql.NullString{String: "en", Valid: true},
+		Content: &models.WebArticleContent{
+			Body:            "First  sentence.\n\nSecond sentence.",
+			MetaDescription: sql.NullString{String: "Descrizione", Valid: true},
+		},
+	}
+	untranslated := &models.WebArticle{Title: "Titolo", Language: "it"}
+
+	testCases := []struct {
+		name   string
+		wa     *models.WebArticle
+		conf   config.TextSelection
+		accept func(Text) bool
+		want   Text
+	}{
+		{
+			name: "title",
+			wa:   wa,
+			conf: config.TextSelection{Sources: []config.TextSource{config.TitleTextSource}},
+			want: Text{Source: config.TitleTextSource, Language: "it", Chunks: []string{"Titolo"}},
+		},
+		{
+			name: "translated title",
+			wa:   wa,
+			conf: config.TextSelection{Sources: []config.TextSource{config.TranslatedTitleTextSource, config.TitleTextSource}},
+			want: Text{Source: config.TranslatedTitleTextSource, Language: "en", Chunks: []string{"Title"}},
+		},
+		{
+			name: "fallback to title",
+			wa:   untranslated,
+			conf: config.TextSelection{Sources: []config.TextSource{config.TranslatedTitleTextSource, config.TitleTextSource}},
+			want: Text{Source: config.TitleTextSource, Language: "it", Chunks: []string{"Titolo"}},
+		},
+		{
+			name: "title and description",
+			wa:   wa,
+			conf: config.TextSelection{Sources: []config.TextSource{config.TitleAndDescriptionTextSource}},
+			want: Text{Source: config.TitleAndDescriptionTextSource, Language: "it", Chunks: []string{"Titolo Descrizione"}},
+		},
+		{
+			name: "body with truncation and chunking",
+			wa:   wa,
+			conf: config.TextSelection{
+				Sources:     []config.TextSource{config.BodyTextSource},
+				MaxLength:   30,
+				ChunkLength: 16,
+			},
+			want: Text{Source: config.BodyTextSource, Language: "it", Chunks: []string{"First sentence.", "Second"}},
+		},
+		{
+			name:   "accept function",
+			wa:     wa,
+			conf:   config.TextSelection{Sources: []config.TextSource{config.TitleTextSource, config.TranslatedTitleTextSource}},
+			accept: func(t Text) bool { return t.Language == "en" },
+			want:   Text{Source: config.TranslatedTitleTextSource, Language: "en", Chunks: []string{"Title"}},
+		},
+		{
+			name: "nothing found",
+			wa:   untranslated,
+			conf: config.TextSelection{Sources: []config.TextSource{config.TranslatedTitleTextSource}},
+			want: Text{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			text, err := NewSelector(tc.conf).SelectFunc(nil, tc.wa, tc.accept)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, text)
+			assert.Equal(t, len(tc.want.Chunks) == 0, text.IsEmpty())
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "foo bar baz", Truncate("foo bar baz", 0))
+	assert.Equal(t, "foo bar baz", Truncate("foo bar baz", 11))
+	assert.Equal(t, "foo bar", Truncate("foo bar baz", 8))
+	assert.Equal(t, "foo bar", Truncate("foo bar baz", 9))
+	assert.Equal(t, "foo", Truncate("foobar", 3))
+	assert.Equal(t, "àèì", Truncate("àèìòù", 3))
+}
+
+func TestChunk(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []string{}, Chunk("  ", 10))
+	assert.Equal(t, []string{"foo bar baz"}, Chunk(" foo  bar\nbaz ", 0))
+	assert.Equal(t, []string{"foo bar", "baz"}, Chunk("foo bar baz", 7))
+	assert.Equal(t, []string{"foo", "bar", "baz"}, Chunk("foo bar baz", 6))
+	assert.Equal(t, []string{"a", "abcd", "efgh", "ij b"}, Chunk("a abcdefghij b", 4))
+	assert.Equal(t, []string{"àè", "ìò"}, Chunk("àè ìò", 3))
+}
+
+func TestStripHTML(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "Tom & Jerry", StripHTML("Tom &amp; Jerry"))
+	assert.Equal(t, "Hello world !", StripHTML("<p>Hello <b>world</b></p>!"))
+}
+
+func TestSplitSentences(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		text     string
+		expected []string
+	}{
+		{"", []string{}},
+		{"Hello world", []string{"Hello world"}},
+		{"Hello world. How are you?  Fine!", []string{"Hello world.", "How are you?", "Fine!"}},
+		{"He said \"Stop!\" Then he left.", []string{"He said \"Stop!\"", "Then he left."}},
+		{"Mr. Smith met Dr. Jones, i.e. a friend.", []string{"Mr. Smith met Dr. Jones, i.e. a friend."}},
+		{"J. R. R. Tolkien wrote it. It costs 3.5 dollars...", []string{"J. R. R. Tolkien wrote it.", "It costs 3.5 dollars..."}},
+		{"Wait… what?! Really.", []string{"Wait…", "what?!", "Really."}},
+		{"今日は晴れです。明日は雨？", []string{"今日は晴れです。", "明日は雨？"}},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, SplitSentences(tc.text), tc.text)
+	}
+}
+
+func TestSegment(t *testing.T) {
+	t.Parallel()
+	text := "One two. Three four five.\n\n  Six.  Seven eight nine ten eleven. \n"
+
+	assert.Equal(t, [][]string{}, Segment(" \n ", 10))
+	assert.Equal(t, [][]string{
+		{"One two.", "Three four five."},
+		{"Six.", "Seven eight nine ten eleven."},
+	}, Segment(text, 0))
+	assert.Equal(t, [][]string{
+		{"One two. Three four five."},
+		{"Six. Seven eight nine ten", "eleven."},
+	}, Segment(text, 25))
+
+	expected := "One two. Three four five.\nSix. Seven eight nine ten eleven."
+	assert.Equal(t, expected, JoinSegments(Segment(text, 0)))
+	assert.Equal(t, expected, JoinSegments(Segment(text, 25)))
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package articletext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// abbreviations are common lowercase abbreviations (without the final
+// period) after which a period does not end a sentence.
+var abbreviations = map[string]struct{}{
+	"mr": {}, "mrs": {}, "ms": {}, "dr": {}, "prof": {}, "st": {}, "vs": {},
+	"etc": {}, "e.g": {}, "i.e": {}, "jr": {}, "sr": {}, "no": {}, "inc": {},
+	"ltd": {}, "co": {},
+}
+
+// SplitSentences splits a single paragraph of text into sentences, using
+// simple punctuation-based rules. Whitespace is normalized to single spaces.
+func SplitSentences(text string) []string {
+	text = normalizeSpace(text)
+	sentences := make([]string, 0)
+
+	start := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+
+		switch r {
+		case '。', '！', '？':
+			i = skipClosers(text, i)
+		case '.', '!', '?', '…':
+			i = skipClosers(text, i)
+			if i < len(text) && text[i] != ' ' {
+				continue
+			}
+			if r == '.' && isAbbreviation(text[start:i]) {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if s := strings.TrimSpace(text[start:i]); len(s) > 0 {
+			sentences = append(sentences, s)
+		}
+		start = i
+	}
+	if s := strings.TrimSpace(text[start:]); len(s) > 0 {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+// skipClosers returns the position after any sequence of further
+// terminals, closing quotes and brackets starting at i.
+func skipClosers(text string, i int) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !strings.ContainsRune(".!?…。！？\"'”’»)]", r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// isAbbreviation reports whether the last word of the given text, which
+// ends with a period, is a single-letter initial or a common abbreviation.
+func isAbbreviation(text string) bool {
+	word := text[strings.LastIndexByte(text, ' ')+1:]
+	word = strings.TrimLeftFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	word = strings.TrimSuffix(word, ".")
+	if utf8.RuneCountInString(word) == 1 {
+		return unicode.IsLetter([]rune(word)[0])
+	}
+	_, ok := abbreviations[strings.ToLower(word)]
+	return ok
+}
+
+// Segment splits the text into paragraphs (separated by newlines), and each
+// paragraph into segments of at most maxLength characters (runes). Segments
+// are made of whole consecutive sentences; sentences longer than maxLength
+// are further split with Chunk. If maxLength is zero or negative, each
+// sentence is a segment on its own.
+//
+// The original text, with normalized whitespace, can be rebuilt with
+// JoinSegments.
+func Segment(text string, maxLength int) [][]string {
+	paragraphs := make([][]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		sentences := SplitSentences(line)
+		if len(sentences) == 0 {
+			continue
+		}
+		if maxLength <= 0 {
+			paragraphs = append(paragraphs, sentences)
+			continue
+		}
+
+		segments := make([]string, 0, len(sentences))
+		current, currentLen := "", 0
+		for _, sentence := range sentences {
+			for _, part := range Chunk(sentence, maxLength) {
+				partLen := utf8.RuneCountInString(part)
+				if currentLen > 0 && currentLen+1+partLen <= maxLength {
+					current += " " + part
+					currentLen += 1 + partLen
+					continue
+				}
+				if currentLen > 0 {
+					segments = append(segments, current)
+				}
+				current, currentLen = part, partLen
+			}
+		}
+		segments = append(segments, current)
+		paragraphs = append(paragraphs, segments)
+	}
+	return paragraphs
+}
+
+// JoinSegments is the reverse of Segment: segments are joined with spaces,
+// and paragraphs with newlines.
+func JoinSegments(paragraphs [][]string) string {
+	lines := make([]string, len(paragraphs))
+	for i, segments := range paragraphs {
+		lines[i] = strings.Join(segments, " ")
+	}
+	return strings.Join(lines, "\n")
+}
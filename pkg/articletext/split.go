@@ -0,0 +1,104 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package articletext
+
+import (
+	"golang.org/x/net/html"
+	"strings"
+	"unicode"
+)
+
+// Truncate shortens the text to at most maxLength characters (runes),
+// possibly cutting at the last whitespace, so that the last word is not
+// broken. If maxLength is zero or negative, the text is returned unmodified.
+func Truncate(text string, maxLength int) string {
+	runes := []rune(text)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return text
+	}
+
+	cut := maxLength
+	if !unicode.IsSpace(runes[cut]) {
+		for i := cut - 1; i > 0; i-- {
+			if unicode.IsSpace(runes[i]) {
+				cut = i
+				break
+			}
+		}
+	}
+	return strings.TrimSpace(string(runes[:cut]))
+}
+
+// Chunk splits the text into parts of at most chunkLength characters
+// (runes), breaking at whitespaces. Words longer than chunkLength are split
+// as well. If chunkLength is zero or negative, the whole text is returned
+// as single chunk. Whitespace is normalized to single spaces.
+func Chunk(text string, chunkLength int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{}
+	}
+	if chunkLength <= 0 {
+		return []string{strings.Join(words, " ")}
+	}
+
+	chunks := make([]string, 0)
+	var sb strings.Builder
+	sbLen := 0
+
+	flush := func() {
+		if sbLen > 0 {
+			chunks = append(chunks, sb.String())
+			sb.Reset()
+			sbLen = 0
+		}
+	}
+
+	for _, word := range words {
+		runes := []rune(word)
+		for len(runes) > chunkLength {
+			flush()
+			chunks = append(chunks, string(runes[:chunkLength]))
+			runes = runes[chunkLength:]
+		}
+		if sbLen > 0 && sbLen+1+len(runes) > chunkLength {
+			flush()
+		}
+		if sbLen > 0 {
+			sb.WriteByte(' ')
+			sbLen++
+		}
+		sb.WriteString(string(runes))
+		sbLen += len(runes)
+	}
+	flush()
+
+	return chunks
+}
+
+// StripHTML returns the text content of an HTML fragment, as commonly
+// found in feed items descriptions, with normalized whitespace.
+func StripHTML(s string) string {
+	if !strings.Contains(s, "<") {
+		return html.UnescapeString(s)
+	}
+
+	var sb strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return normalizeSpace(sb.String())
+		case html.TextToken:
+			sb.Write(z.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			sb.WriteByte(' ')
+		}
+	}
+}
+
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bertencoder
+
+import (
+	"context"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/grpcconn"
+	"github.com/nlpodyssey/spago/pkg/mat32"
+	bertgrpcapi "github.com/nlpodyssey/spago/pkg/nlp/transformers/bert/grpcapi"
+	"github.com/rs/zerolog"
+)
+
+// Encoder creates dense vector representations of texts, using a spaGO BERT
+// gRPC service.
+type Encoder struct {
+	conf config.GRPCServer
+	log  zerolog.Logger
+}
+
+// New creates a new Encoder.
+func New(conf config.GRPCServer, log zerolog.Logger) *Encoder {
+	return &Encoder{
+		conf: conf,
+		log:  log,
+	}
+}
+
+// Encode returns a dense vector representation of the given text.
+// It is expected to work well with models such as LaBSE (Language-agnostic
+// BERT Sentence Embedding).
+//
+// It simply calls the remote BERT Encode method to get a vector, which is
+// then normalized and returned.
+func (e *Encoder) Encode(ctx context.Context, text string) ([]float32, error) {
+	return e.EncodeChunks(ctx, []string{text})
+}
+
+// EncodeChunks returns a dense vector representation of a text which was
+// split into chunks. Each chunk is encoded separately, and the normalized
+// average of all vectors is returned.
+func (e *Encoder) EncodeChunks(ctx context.Context, chunks []string) ([]float32, error) {
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("no text to encode")
+	}
+
+	bertConn, err := grpcconn.Dial(ctx, e.conf)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := bertConn.Close(); err != nil {
+			e.log.Err(err).Msg("error closing BERT connection")
+		}
+	}()
+	bertClient := bertgrpcapi.NewBERTClient(bertConn)
+
+	var sum *mat32.Dense
+	for _, chunk := range chunks {
+		request := &bertgrpcapi.EncodeRequest{Text: chunk}
+		encoding, err := bertClient.Encode(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("BERT encoding error: %w", err)
+		}
+		vec := mat32.NewVecDense(encoding.Vector).Normalize2()
+		if sum == nil {
+			sum = vec
+			continue
+		}
+		sum.AddInPlace(vec)
+	}
+	return sum.Normalize2().Data(), nil
+}
@@ -12,14 +12,27 @@ import (
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/classifytext"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/db"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/detectduplicates"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/discoverfeeds"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/extractinformation"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/fetchapisources"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/fetchfeeds"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/fetchgdelt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/fetchgdeltgkg"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/fetchnewsletters"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/fetchsitemaps"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/opml"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/parsegeo"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/purgehnsw"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/recoverjobs"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/retryscrapes"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/scheduleapisources"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/schedulefeeds"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/schedulemastodon"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/schedulenewsletters"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/schedulesitemaps"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/scheduletwitter"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/scrapefailures"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/scrapemastodon"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/scrapetwitter"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/scrapeweb"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command/server"
@@ -39,11 +52,22 @@ var (
 	commands = []*command.Command{
 		db.CmdDB,
 		server.CmdServer,
+		discoverfeeds.CmdDiscoverFeeds,
+		opml.CmdOPML,
 		schedulefeeds.CmdScheduleFeeds,
 		scheduletwitter.CmdScheduleTwitter,
+		schedulemastodon.CmdScheduleMastodon,
+		schedulesitemaps.CmdScheduleSitemaps,
+		scheduleapisources.CmdScheduleAPISources,
+		schedulenewsletters.CmdScheduleNewsletters,
 		fetchfeeds.CmdFetchFeeds,
+		fetchsitemaps.CmdFetchSitemaps,
+		fetchapisources.CmdFetchAPISources,
+		fetchnewsletters.CmdFetchNewsletters,
 		fetchgdelt.CmdFetchGDELT,
+		fetchgdeltgkg.CmdFetchGDELTGKG,
 		scrapetwitter.CmdScrapeTwitter,
+		scrapemastodon.CmdScrapeMastodon,
 		scrapeweb.CmdScrapeWeb,
 		translate.CmdTranslate,
 		zeroshotclassify.CmdZeroShotClassify,
@@ -53,6 +77,8 @@ var (
 		detectduplicates.CmdDetectDuplicates,
 		extractinformation.CmdExtractInformation,
 		recoverjobs.CmdRecoverJobs,
+		retryscrapes.CmdRetryScrapes,
+		scrapefailures.CmdScrapeFailures,
 		purgehnsw.CmdPurgeHNSW,
 	}
 )
@@ -0,0 +1,45 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ParseBackfillArgs parses the arguments of a "backfill" sub-command
+// ("-from <time> [-to <time>]", in RFC 3339 format), returning the time
+// range to process. The end of the range defaults to the current time.
+func ParseBackfillArgs(args []string) (from, to time.Time, err error) {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fromFlag := fs.String("from", "", "")
+	toFlag := fs.String("to", "", "")
+	err = fs.Parse(args)
+	if err != nil {
+		return from, to, InvalidArguments(err.Error())
+	}
+	if fs.NArg() != 0 || len(*fromFlag) == 0 {
+		return from, to, ErrInvalidArguments
+	}
+
+	from, err = time.Parse(time.RFC3339, *fromFlag)
+	if err != nil {
+		return from, to, InvalidArguments(fmt.Sprintf("invalid -from time: %v", err))
+	}
+	to = time.Now().UTC()
+	if len(*toFlag) > 0 {
+		to, err = time.Parse(time.RFC3339, *toFlag)
+		if err != nil {
+			return from, to, InvalidArguments(fmt.Sprintf("invalid -to time: %v", err))
+		}
+	}
+	if to.Before(from) {
+		return from, to, InvalidArguments("the -to time precedes the -from time")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package command_test
+
+import (
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseBackfillArgs(t *testing.T) {
+	t.Parallel()
+
+	from, to, err := command.ParseBackfillArgs([]string{"-from", "2021-11-01T00:00:00Z", "-to", "2021-11-02T00:00:00Z"})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2021, 11, 2, 0, 0, 0, 0, time.UTC), to)
+
+	before := time.Now().UTC()
+	_, to, err = command.ParseBackfillArgs([]string{"-from", "2021-11-01T00:00:00Z"})
+	require.NoError(t, err)
+	assert.False(t, to.Before(before))
+
+	invalid := [][]string{
+		{},
+		{"-to", "2021-11-02T00:00:00Z"},
+		{"-from", "2021-11-01"},
+		{"-from", "2021-11-01T00:00:00Z", "-to", "tomorrow"},
+		{"-from", "2021-11-02T00:00:00Z", "-to", "2021-11-01T00:00:00Z"},
+		{"-from", "2021-11-01T00:00:00Z", "extra"},
+		{"-unknown"},
+	}
+	for _, args := range invalid {
+		_, _, err = command.ParseBackfillArgs(args)
+		assert.True(t, command.IsInvalidArguments(err), "%v", args)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package discoverfeeds
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/feeddiscovery"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// CmdDiscoverFeeds implements the command "whatsnew discover-feeds".
+var CmdDiscoverFeeds = &command.Command{
+	Name:      "discover-feeds",
+	UsageLine: "discover-feeds [-create] [-tags tag1,tag2] url...",
+	Short:     "find the feeds of websites",
+	Long: `
+The command "discover-feeds" fetches the web page at each given URL, and looks
+for the RSS, Atom or JSON feeds linked from it. Each candidate is validated
+by fetching and parsing it. If none of the linked feeds is valid, some common
+feed paths of the website are tried instead.
+
+The valid feeds are printed on the standard output, one per line, with their
+type and title.
+
+The flags are:
+
+	-create
+		Create a new enabled Feed for each discovered feed which is not
+		yet in the database.
+
+	-tags tag1,tag2
+		Comma-separated tags of the new Feeds.
+`,
+	Run: Run,
+}
+
+// Run runs the command "whatsnew discover-feeds".
+func Run(ctx context.Context, conf *config.Config, args []string) (err error) {
+	fs := flag.NewFlagSet("discover-feeds", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	create := fs.Bool("create", false, "")
+	tags := fs.String("tags", "", "")
+	if err := fs.Parse(args); err != nil {
+		return command.InvalidArguments(err.Error())
+	}
+	if fs.NArg() == 0 {
+		return command.ErrInvalidArguments
+	}
+
+	discoverer := feeddiscovery.New(conf.Server.FeedDiscovery)
+
+	var feeds []feeddiscovery.Feed
+	for _, siteURL := range fs.Args() {
+		found, err := discoverer.Discover(ctx, siteURL)
+		if err != nil {
+			return fmt.Errorf("error discovering feeds of %#v: %w", siteURL, err)
+		}
+		if len(found) == 0 {
+			_, _ = fmt.Fprintf(os.Stderr, "no feeds found for %s\n", siteURL)
+		}
+		feeds = append(feeds, found...)
+	}
+
+	stored := make([]feeddiscovery.StoredFeed, len(feeds))
+	for i, feed := range feeds {
+		stored[i] = feeddiscovery.StoredFeed{Feed: feed}
+	}
+
+	if *create && len(feeds) > 0 {
+		db, err := database.OpenDB(conf.DB)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if e := database.CloseDB(db); e != nil && err == nil {
+				err = e
+			}
+		}()
+
+		stored, err = feeddiscovery.Store(db.WithContext(ctx), feeds, splitTags(*tags))
+		if err != nil {
+			return err
+		}
+	}
+
+	return printFeeds(stored, *create)
+}
+
+func printFeeds(feeds []feeddiscovery.StoredFeed, withStatus bool) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, feed := range feeds {
+		if withStatus {
+			status := "existing"
+			if feed.Created {
+				status = "created"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s %d\n", feed.URL, feed.Type, feed.Title, status, feed.FeedID)
+		} else {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", feed.URL, feed.Type, feed.Title)
+		}
+	}
+	return w.Flush()
+}
+
+func splitTags(s string) []string {
+	if len(strings.TrimSpace(s)) == 0 {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
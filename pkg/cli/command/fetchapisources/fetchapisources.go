@@ -0,0 +1,53 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetchapisources
+
+import (
+	"context"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/apifetcher"
+)
+
+// CmdFetchAPISources implements the command "whatsnew fetch-api-sources".
+var CmdFetchAPISources = &command.Command{
+	Name:      "fetch-api-sources",
+	UsageLine: "fetch-api-sources",
+	Short:     "run the worker to fetch API sources and get new API items",
+	Long: `
+The "fetch-api-sources" command runs the worker for fetching API sources and
+getting new API items.
+`,
+	Run: Run,
+}
+
+// Run runs the command "whatsnew fetch-api-sources".
+func Run(_ context.Context, conf *config.Config, args []string) (err error) {
+	if len(args) != 0 {
+		return command.ErrInvalidArguments
+	}
+
+	db, err := database.OpenDB(conf.DB)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := database.CloseDB(db); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	fk, err := workers.NewManager(conf.Faktory)
+	if err != nil {
+		return err
+	}
+
+	af := apifetcher.New(conf.Workers.APIFetcher, db, fk)
+	af.Run()
+
+	return nil
+}
@@ -16,24 +16,49 @@ import (
 // CmdFetchGDELT implements the command "whatsnew fetch-gdelt".
 var CmdFetchGDELT = &command.Command{
 	Name:      "fetch-gdelt",
-	UsageLine: "fetch-gdelt",
+	UsageLine: "fetch-gdelt [backfill -from <time> [-to <time>]]",
 	Short:     "fetch latest news from GDELT",
 	Long: `
 The command "fetch-gdelt" starts a process which periodically fetches
 events from GDELT Master CSV Data File List. For each event, the source
 URL is extracted (a link to the first news report it found this event in).
-If present, a new WebResource is created and new related jobs are scheduled 
+If present, a new WebResource is created and new related jobs are scheduled
 (as configured).
+
+Every processed GDELT update (published every 15 minutes) is recorded.
+When the process starts, the updates published since the last processed
+one are fetched as well, within the configured catch-up limit.
+
+With the "backfill" sub-command, the GDELT updates published in the given
+time range (both ends included) are processed once, and the command
+terminates. The time values must be in RFC 3339 format (for example,
+"2021-11-20T00:00:00Z"); the end of the range defaults to the current
+time. The updates which were already processed are skipped.
 `,
 	Run: Run,
 }
 
 // Run runs the command "whatsnew fetch-gdelt".
 func Run(ctx context.Context, conf *config.Config, args []string) error {
-	if len(args) != 0 {
+	if len(args) == 0 {
+		return run(conf, func(gf *gdeltfetcher.GDELTFetcher) error {
+			return gf.Run(ctx)
+		})
+	}
+	if args[0] != "backfill" {
 		return command.ErrInvalidArguments
 	}
 
+	from, to, err := command.ParseBackfillArgs(args[1:])
+	if err != nil {
+		return err
+	}
+	return run(conf, func(gf *gdeltfetcher.GDELTFetcher) error {
+		return gf.Backfill(ctx, from, to)
+	})
+}
+
+func run(conf *config.Config, fn func(*gdeltfetcher.GDELTFetcher) error) (err error) {
 	db, err := database.OpenDB(conf.DB)
 	if err != nil {
 		return err
@@ -54,6 +79,5 @@ func Run(ctx context.Context, conf *config.Config, args []string) error {
 		}
 	}()
 
-	fs := gdeltfetcher.New(conf.Tasks.GDELTFetcher, db, fk)
-	return fs.Run(ctx)
+	return fn(gdeltfetcher.New(conf.Tasks.GDELTFetcher, db, fk))
 }
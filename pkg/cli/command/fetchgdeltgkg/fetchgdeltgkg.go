@@ -0,0 +1,87 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetchgdeltgkg
+
+import (
+	"context"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/tasks/gdeltgkgfetcher"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers"
+)
+
+// CmdFetchGDELTGKG implements the command "whatsnew fetch-gdelt-gkg".
+var CmdFetchGDELTGKG = &command.Command{
+	Name:      "fetch-gdelt-gkg",
+	UsageLine: "fetch-gdelt-gkg [backfill -from <time> [-to <time>]]",
+	Short:     "fetch latest news from GDELT Global Knowledge Graph",
+	Long: `
+The command "fetch-gdelt-gkg" starts a process which periodically fetches
+the records of the GDELT Global Knowledge Graph (GKG). Each record describes
+a source document, with its themes, persons, organizations, locations and
+tone. For each record of a web document allowed by the configured theme
+and tone filters, a new WebResource is created and new related jobs are
+scheduled (as configured).
+
+The data files are read from the configured base URL, which can also be
+a local directory containing sample files.
+
+Every processed GKG update (published every 15 minutes) is recorded.
+When the process starts, the updates published since the last processed
+one are fetched as well, within the configured catch-up limit.
+
+With the "backfill" sub-command, the GKG updates published in the given
+time range (both ends included) are processed once, and the command
+terminates. The time values must be in RFC 3339 format (for example,
+"2021-11-20T00:00:00Z"); the end of the range defaults to the current
+time. The updates which were already processed are skipped.
+`,
+	Run: Run,
+}
+
+// Run runs the command "whatsnew fetch-gdelt-gkg".
+func Run(ctx context.Context, conf *config.Config, args []string) error {
+	if len(args) == 0 {
+		return run(conf, func(gf *gdeltgkgfetcher.GDELTGKGFetcher) error {
+			return gf.Run(ctx)
+		})
+	}
+	if args[0] != "backfill" {
+		return command.ErrInvalidArguments
+	}
+
+	from, to, err := command.ParseBackfillArgs(args[1:])
+	if err != nil {
+		return err
+	}
+	return run(conf, func(gf *gdeltgkgfetcher.GDELTGKGFetcher) error {
+		return gf.Backfill(ctx, from, to)
+	})
+}
+
+func run(conf *config.Config, fn func(*gdeltgkgfetcher.GDELTGKGFetcher) error) (err error) {
+	db, err := database.OpenDB(conf.DB)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := database.CloseDB(db); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	fk, err := workers.NewClient(conf.Faktory)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := fk.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	return fn(gdeltgkgfetcher.New(conf.Tasks.GDELTGKGFetcher, db, fk))
+}
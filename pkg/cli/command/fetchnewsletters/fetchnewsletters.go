@@ -0,0 +1,53 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetchnewsletters
+
+import (
+	"context"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/newsletterfetcher"
+)
+
+// CmdFetchNewsletters implements the command "whatsnew fetch-newsletters".
+var CmdFetchNewsletters = &command.Command{
+	Name:      "fetch-newsletters",
+	UsageLine: "fetch-newsletters",
+	Short:     "run the worker to read newsletter sources and get new messages",
+	Long: `
+The "fetch-newsletters" command runs the worker for reading the IMAP folders
+of newsletter sources and processing their new messages.
+`,
+	Run: Run,
+}
+
+// Run runs the command "whatsnew fetch-newsletters".
+func Run(_ context.Context, conf *config.Config, args []string) (err error) {
+	if len(args) != 0 {
+		return command.ErrInvalidArguments
+	}
+
+	db, err := database.OpenDB(conf.DB)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := database.CloseDB(db); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	fk, err := workers.NewManager(conf.Faktory)
+	if err != nil {
+		return err
+	}
+
+	nf := newsletterfetcher.New(conf.Workers.NewsletterFetcher, db, fk)
+	nf.Run()
+
+	return nil
+}
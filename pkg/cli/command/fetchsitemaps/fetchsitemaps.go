@@ -0,0 +1,53 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetchsitemaps
+
+import (
+	"context"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/sitemapfetcher"
+)
+
+// CmdFetchSitemaps implements the command "whatsnew fetch-sitemaps".
+var CmdFetchSitemaps = &command.Command{
+	Name:      "fetch-sitemaps",
+	UsageLine: "fetch-sitemaps",
+	Short:     "run the worker to fetch sitemaps and get new sitemap items",
+	Long: `
+The "fetch-sitemaps" command runs the worker for fetching sitemaps and getting
+new sitemap items.
+`,
+	Run: Run,
+}
+
+// Run runs the command "whatsnew fetch-sitemaps".
+func Run(_ context.Context, conf *config.Config, args []string) (err error) {
+	if len(args) != 0 {
+		return command.ErrInvalidArguments
+	}
+
+	db, err := database.OpenDB(conf.DB)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := database.CloseDB(db); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	fk, err := workers.NewManager(conf.Faktory)
+	if err != nil {
+		return err
+	}
+
+	sf := sitemapfetcher.New(conf.Workers.SitemapFetcher, db, fk)
+	sf.Run()
+
+	return nil
+}
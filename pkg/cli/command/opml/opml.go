@@ -0,0 +1,168 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opml
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/opml"
+	"gorm.io/gorm"
+	"io"
+	"os"
+	"time"
+)
+
+// CmdOPML implements the command "whatsnew opml".
+var CmdOPML = &command.Command{
+	Name:      "opml",
+	UsageLine: "opml (import <file> | export [-enabled] [-disabled] [<file>])",
+	Short:     "import or export the feeds as OPML",
+	Long: `
+The "opml" command imports or exports the Feeds from or to an OPML document.
+
+You must specify a sub-command, which identifies the specific operation to
+perform. The supported operations are:
+
+	import <file>
+		Read the OPML document from the given file ("-" for the standard
+		input), and create a new enabled Feed for each outline whose feed URL
+		is not yet in the database. The texts of the enclosing outlines
+		(folders) and the categories of each outline become the tags of
+		the new Feed. The feeds which already exist are reported as
+		duplicates, and otherwise ignored.
+
+	export [-enabled] [-disabled] [<file>]
+		Write an OPML 2.0 document of the enabled Feeds, the disabled
+		Feeds, or both, to the given file, or to the standard output.
+		When neither -enabled nor -disabled is specified, all Feeds are
+		exported.
+`,
+	Run: Run,
+}
+
+// Run runs the command "whatsnew opml".
+func Run(ctx context.Context, conf *config.Config, args []string) error {
+	if len(args) == 0 {
+		return command.ErrInvalidArguments
+	}
+	fn, ok := operations[args[0]]
+	if !ok {
+		return command.ErrInvalidArguments
+	}
+	return fn(ctx, conf, args[1:])
+}
+
+type opFn func(ctx context.Context, conf *config.Config, args []string) error
+
+var operations = map[string]opFn{
+	"import": runImport,
+	"export": runExport,
+}
+
+func runImport(ctx context.Context, conf *config.Config, args []string) (err error) {
+	if len(args) != 1 {
+		return command.ErrInvalidArguments
+	}
+
+	var r io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if e := f.Close(); e != nil && err == nil {
+				err = e
+			}
+		}()
+		r = f
+	}
+
+	doc, err := opml.Parse(r)
+	if err != nil {
+		return err
+	}
+
+	return withDB(conf, func(db *gorm.DB) error {
+		var imported []opml.ImportedFeed
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+			imported, err = opml.Import(tx, doc.Feeds())
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		created := 0
+		for _, f := range imported {
+			if f.Duplicate {
+				fmt.Printf("duplicate %s\n", f.URL)
+				continue
+			}
+			created++
+			fmt.Printf("created   %s (ID %d)\n", f.URL, f.FeedID)
+		}
+		fmt.Printf("%d feeds created, %d duplicates\n", created, len(imported)-created)
+		return nil
+	})
+}
+
+func runExport(ctx context.Context, conf *config.Config, args []string) (err error) {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	enabled := fs.Bool("enabled", false, "")
+	disabled := fs.Bool("disabled", false, "")
+	if err := fs.Parse(args); err != nil {
+		return command.InvalidArguments(err.Error())
+	}
+	if fs.NArg() > 1 {
+		return command.ErrInvalidArguments
+	}
+	if !*enabled && !*disabled {
+		*enabled, *disabled = true, true
+	}
+
+	var feeds []opml.Feed
+	err = withDB(conf, func(db *gorm.DB) (err error) {
+		feeds, err = opml.Export(db.WithContext(ctx), *enabled, *disabled)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if fs.NArg() == 1 {
+		f, err := os.Create(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if e := f.Close(); e != nil && err == nil {
+				err = e
+			}
+		}()
+		w = f
+	}
+
+	return opml.NewDocument("WhatsNew Feeds", feeds, time.Now()).Write(w)
+}
+
+func withDB(conf *config.Config, fn func(db *gorm.DB) error) (err error) {
+	db, err := database.OpenDB(conf.DB)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := database.CloseDB(db); e != nil && err == nil {
+			err = e
+		}
+	}()
+	return fn(db)
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package retryscrapes
+
+import (
+	"context"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/tasks/scraperetrier"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers"
+)
+
+// CmdRetryScrapes implements the command "whatsnew retry-scrapes".
+var CmdRetryScrapes = &command.Command{
+	Name:      "retry-scrapes",
+	UsageLine: "retry-scrapes",
+	Short:     "periodically reschedule the web resources whose scraping failed",
+	Long: `
+The command "retry-scrapes" starts a process which periodically reschedules
+the scraping of the WebResources which failed for a transient reason, with
+an exponential backoff.
+`,
+	Run: Run,
+}
+
+// Run runs the command "whatsnew retry-scrapes".
+func Run(ctx context.Context, conf *config.Config, args []string) (err error) {
+	if len(args) != 0 {
+		return command.ErrInvalidArguments
+	}
+
+	db, err := database.OpenDB(conf.DB)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := database.CloseDB(db); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	fk, err := workers.NewClient(conf.Faktory)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := fk.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	sr := scraperetrier.New(conf.Tasks.ScrapeRetrier, db, fk)
+	return sr.Run(ctx)
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scrapefailures
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/scrapestats"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// CmdScrapeFailures implements the command "whatsnew scrape-failures".
+var CmdScrapeFailures = &command.Command{
+	Name:      "scrape-failures",
+	UsageLine: "scrape-failures [-status <status>] [-domain <domain>] [-since <duration>] [-first <n>]",
+	Short:     "show the unsuccessful scrapings by domain and reason",
+	Long: `
+The command "scrape-failures" prints the number of WebResources whose
+scraping was unsuccessful, grouped by domain, scrape status, reason and HTTP
+status code, in decreasing order of count.
+
+The flags are:
+
+	-status <status>
+		Only count the WebResources with this scrape status ("skipped",
+		"blocked", "failed" or "retrying"). By default, all of them.
+
+	-domain <domain>
+		Only count the WebResources of this domain, including its
+		subdomains.
+
+	-since <duration>
+		Only count the WebResources whose last scraping attempt happened
+		within this duration (e.g. "24h").
+
+	-first <n>
+		Print at most n rows.
+`,
+	Run: Run,
+}
+
+// Run runs the command "whatsnew scrape-failures".
+func Run(ctx context.Context, conf *config.Config, args []string) (err error) {
+	fs := flag.NewFlagSet("scrape-failures", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	status := fs.String("status", "", "")
+	domain := fs.String("domain", "", "")
+	since := fs.Duration("since", 0, "")
+	first := fs.Int("first", 0, "")
+	if err := fs.Parse(args); err != nil {
+		return command.InvalidArguments(err.Error())
+	}
+	if fs.NArg() != 0 {
+		return command.ErrInvalidArguments
+	}
+
+	filter := scrapestats.Filter{
+		Status: models.ScrapeStatus(*status),
+		Domain: *domain,
+		Limit:  *first,
+	}
+	if *since > 0 {
+		filter.Since = time.Now().Add(-*since)
+	}
+
+	db, err := database.OpenDB(conf.DB)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := database.CloseDB(db); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	failures, err := scrapestats.Failures(db.WithContext(ctx), filter)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DOMAIN\tSTATUS\tREASON\tHTTP\tCOUNT\tLAST ATTEMPT")
+	for _, f := range failures {
+		httpStatus, lastAttempt := "-", "-"
+		if f.HTTPStatus != 0 {
+			httpStatus = fmt.Sprintf("%d", f.HTTPStatus)
+		}
+		if f.LastAttemptAt.Valid {
+			lastAttempt = f.LastAttemptAt.Time.Format(time.RFC3339)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n",
+			f.Domain, f.Status, f.Reason, httpStatus, f.Count, lastAttempt)
+	}
+	return w.Flush()
+}
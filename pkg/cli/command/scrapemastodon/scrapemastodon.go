@@ -0,0 +1,53 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scrapemastodon
+
+import (
+	"context"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/cli/command"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/mastodonscraper"
+)
+
+// CmdScrapeMastodon implements the command "whatsnew scrape-mastodon".
+var CmdScrapeMastodon = &command.Command{
+	Name:      "scrape-mastodon",
+	UsageLine: "scrape-mastodon",
+	Short:     "scrape Mastodon posts from account or hashtag sources",
+	Long: `
+The command "scrape-mastodon" runs the worker for fetching Mastodon posts from
+specific Mastodon sources.
+`,
+	Run: Run,
+}
+
+// Run runs the command "whatsnew scrape-mastodon".
+func Run(_ context.Context, conf *config.Config, args []string) error {
+	if len(args) != 0 {
+		return command.ErrInvalidArguments
+	}
+
+	db, err := database.OpenDB(conf.DB)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := database.CloseDB(db); e != nil && err == nil {
+			err = e
+		}
+	}()
+
+	fk, err := workers.NewManager(conf.Faktory)
+	if err != nil {
+		return err
+	}
+
+	ms := mastodonscraper.New(conf.Workers.MastodonScraper, db, fk)
+	ms.Run()
+
+	return nil
+}
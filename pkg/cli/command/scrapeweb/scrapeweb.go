@@ -46,7 +46,10 @@ func Run(_ context.Context, conf *config.Config, args []string) error {
 		return err
 	}
 
-	ws := webscraper.New(conf.Workers.WebScraper, db, fk)
+	ws, err := webscraper.New(conf.Workers.WebScraper, db, fk)
+	if err != nil {
+		return err
+	}
 	ws.Run()
 
 	return nil
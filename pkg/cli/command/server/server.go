@@ -40,6 +40,6 @@ func Run(ctx context.Context, conf *config.Config, args []string) error {
 		}
 	}()
 
-	s := server.New(conf.Server, db)
+	s := server.New(conf.Server, conf.HNSW, db)
 	return s.Run(ctx)
 }
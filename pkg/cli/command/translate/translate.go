@@ -46,8 +46,11 @@ func Run(_ context.Context, conf *config.Config, args []string) error {
 		return err
 	}
 
-	zsc := translator.New(conf.Workers.Translator, db, fk)
-	zsc.Run()
+	t, err := translator.New(conf.Workers.Translator, db, fk)
+	if err != nil {
+		return err
+	}
+	t.Run()
 
 	return nil
 }
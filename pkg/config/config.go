@@ -58,14 +58,56 @@ type HNSWIndex struct {
 
 // FeedScheduler holds settings for scheduling feeds for further processing.
 type FeedScheduler struct {
+	TimeInterval    time.Duration   `yaml:"time_interval"`
+	AdaptivePolling AdaptivePolling `yaml:"adaptive_polling"`
+	Jobs            []FaktoryJob    `yaml:"jobs"`
+	LogLevel        LogLevel        `yaml:"loglevel"`
+}
+
+// AdaptivePolling is part of FeedScheduler settings. When enabled, the
+// scheduling interval of each Feed without an explicit interval is adapted
+// to its observed publication rate, within the given bounds.
+type AdaptivePolling struct {
+	Enabled     bool          `yaml:"enabled"`
+	MinInterval time.Duration `yaml:"min_interval"`
+	MaxInterval time.Duration `yaml:"max_interval"`
+}
+
+// TwitterScheduler holds settings for scheduling twitter sources for further
+// processing.
+type TwitterScheduler struct {
 	TimeInterval time.Duration `yaml:"time_interval"`
 	Jobs         []FaktoryJob  `yaml:"jobs"`
 	LogLevel     LogLevel      `yaml:"loglevel"`
 }
 
-// TwitterScheduler holds settings for scheduling twitter sources for further
+// MastodonScheduler holds settings for scheduling mastodon sources for
+// further processing.
+type MastodonScheduler struct {
+	TimeInterval time.Duration `yaml:"time_interval"`
+	Jobs         []FaktoryJob  `yaml:"jobs"`
+	LogLevel     LogLevel      `yaml:"loglevel"`
+}
+
+// SitemapScheduler holds settings for scheduling sitemaps for further
 // processing.
-type TwitterScheduler struct {
+type SitemapScheduler struct {
+	TimeInterval time.Duration `yaml:"time_interval"`
+	Jobs         []FaktoryJob  `yaml:"jobs"`
+	LogLevel     LogLevel      `yaml:"loglevel"`
+}
+
+// APIScheduler holds settings for scheduling API sources for further
+// processing.
+type APIScheduler struct {
+	TimeInterval time.Duration `yaml:"time_interval"`
+	Jobs         []FaktoryJob  `yaml:"jobs"`
+	LogLevel     LogLevel      `yaml:"loglevel"`
+}
+
+// NewsletterScheduler holds settings for the periodic scheduling of jobs
+// for processing all enabled newsletter sources.
+type NewsletterScheduler struct {
 	TimeInterval time.Duration `yaml:"time_interval"`
 	Jobs         []FaktoryJob  `yaml:"jobs"`
 	LogLevel     LogLevel      `yaml:"loglevel"`
@@ -77,7 +119,32 @@ type GDELTFetcher struct {
 	TimeInterval           time.Duration `yaml:"time_interval"`
 	EventRootCodeWhitelist []string      `yaml:"event_root_code_whitelist"`
 	NewWebResourceJobs     []FaktoryJob  `yaml:"new_web_resource_jobs"`
-	LogLevel               LogLevel      `yaml:"loglevel"`
+	// MaxCatchUp limits how far back in time the missed GDELT updates are
+	// processed, when the fetcher starts. Zero means no limits.
+	MaxCatchUp     time.Duration `yaml:"max_catch_up"`
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	LogLevel       LogLevel      `yaml:"loglevel"`
+}
+
+// GDELTGKGFetcher holds settings for fetching the GDELT Global Knowledge
+// Graph and extracting the source document URLs for further processing.
+type GDELTGKGFetcher struct {
+	TimeInterval time.Duration `yaml:"time_interval"`
+	// BaseURL is the URL of the GDELT 2.0 data files, or the path of a local
+	// directory containing them.
+	BaseURL string `yaml:"base_url"`
+	// ThemeWhitelist, if not empty, only allows the records with at least
+	// one of these themes. A value ending with "*" matches a theme prefix.
+	ThemeWhitelist []string `yaml:"theme_whitelist"`
+	// ThemeBlacklist excludes the records with any of these themes, with
+	// the same syntax of ThemeWhitelist.
+	ThemeBlacklist     []string      `yaml:"theme_blacklist"`
+	MinTone            float64       `yaml:"min_tone"`
+	MaxTone            float64       `yaml:"max_tone"`
+	NewWebResourceJobs []FaktoryJob  `yaml:"new_web_resource_jobs"`
+	MaxCatchUp         time.Duration `yaml:"max_catch_up"`
+	RequestTimeout     time.Duration `yaml:"request_timeout"`
+	LogLevel           LogLevel      `yaml:"loglevel"`
 }
 
 // JobsRecoverer holds settings for the periodic recovery process of
@@ -88,6 +155,24 @@ type JobsRecoverer struct {
 	LogLevel     LogLevel      `yaml:"loglevel"`
 }
 
+// ScrapeRetrier holds settings for the periodic rescheduling of the
+// WebResources whose scraping failed for a transient reason.
+type ScrapeRetrier struct {
+	TimeInterval time.Duration `yaml:"time_interval"`
+	// MaxAttempts is the maximum number of scraping attempts of a
+	// WebResource, including the first one.
+	MaxAttempts int `yaml:"max_attempts"`
+	// InitialBackoff is the delay after the first failed attempt, which is
+	// doubled after each further attempt, up to MaxBackoff.
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	MaxBackoff     time.Duration `yaml:"max_backoff"`
+	// RetryingTimeout is the time after which a WebResource still marked
+	// as retrying is considered failed again, since its job was lost.
+	RetryingTimeout time.Duration `yaml:"retrying_timeout"`
+	Jobs            []FaktoryJob  `yaml:"jobs"`
+	LogLevel        LogLevel      `yaml:"loglevel"`
+}
+
 // HNSWPurger holds settings for the periodic deletion of old HNSW indices.
 type HNSWPurger struct {
 	TimeInterval               time.Duration `yaml:"time_interval"`
@@ -97,27 +182,49 @@ type HNSWPurger struct {
 
 // Server holds settings for the HTTP and gRPC server.
 type Server struct {
-	Address        string   `yaml:"address"`
-	TLSEnabled     bool     `yaml:"tls_enabled"`
-	TLSCert        string   `yaml:"tls_cert"`
-	TLSKey         string   `yaml:"tls_key"`
-	AllowedOrigins []string `yaml:"allowed_origins"`
-	LogLevel       LogLevel `yaml:"loglevel"`
+	Address         string        `yaml:"address"`
+	TLSEnabled      bool          `yaml:"tls_enabled"`
+	TLSCert         string        `yaml:"tls_cert"`
+	TLSKey          string        `yaml:"tls_key"`
+	AllowedOrigins  []string      `yaml:"allowed_origins"`
+	SpagoBERTServer GRPCServer    `yaml:"spago_bert_server"`
+	FeedDiscovery   FeedDiscovery `yaml:"feed_discovery"`
+	LogLevel        LogLevel      `yaml:"loglevel"`
+}
+
+// FeedDiscovery holds settings for discovering the feeds of a website, both
+// from the API and from the command line.
+type FeedDiscovery struct {
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	UserAgent      string        `yaml:"user_agent"`
+	// MaxCandidates is the maximum number of candidate feed URLs which are
+	// validated for each website.
+	MaxCandidates int `yaml:"max_candidates"`
 }
 
 // Tasks holds settings for various tasks.
 type Tasks struct {
-	FeedScheduler    FeedScheduler    `yaml:"feed_scheduler"`
-	TwitterScheduler TwitterScheduler `yaml:"twitter_scheduler"`
-	GDELTFetcher     GDELTFetcher     `yaml:"gdelt_fetcher"`
-	JobsRecoverer    JobsRecoverer    `yaml:"jobs_recoverer"`
-	HNSWPurger       HNSWPurger       `yaml:"hnsw_purger"`
+	FeedScheduler       FeedScheduler       `yaml:"feed_scheduler"`
+	TwitterScheduler    TwitterScheduler    `yaml:"twitter_scheduler"`
+	MastodonScheduler   MastodonScheduler   `yaml:"mastodon_scheduler"`
+	SitemapScheduler    SitemapScheduler    `yaml:"sitemap_scheduler"`
+	APIScheduler        APIScheduler        `yaml:"api_scheduler"`
+	NewsletterScheduler NewsletterScheduler `yaml:"newsletter_scheduler"`
+	GDELTFetcher        GDELTFetcher        `yaml:"gdelt_fetcher"`
+	GDELTGKGFetcher     GDELTGKGFetcher     `yaml:"gdelt_gkg_fetcher"`
+	JobsRecoverer       JobsRecoverer       `yaml:"jobs_recoverer"`
+	ScrapeRetrier       ScrapeRetrier       `yaml:"scrape_retrier"`
+	HNSWPurger          HNSWPurger          `yaml:"hnsw_purger"`
 }
 
 // Workers holds settings for the various workers.
 type Workers struct {
 	FeedFetcher          FeedFetcher          `yaml:"feed_fetcher"`
+	SitemapFetcher       SitemapFetcher       `yaml:"sitemap_fetcher"`
+	APIFetcher           APIFetcher           `yaml:"api_fetcher"`
 	TwitterScraper       TwitterScraper       `yaml:"twitter_scraper"`
+	MastodonScraper      MastodonScraper      `yaml:"mastodon_scraper"`
+	NewsletterFetcher    NewsletterFetcher    `yaml:"newsletter_fetcher"`
 	WebScraper           WebScraper           `yaml:"web_scraper"`
 	Translator           Translator           `yaml:"translator"`
 	ZeroShotClassifier   ZeroShotClassifier   `yaml:"zero_shot_classifier"`
@@ -140,6 +247,35 @@ type FeedFetcher struct {
 	LogLevel                 LogLevel                 `yaml:"loglevel"`
 }
 
+// SitemapFetcher holds settings for the SitemapFetcher worker.
+type SitemapFetcher struct {
+	Queues                   []string                 `yaml:"queues"`
+	Concurrency              int                      `yaml:"concurrency"`
+	NewWebResourceJobs       []FaktoryJob             `yaml:"new_web_resource_jobs"`
+	MaxAllowedFailures       int                      `yaml:"max_allowed_failures"`
+	OmitItemsPublishedBefore OmitItemsPublishedBefore `yaml:"omit_items_published_before"`
+	LanguageFilter           []string                 `yaml:"language_filter"`
+	RequestTimeout           time.Duration            `yaml:"request_timeout"`
+	UserAgent                string                   `yaml:"user_agent"`
+	// MaxSitemaps is the maximum number of sitemaps visited from a sitemap
+	// index, most recently modified first.
+	MaxSitemaps int      `yaml:"max_sitemaps"`
+	LogLevel    LogLevel `yaml:"loglevel"`
+}
+
+// APIFetcher holds settings for the APIFetcher worker.
+type APIFetcher struct {
+	Queues                   []string                 `yaml:"queues"`
+	Concurrency              int                      `yaml:"concurrency"`
+	NewWebResourceJobs       []FaktoryJob             `yaml:"new_web_resource_jobs"`
+	MaxAllowedFailures       int                      `yaml:"max_allowed_failures"`
+	OmitItemsPublishedBefore OmitItemsPublishedBefore `yaml:"omit_items_published_before"`
+	LanguageFilter           []string                 `yaml:"language_filter"`
+	RequestTimeout           time.Duration            `yaml:"request_timeout"`
+	UserAgent                string                   `yaml:"user_agent"`
+	LogLevel                 LogLevel                 `yaml:"loglevel"`
+}
+
 // TwitterScraper holds settings for the TwitterScraper worker.
 type TwitterScraper struct {
 	Queues                    []string                 `yaml:"queues"`
@@ -148,7 +284,47 @@ type TwitterScraper struct {
 	NewWebArticleJobs         []FaktoryJob             `yaml:"new_web_article_jobs"`
 	OmitTweetsPublishedBefore OmitItemsPublishedBefore `yaml:"omit_tweets_published_before"`
 	LanguageFilter            []string                 `yaml:"language_filter"`
-	LogLevel                  LogLevel                 `yaml:"loglevel"`
+	// FollowLinks enables the creation of a new WebResource for each URL
+	// linked by a tweet, pushing NewWebResourceJobs.
+	FollowLinks        bool         `yaml:"follow_links"`
+	NewWebResourceJobs []FaktoryJob `yaml:"new_web_resource_jobs"`
+	LogLevel           LogLevel     `yaml:"loglevel"`
+}
+
+// MastodonScraper holds settings for the MastodonScraper worker.
+type MastodonScraper struct {
+	Queues                   []string                 `yaml:"queues"`
+	Concurrency              int                      `yaml:"concurrency"`
+	MaxPostsNumber           int                      `yaml:"max_posts_number"`
+	NewWebArticleJobs        []FaktoryJob             `yaml:"new_web_article_jobs"`
+	OmitPostsPublishedBefore OmitItemsPublishedBefore `yaml:"omit_posts_published_before"`
+	LanguageFilter           []string                 `yaml:"language_filter"`
+	// FollowLinks enables the creation of a new WebResource for each URL
+	// linked by a post, pushing NewWebResourceJobs.
+	FollowLinks        bool          `yaml:"follow_links"`
+	NewWebResourceJobs []FaktoryJob  `yaml:"new_web_resource_jobs"`
+	RequestTimeout     time.Duration `yaml:"request_timeout"`
+	UserAgent          string        `yaml:"user_agent"`
+	LogLevel           LogLevel      `yaml:"loglevel"`
+}
+
+// NewsletterFetcher holds settings for the NewsletterFetcher worker.
+type NewsletterFetcher struct {
+	Queues      []string `yaml:"queues"`
+	Concurrency int      `yaml:"concurrency"`
+	// MaxMessagesNumber is the maximum number of messages read from a
+	// source on each visit. The oldest unread messages are read first.
+	MaxMessagesNumber int `yaml:"max_messages_number"`
+	// NewWebArticleJobs are pushed for the messages of the sources in
+	// "article" mode, NewWebResourceJobs for the links found in the
+	// messages of the sources in "links" mode.
+	NewWebArticleJobs           []FaktoryJob             `yaml:"new_web_article_jobs"`
+	NewWebResourceJobs          []FaktoryJob             `yaml:"new_web_resource_jobs"`
+	MaxAllowedFailures          int                      `yaml:"max_allowed_failures"`
+	OmitMessagesPublishedBefore OmitItemsPublishedBefore `yaml:"omit_messages_published_before"`
+	LanguageFilter              []string                 `yaml:"language_filter"`
+	RequestTimeout              time.Duration            `yaml:"request_timeout"`
+	LogLevel                    LogLevel                 `yaml:"loglevel"`
 }
 
 // WebScraper holds settings for the WebScraper worker.
@@ -159,54 +335,127 @@ type WebScraper struct {
 	LanguageFilter    []string      `yaml:"language_filter"`
 	RequestTimeout    time.Duration `yaml:"request_timeout"`
 	UserAgent         string        `yaml:"user_agent"`
-	LogLevel          LogLevel      `yaml:"loglevel"`
+	// RespectRobotsTxt enables the rules of the robots.txt files, which
+	// are cached for RobotsTxtTTL.
+	RespectRobotsTxt bool          `yaml:"respect_robots_txt"`
+	RobotsTxtTTL     time.Duration `yaml:"robots_txt_ttl"`
+	// MinRequestInterval is the default minimum interval between two
+	// requests to the same host, shared by all the concurrent jobs.
+	MinRequestInterval time.Duration `yaml:"min_request_interval"`
+	// InsecureSkipVerify disables the verification of TLS certificates
+	// for all domains.
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+	// DomainPolicies override the settings above for specific domains. The
+	// policies stored in the database, which are reloaded every
+	// PoliciesRefreshInterval, take precedence over these ones.
+	DomainPolicies          []DomainPolicy `yaml:"domain_policies"`
+	PoliciesRefreshInterval time.Duration  `yaml:"policies_refresh_interval"`
+	// WallDetection recognizes the paywalls and consent walls served in
+	// place of the articles.
+	WallDetection WallDetection `yaml:"wall_detection"`
+	LogLevel      LogLevel      `yaml:"loglevel"`
+}
+
+// WallDetection holds the heuristics for recognizing the paywall and
+// consent-wall pages served in place of the articles.
+type WallDetection struct {
+	// PaywallTitlePatterns and ConsentWallTitlePatterns are regular
+	// expressions, matched case-insensitively against the page title.
+	PaywallTitlePatterns     []string `yaml:"paywall_title_patterns"`
+	ConsentWallTitlePatterns []string `yaml:"consent_wall_title_patterns"`
+	// PaywallSelectors and ConsentWallSelectors are CSS selectors of the
+	// page elements which mark a wall. They are only considered when the
+	// extracted body is shorter than MaxWallBodyLength characters (zero
+	// means no limit).
+	PaywallSelectors     []string `yaml:"paywall_selectors"`
+	ConsentWallSelectors []string `yaml:"consent_wall_selectors"`
+	MaxWallBodyLength    int      `yaml:"max_wall_body_length"`
+	// FeedItemFallback enables the creation of the article from the title
+	// and the description of the feed item, when a wall is detected.
+	FeedItemFallback bool `yaml:"feed_item_fallback"`
+}
+
+// DomainPolicy holds the rules to follow when scraping the web pages of a
+// domain, including all its subdomains.
+type DomainPolicy struct {
+	Domain string `yaml:"domain"`
+	// UserAgent, when not empty, replaces the default one.
+	UserAgent string            `yaml:"user_agent"`
+	Headers   map[string]string `yaml:"headers"`
+	Cookies   map[string]string `yaml:"cookies"`
+	ProxyURL  string            `yaml:"proxy_url"`
+	// MinRequestInterval, when not zero, replaces the default one.
+	MinRequestInterval time.Duration `yaml:"min_request_interval"`
+	// IgnoreRobotsTxt and InsecureSkipVerify, when set, replace the default
+	// settings (that is, the negated RespectRobotsTxt and the WebScraper's
+	// InsecureSkipVerify).
+	IgnoreRobotsTxt    *bool `yaml:"ignore_robots_txt"`
+	InsecureSkipVerify *bool `yaml:"insecure_skip_verify"`
 }
 
 // Translator holds settings for the translator worker.
 type Translator struct {
-	Queues                  []string     `yaml:"queues"`
-	Concurrency             int          `yaml:"concurrency"`
-	TranslatorServer        GRPCServer   `yaml:"translator_server"`
-	ProcessedWebArticleJobs []FaktoryJob `yaml:"processed_web_article_jobs"`
-	LanguageWhitelist       []string     `yaml:"language_whitelist"`
-	TargetLanguage          string       `yaml:"target_language"`
-	LogLevel                LogLevel     `yaml:"loglevel"`
+	Queues        []string      `yaml:"queues"`
+	Concurrency   int           `yaml:"concurrency"`
+	TextSelection TextSelection `yaml:"text_selection"`
+	// Providers are the available translation services.
+	Providers []TranslationProvider `yaml:"providers"`
+	// LanguagePairs associate each pair of source and target languages
+	// to one of the Providers.
+	LanguagePairs           []TranslationLanguagePair `yaml:"language_pairs"`
+	ProcessedWebArticleJobs []FaktoryJob              `yaml:"processed_web_article_jobs"`
+	LanguageWhitelist       []string                  `yaml:"language_whitelist"`
+	// TargetLanguages are the languages each WebArticle is translated into.
+	// The translated title of the WebArticle itself is always the one in
+	// the first language.
+	TargetLanguages []string `yaml:"target_languages"`
+	// Fields are further text sources to be translated, in addition to the
+	// one chosen with TextSelection.
+	Fields []TextSource `yaml:"fields"`
+	// MaxSegmentLength is the maximum length (in characters) of each
+	// group of sentences sent to the translation service.
+	MaxSegmentLength int      `yaml:"max_segment_length"`
+	LogLevel         LogLevel `yaml:"loglevel"`
 }
 
 // ZeroShotClassifier holds settings for the zero-shot classifier worker.
 type ZeroShotClassifier struct {
-	Queues                  []string     `yaml:"queues"`
-	Concurrency             int          `yaml:"concurrency"`
-	ProcessedWebArticleJobs []FaktoryJob `yaml:"processed_web_article_jobs"`
-	SpagoBARTServer         GRPCServer   `yaml:"spago_bart_server"`
-	LogLevel                LogLevel     `yaml:"loglevel"`
+	Queues                  []string      `yaml:"queues"`
+	Concurrency             int           `yaml:"concurrency"`
+	TextSelection           TextSelection `yaml:"text_selection"`
+	ProcessedWebArticleJobs []FaktoryJob  `yaml:"processed_web_article_jobs"`
+	SpagoBARTServer         GRPCServer    `yaml:"spago_bart_server"`
+	LogLevel                LogLevel      `yaml:"loglevel"`
 }
 
 // TextClassifier holds settings for the text classifier worker.
 type TextClassifier struct {
-	Queues                  []string     `yaml:"queues"`
-	Concurrency             int          `yaml:"concurrency"`
-	ProcessedWebArticleJobs []FaktoryJob `yaml:"processed_web_article_jobs"`
-	ClassifierServer        GRPCServer   `yaml:"classifier_server"`
-	LogLevel                LogLevel     `yaml:"loglevel"`
+	Queues                  []string      `yaml:"queues"`
+	Concurrency             int           `yaml:"concurrency"`
+	TextSelection           TextSelection `yaml:"text_selection"`
+	ProcessedWebArticleJobs []FaktoryJob  `yaml:"processed_web_article_jobs"`
+	ClassifierServer        GRPCServer    `yaml:"classifier_server"`
+	LogLevel                LogLevel      `yaml:"loglevel"`
 }
 
 // GeoParser holds settings for the geo-parser worker.
 type GeoParser struct {
-	Queues                  []string     `yaml:"queues"`
-	Concurrency             int          `yaml:"concurrency"`
-	ProcessedWebArticleJobs []FaktoryJob `yaml:"processed_web_article_jobs"`
-	CliffURI                string       `yaml:"cliff_uri"`
-	LogLevel                LogLevel     `yaml:"loglevel"`
+	Queues                  []string      `yaml:"queues"`
+	Concurrency             int           `yaml:"concurrency"`
+	TextSelection           TextSelection `yaml:"text_selection"`
+	ProcessedWebArticleJobs []FaktoryJob  `yaml:"processed_web_article_jobs"`
+	CliffURI                string        `yaml:"cliff_uri"`
+	LogLevel                LogLevel      `yaml:"loglevel"`
 }
 
 // Vectorizer holds settings for the Vectorizer worker.
 type Vectorizer struct {
-	Queues                   []string     `yaml:"queues"`
-	Concurrency              int          `yaml:"concurrency"`
-	VectorizedWebArticleJobs []FaktoryJob `yaml:"vectorized_web_article_jobs"`
-	SpagoBERTServer          GRPCServer   `yaml:"spago_bert_server"`
-	LogLevel                 LogLevel     `yaml:"loglevel"`
+	Queues                   []string      `yaml:"queues"`
+	Concurrency              int           `yaml:"concurrency"`
+	TextSelection            TextSelection `yaml:"text_selection"`
+	VectorizedWebArticleJobs []FaktoryJob  `yaml:"vectorized_web_article_jobs"`
+	SpagoBERTServer          GRPCServer    `yaml:"spago_bert_server"`
+	LogLevel                 LogLevel      `yaml:"loglevel"`
 }
 
 // DuplicateDetector holds settings for the duplicate detector worker.
@@ -221,11 +470,81 @@ type DuplicateDetector struct {
 
 // InformationExtractor holds settings for the information extractor worker.
 type InformationExtractor struct {
-	Queues                  []string     `yaml:"queues"`
-	Concurrency             int          `yaml:"concurrency"`
-	SpagoBERTServer         GRPCServer   `yaml:"spago_bert_server"`
-	ProcessedWebArticleJobs []FaktoryJob `yaml:"processed_web_article_jobs"`
-	LogLevel                LogLevel     `yaml:"loglevel"`
+	Queues                  []string      `yaml:"queues"`
+	Concurrency             int           `yaml:"concurrency"`
+	TextSelection           TextSelection `yaml:"text_selection"`
+	SpagoBERTServer         GRPCServer    `yaml:"spago_bert_server"`
+	ProcessedWebArticleJobs []FaktoryJob  `yaml:"processed_web_article_jobs"`
+	LogLevel                LogLevel      `yaml:"loglevel"`
+}
+
+// TextSelection holds settings for selecting the text of a WebArticle
+// processed by a worker.
+type TextSelection struct {
+	// Sources are tried in order, until a non-empty text is found.
+	Sources []TextSource `yaml:"sources"`
+	// MaxLength is the maximum number of characters of the text, after
+	// which it is truncated. Zero means no limit.
+	MaxLength int `yaml:"max_length"`
+	// ChunkLength is the maximum number of characters of each chunk the
+	// text is split into. Zero means no chunking.
+	ChunkLength int `yaml:"chunk_length"`
+}
+
+// TranslationProvider is part of Translator settings, and describes a
+// translation service.
+type TranslationProvider struct {
+	// Name identifies the provider in TranslationLanguagePair and in the
+	// stored translations.
+	Name string                  `yaml:"name"`
+	Type TranslationProviderType `yaml:"type"`
+	// GRPCServer is only used by the GRPCTranslationProvider type.
+	GRPCServer GRPCServer `yaml:"grpc_server"`
+	// HTTPAPI is only used by the HTTPTranslationProvider type.
+	HTTPAPI TranslationHTTPAPI `yaml:"http_api"`
+}
+
+// TranslationProviderType is the type of a TranslationProvider.
+type TranslationProviderType string
+
+const (
+	// GRPCTranslationProvider is the SpecializedGeneralist translator gRPC
+	// service.
+	GRPCTranslationProvider TranslationProviderType = "grpc"
+	// HTTPTranslationProvider is a LibreTranslate-compatible HTTP API.
+	HTTPTranslationProvider TranslationProviderType = "http"
+	// IdentityTranslationProvider leaves the texts untranslated. It is
+	// mostly useful for testing.
+	IdentityTranslationProvider TranslationProviderType = "identity"
+)
+
+// UnmarshalText satisfies the encoding.TextUnmarshaler interface, unmarshaling
+// the text to a TranslationProviderType.
+func (t *TranslationProviderType) UnmarshalText(text []byte) error {
+	s := TranslationProviderType(text)
+	switch s {
+	case GRPCTranslationProvider, HTTPTranslationProvider, IdentityTranslationProvider:
+		*t = s
+		return nil
+	default:
+		return fmt.Errorf("invalid translation provider type: %#v", string(text))
+	}
+}
+
+// TranslationHTTPAPI holds settings for a LibreTranslate-compatible HTTP
+// translation API.
+type TranslationHTTPAPI struct {
+	URL            string        `yaml:"url"`
+	APIKey         string        `yaml:"api_key"`
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+}
+
+// TranslationLanguagePair is part of Translator settings. The language "*"
+// matches any language.
+type TranslationLanguagePair struct {
+	SourceLanguage string `yaml:"source_language"`
+	TargetLanguage string `yaml:"target_language"`
+	Provider       string `yaml:"provider"`
 }
 
 // OmitItemsPublishedBefore is part of FeedFetcher settings.
@@ -287,6 +606,49 @@ func (l *LogLevel) UnmarshalText(text []byte) (err error) {
 	return nil
 }
 
+// TextSource identifies a text of a WebArticle, among the ones which can
+// be selected with TextSelection.
+type TextSource string
+
+const (
+	// TitleTextSource is the original title of the WebArticle.
+	TitleTextSource TextSource = "title"
+	// TranslatedTitleTextSource is the translated title of the WebArticle.
+	TranslatedTitleTextSource TextSource = "translated_title"
+	// TitleAndDescriptionTextSource is the original title of the WebArticle,
+	// followed by the description from the HTML meta tags.
+	TitleAndDescriptionTextSource TextSource = "title_and_description"
+	// DescriptionTextSource is the description of the WebArticle from the
+	// HTML meta tags.
+	DescriptionTextSource TextSource = "description"
+	// FeedDescriptionTextSource is the description of the feed item the
+	// WebArticle comes from.
+	FeedDescriptionTextSource TextSource = "feed_description"
+	// FeedContentTextSource is the content of the feed item the WebArticle
+	// comes from.
+	FeedContentTextSource TextSource = "feed_content"
+	// TweetTextSource is the text of the tweet the WebArticle comes from.
+	TweetTextSource TextSource = "tweet_text"
+	// BodyTextSource is the full text of the WebArticle, as extracted by
+	// the web scraper.
+	BodyTextSource TextSource = "body"
+)
+
+// UnmarshalText satisfies the encoding.TextUnmarshaler interface, unmarshaling
+// the text to a TextSource.
+func (ts *TextSource) UnmarshalText(text []byte) error {
+	s := TextSource(text)
+	switch s {
+	case TitleTextSource, TranslatedTitleTextSource, TitleAndDescriptionTextSource,
+		DescriptionTextSource, FeedDescriptionTextSource, FeedContentTextSource,
+		TweetTextSource, BodyTextSource:
+		*ts = s
+		return nil
+	default:
+		return fmt.Errorf("invalid text source: %#v", string(text))
+	}
+}
+
 // HNSWSpaceType is a redefinition of HNSW gRPC API CreateIndexRequest_SpaceType
 // which satisfies encoding.TextUnmarshaler, to be conveniently parsed from YAML.
 type HNSWSpaceType hnswgrpcapi.CreateIndexRequest_SpaceType
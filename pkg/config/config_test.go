@@ -86,11 +86,25 @@ func TestFromYAMLFile(t *testing.T) {
 				TLSCert:        "",
 				TLSKey:         "",
 				AllowedOrigins: []string{"*"},
-				LogLevel:       config.LogLevel(zerolog.InfoLevel),
+				SpagoBERTServer: config.GRPCServer{
+					Target:     "127.0.0.1:1976",
+					TLSEnabled: false,
+				},
+				FeedDiscovery: config.FeedDiscovery{
+					RequestTimeout: 10 * time.Second,
+					UserAgent:      "WhatsNew/1.0.0-beta.3",
+					MaxCandidates:  10,
+				},
+				LogLevel: config.LogLevel(zerolog.InfoLevel),
 			},
 			Tasks: config.Tasks{
 				FeedScheduler: config.FeedScheduler{
 					TimeInterval: 5 * time.Minute,
+					AdaptivePolling: config.AdaptivePolling{
+						Enabled:     true,
+						MinInterval: 2 * time.Minute,
+						MaxInterval: 6 * time.Hour,
+					},
 					Jobs: []config.FaktoryJob{
 						{
 							JobType:    "FeedFetcher",
@@ -113,6 +127,54 @@ func TestFromYAMLFile(t *testing.T) {
 					},
 					LogLevel: config.LogLevel(zerolog.InfoLevel),
 				},
+				MastodonScheduler: config.MastodonScheduler{
+					TimeInterval: 5 * time.Minute,
+					Jobs: []config.FaktoryJob{
+						{
+							JobType:    "MastodonScraper",
+							Queue:      "mastodon_scraper",
+							ReserveFor: 300,
+							Retry:      -1,
+						},
+					},
+					LogLevel: config.LogLevel(zerolog.InfoLevel),
+				},
+				SitemapScheduler: config.SitemapScheduler{
+					TimeInterval: 15 * time.Minute,
+					Jobs: []config.FaktoryJob{
+						{
+							JobType:    "SitemapFetcher",
+							Queue:      "sitemap_fetcher",
+							ReserveFor: 600,
+							Retry:      -1,
+						},
+					},
+					LogLevel: config.LogLevel(zerolog.InfoLevel),
+				},
+				APIScheduler: config.APIScheduler{
+					TimeInterval: 15 * time.Minute,
+					Jobs: []config.FaktoryJob{
+						{
+							JobType:    "APIFetcher",
+							Queue:      "api_fetcher",
+							ReserveFor: 600,
+							Retry:      -1,
+						},
+					},
+					LogLevel: config.LogLevel(zerolog.InfoLevel),
+				},
+				NewsletterScheduler: config.NewsletterScheduler{
+					TimeInterval: 15 * time.Minute,
+					Jobs: []config.FaktoryJob{
+						{
+							JobType:    "NewsletterFetcher",
+							Queue:      "newsletter_fetcher",
+							ReserveFor: 600,
+							Retry:      -1,
+						},
+					},
+					LogLevel: config.LogLevel(zerolog.InfoLevel),
+				},
 				GDELTFetcher: config.GDELTFetcher{
 					TimeInterval:           5 * time.Minute,
 					EventRootCodeWhitelist: make([]string, 0),
@@ -124,13 +186,50 @@ func TestFromYAMLFile(t *testing.T) {
 							Retry:      5,
 						},
 					},
-					LogLevel: config.LogLevel(zerolog.InfoLevel),
+					MaxCatchUp:     24 * time.Hour,
+					RequestTimeout: 60 * time.Second,
+					LogLevel:       config.LogLevel(zerolog.InfoLevel),
+				},
+				GDELTGKGFetcher: config.GDELTGKGFetcher{
+					TimeInterval:   5 * time.Minute,
+					BaseURL:        "http://data.gdeltproject.org/gdeltv2/",
+					ThemeWhitelist: make([]string, 0),
+					ThemeBlacklist: make([]string, 0),
+					MinTone:        -100,
+					MaxTone:        100,
+					NewWebResourceJobs: []config.FaktoryJob{
+						{
+							JobType:    "WebScraper",
+							Queue:      "web_scraper",
+							ReserveFor: 600,
+							Retry:      5,
+						},
+					},
+					MaxCatchUp:     24 * time.Hour,
+					RequestTimeout: 60 * time.Second,
+					LogLevel:       config.LogLevel(zerolog.InfoLevel),
 				},
 				JobsRecoverer: config.JobsRecoverer{
 					TimeInterval: time.Minute,
 					LeewayTime:   time.Minute,
 					LogLevel:     config.LogLevel(zerolog.InfoLevel),
 				},
+				ScrapeRetrier: config.ScrapeRetrier{
+					TimeInterval:    5 * time.Minute,
+					MaxAttempts:     5,
+					InitialBackoff:  10 * time.Minute,
+					MaxBackoff:      12 * time.Hour,
+					RetryingTimeout: 6 * time.Hour,
+					Jobs: []config.FaktoryJob{
+						{
+							JobType:    "WebScraper",
+							Queue:      "web_scraper",
+							ReserveFor: 600,
+							Retry:      0,
+						},
+					},
+					LogLevel: config.LogLevel(zerolog.InfoLevel),
+				},
 				HNSWPurger: config.HNSWPurger{
 					TimeInterval:               1 * time.Hour,
 					DeleteIndicesOlderThanDays: 6,
@@ -158,6 +257,49 @@ func TestFromYAMLFile(t *testing.T) {
 					RequestTimeout: 10 * time.Second,
 					LogLevel:       config.LogLevel(zerolog.InfoLevel),
 				},
+				SitemapFetcher: config.SitemapFetcher{
+					Queues:      []string{"sitemap_fetcher"},
+					Concurrency: 4,
+					NewWebResourceJobs: []config.FaktoryJob{
+						{
+							JobType:    "WebScraper",
+							Queue:      "web_scraper",
+							ReserveFor: 600,
+							Retry:      5,
+						},
+					},
+					MaxAllowedFailures: 15,
+					OmitItemsPublishedBefore: config.OmitItemsPublishedBefore{
+						Enabled: true,
+						Time:    time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+					},
+					LanguageFilter: []string{"en", "es", "fr", "it"},
+					RequestTimeout: 30 * time.Second,
+					UserAgent:      "WhatsNew/1.0.0-beta.3",
+					MaxSitemaps:    10,
+					LogLevel:       config.LogLevel(zerolog.InfoLevel),
+				},
+				APIFetcher: config.APIFetcher{
+					Queues:      []string{"api_fetcher"},
+					Concurrency: 4,
+					NewWebResourceJobs: []config.FaktoryJob{
+						{
+							JobType:    "WebScraper",
+							Queue:      "web_scraper",
+							ReserveFor: 600,
+							Retry:      5,
+						},
+					},
+					MaxAllowedFailures: 15,
+					OmitItemsPublishedBefore: config.OmitItemsPublishedBefore{
+						Enabled: true,
+						Time:    time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+					},
+					LanguageFilter: []string{"en", "es", "fr", "it"},
+					RequestTimeout: 30 * time.Second,
+					UserAgent:      "WhatsNew/1.0.0-beta.3",
+					LogLevel:       config.LogLevel(zerolog.InfoLevel),
+				},
 				TwitterScraper: config.TwitterScraper{
 					Queues:          []string{"twitter_scraper"},
 					Concurrency:     10,
@@ -175,11 +317,21 @@ func TestFromYAMLFile(t *testing.T) {
 						Time:    time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
 					},
 					LanguageFilter: []string{"en", "es", "fr", "it"},
-					LogLevel:       config.LogLevel(zerolog.InfoLevel),
+					FollowLinks:    false,
+					NewWebResourceJobs: []config.FaktoryJob{
+						{
+							JobType:    "WebScraper",
+							Queue:      "web_scraper",
+							ReserveFor: 600,
+							Retry:      5,
+						},
+					},
+					LogLevel: config.LogLevel(zerolog.InfoLevel),
 				},
-				WebScraper: config.WebScraper{
-					Queues:      []string{"web_scraper"},
-					Concurrency: 10,
+				MastodonScraper: config.MastodonScraper{
+					Queues:         []string{"mastodon_scraper"},
+					Concurrency:    10,
+					MaxPostsNumber: 200,
 					NewWebArticleJobs: []config.FaktoryJob{
 						{
 							JobType:    "Translator",
@@ -188,17 +340,134 @@ func TestFromYAMLFile(t *testing.T) {
 							Retry:      25,
 						},
 					},
+					OmitPostsPublishedBefore: config.OmitItemsPublishedBefore{
+						Enabled: true,
+						Time:    time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+					},
 					LanguageFilter: []string{"en", "es", "fr", "it"},
+					FollowLinks:    false,
+					NewWebResourceJobs: []config.FaktoryJob{
+						{
+							JobType:    "WebScraper",
+							Queue:      "web_scraper",
+							ReserveFor: 600,
+							Retry:      5,
+						},
+					},
 					RequestTimeout: 30 * time.Second,
 					UserAgent:      "WhatsNew/1.0.0-beta.3",
 					LogLevel:       config.LogLevel(zerolog.InfoLevel),
 				},
+				NewsletterFetcher: config.NewsletterFetcher{
+					Queues:            []string{"newsletter_fetcher"},
+					Concurrency:       4,
+					MaxMessagesNumber: 50,
+					NewWebArticleJobs: []config.FaktoryJob{
+						{
+							JobType:    "Translator",
+							Queue:      "translator",
+							ReserveFor: 600,
+							Retry:      25,
+						},
+					},
+					NewWebResourceJobs: []config.FaktoryJob{
+						{
+							JobType:    "WebScraper",
+							Queue:      "web_scraper",
+							ReserveFor: 600,
+							Retry:      5,
+						},
+					},
+					MaxAllowedFailures: 15,
+					OmitMessagesPublishedBefore: config.OmitItemsPublishedBefore{
+						Enabled: true,
+						Time:    time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+					},
+					LanguageFilter: []string{"en", "es", "fr", "it"},
+					RequestTimeout: 60 * time.Second,
+					LogLevel:       config.LogLevel(zerolog.InfoLevel),
+				},
+				WebScraper: config.WebScraper{
+					Queues:      []string{"web_scraper"},
+					Concurrency: 10,
+					NewWebArticleJobs: []config.FaktoryJob{
+						{
+							JobType:    "Translator",
+							Queue:      "translator",
+							ReserveFor: 600,
+							Retry:      25,
+						},
+					},
+					LanguageFilter:          []string{"en", "es", "fr", "it"},
+					RequestTimeout:          30 * time.Second,
+					UserAgent:               "WhatsNew/1.0.0-beta.3",
+					RespectRobotsTxt:        true,
+					RobotsTxtTTL:            24 * time.Hour,
+					MinRequestInterval:      time.Second,
+					InsecureSkipVerify:      false,
+					DomainPolicies:          []config.DomainPolicy{},
+					PoliciesRefreshInterval: time.Minute,
+					WallDetection: config.WallDetection{
+						PaywallTitlePatterns: []string{
+							"^subscribe (now )?to (continue|read)",
+							"^(log|sign) in to (continue|read)",
+							"^subscribers? only$",
+						},
+						ConsentWallTitlePatterns: []string{
+							"^before you continue",
+							"^cookie (consent|settings|preferences)$",
+							"^(we )?value your privacy$",
+						},
+						PaywallSelectors: []string{".paywall", "#paywall", "[data-paywall]", ".tp-modal"},
+						ConsentWallSelectors: []string{
+							`form[action*="consent."]`,
+							"#onetrust-consent-sdk",
+							"#didomi-host",
+							".fc-consent-root",
+							"#qc-cmp2-container",
+						},
+						MaxWallBodyLength: 500,
+						FeedItemFallback:  true,
+					},
+					LogLevel: config.LogLevel(zerolog.InfoLevel),
+				},
 				Translator: config.Translator{
 					Queues:      []string{"translator"},
 					Concurrency: 4,
-					TranslatorServer: config.GRPCServer{
-						Target:     "127.0.0.1:4557",
-						TLSEnabled: false,
+					TextSelection: config.TextSelection{
+						Sources:     []config.TextSource{config.TitleTextSource},
+						MaxLength:   0,
+						ChunkLength: 0,
+					},
+					Providers: []config.TranslationProvider{
+						{
+							Name: "translator",
+							Type: config.GRPCTranslationProvider,
+							GRPCServer: config.GRPCServer{
+								Target:     "127.0.0.1:4557",
+								TLSEnabled: false,
+							},
+						},
+						{
+							Name: "libretranslate",
+							Type: config.HTTPTranslationProvider,
+							HTTPAPI: config.TranslationHTTPAPI{
+								URL:            "http://127.0.0.1:5000/translate",
+								APIKey:         "",
+								RequestTimeout: 30 * time.Second,
+							},
+						},
+						{
+							Name: "identity",
+							Type: config.IdentityTranslationProvider,
+						},
+					},
+					LanguagePairs: []config.TranslationLanguagePair{
+						{
+							SourceLanguage: "*",
+							TargetLanguage: "*",
+							Provider:       "translator",
+						},
 					},
 					ProcessedWebArticleJobs: []config.FaktoryJob{
 						{
@@ -209,12 +478,23 @@ func TestFromYAMLFile(t *testing.T) {
 						},
 					},
 					LanguageWhitelist: []string{"fr", "it"},
-					TargetLanguage:    "en",
-					LogLevel:          config.LogLevel(zerolog.InfoLevel),
+					TargetLanguages:   []string{"en"},
+					Fields: []config.TextSource{
+						config.DescriptionTextSource,
+						config.FeedDescriptionTextSource,
+						config.BodyTextSource,
+					},
+					MaxSegmentLength: 500,
+					LogLevel:         config.LogLevel(zerolog.InfoLevel),
 				},
 				ZeroShotClassifier: config.ZeroShotClassifier{
 					Queues:      []string{"zero_shot_classifier"},
 					Concurrency: 4,
+					TextSelection: config.TextSelection{
+						Sources:     []config.TextSource{config.TranslatedTitleTextSource, config.TitleTextSource},
+						MaxLength:   0,
+						ChunkLength: 0,
+					},
 					ProcessedWebArticleJobs: []config.FaktoryJob{
 						{
 							JobType:    "TextClassifier",
@@ -232,6 +512,11 @@ func TestFromYAMLFile(t *testing.T) {
 				TextClassifier: config.TextClassifier{
 					Queues:      []string{"text_classifier"},
 					Concurrency: 4,
+					TextSelection: config.TextSelection{
+						Sources:     []config.TextSource{config.TranslatedTitleTextSource, config.TitleTextSource},
+						MaxLength:   0,
+						ChunkLength: 0,
+					},
 					ProcessedWebArticleJobs: []config.FaktoryJob{
 						{
 							JobType:    "GeoParser",
@@ -249,6 +534,11 @@ func TestFromYAMLFile(t *testing.T) {
 				GeoParser: config.GeoParser{
 					Queues:      []string{"geo_parser"},
 					Concurrency: 4,
+					TextSelection: config.TextSelection{
+						Sources:     []config.TextSource{config.TitleTextSource, config.TranslatedTitleTextSource},
+						MaxLength:   0,
+						ChunkLength: 0,
+					},
 					ProcessedWebArticleJobs: []config.FaktoryJob{
 						{
 							JobType:    "Vectorizer",
@@ -263,6 +553,11 @@ func TestFromYAMLFile(t *testing.T) {
 				Vectorizer: config.Vectorizer{
 					Queues:      []string{"vectorizer"},
 					Concurrency: 4,
+					TextSelection: config.TextSelection{
+						Sources:     []config.TextSource{config.TranslatedTitleTextSource, config.TitleTextSource},
+						MaxLength:   0,
+						ChunkLength: 0,
+					},
 					VectorizedWebArticleJobs: []config.FaktoryJob{
 						{
 							JobType:    "DuplicateDetector",
@@ -295,6 +590,11 @@ func TestFromYAMLFile(t *testing.T) {
 				InformationExtractor: config.InformationExtractor{
 					Queues:      []string{"information_extractor"},
 					Concurrency: 4,
+					TextSelection: config.TextSelection{
+						Sources:     []config.TextSource{config.TranslatedTitleTextSource, config.TitleTextSource},
+						MaxLength:   0,
+						ChunkLength: 0,
+					},
 					SpagoBERTServer: config.GRPCServer{
 						Target:     "127.0.0.1:5831",
 						TLSEnabled: false,
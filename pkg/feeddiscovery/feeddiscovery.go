@@ -0,0 +1,266 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package feeddiscovery finds the RSS, Atom and JSON feeds of a website.
+package feeddiscovery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/sets"
+	"github.com/mmcdole/gofeed"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxBodySize is the maximum number of bytes read from each response.
+const maxBodySize = 10 << 20
+
+// feedMIMETypes are the types of the alternate links which are considered
+// as feed candidates.
+var feedMIMETypes = sets.NewStringSetWithElements(
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/feed+json",
+)
+
+// commonPaths are the paths where the feeds are often found, tried when
+// none of the alternate links of a website is a valid feed.
+var commonPaths = []string{
+	"/feed",
+	"/rss",
+	"/feed.xml",
+	"/rss.xml",
+	"/atom.xml",
+	"/index.xml",
+	"/feed/",
+	"/rss/",
+	"/?feed=rss2",
+	"/feeds/posts/default",
+}
+
+// A Feed is a valid feed found on a website.
+type Feed struct {
+	URL   string
+	Title string
+	// Type is the feed type, as detected by gofeed ("rss", "atom" or "json").
+	Type string
+}
+
+// Discoverer finds the feeds of websites.
+type Discoverer struct {
+	conf   config.FeedDiscovery
+	client *http.Client
+	parser *gofeed.Parser
+}
+
+// New creates a new Discoverer.
+func New(conf config.FeedDiscovery) *Discoverer {
+	return &Discoverer{
+		conf:   conf,
+		client: &http.Client{Timeout: conf.RequestTimeout},
+		parser: gofeed.NewParser(),
+	}
+}
+
+// Discover fetches the web page at the given URL and returns the valid
+// feeds among the ones linked with <link rel="alternate"> tags or, if none
+// of them is valid, among the common feed paths of the website.
+//
+// If the URL already points to a feed, that feed alone is returned.
+func (d *Discoverer) Discover(ctx context.Context, siteURL string) ([]Feed, error) {
+	u, err := normalizeURL(siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	body, finalURL, err := d.get(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if feed, ok := d.parseFeed(finalURL.String(), body); ok {
+		return []Feed{feed}, nil
+	}
+
+	candidates, err := findAlternateLinks(body, finalURL)
+	if err != nil {
+		return nil, err
+	}
+	tried := sets.NewStringSet()
+	feeds, err := d.validateAll(ctx, candidates, tried)
+	if err != nil || len(feeds) > 0 {
+		return feeds, err
+	}
+	return d.validateAll(ctx, commonPathsURLs(finalURL), tried)
+}
+
+// validateAll returns the valid feeds among the candidate URLs, up to the
+// MaxCandidates limit, skipping the URLs already tried. The tried URLs are
+// added to the set.
+func (d *Discoverer) validateAll(ctx context.Context, candidates []string, tried sets.StringSet) ([]Feed, error) {
+	var untried []string
+	for _, candidate := range candidates {
+		if !tried.Has(candidate) {
+			untried = append(untried, candidate)
+		}
+	}
+	if d.conf.MaxCandidates > 0 && len(untried) > d.conf.MaxCandidates {
+		untried = untried[:d.conf.MaxCandidates]
+	}
+
+	feeds := make([]Feed, 0)
+	found := sets.NewStringSet()
+	for _, candidate := range untried {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		tried.Add(candidate)
+		feed, ok := d.validate(ctx, candidate)
+		if !ok || found.Has(feed.URL) {
+			continue
+		}
+		found.Add(feed.URL)
+		feeds = append(feeds, feed)
+	}
+	return feeds, nil
+}
+
+// validate fetches the candidate URL, and reports whether it is a valid feed.
+func (d *Discoverer) validate(ctx context.Context, candidate string) (Feed, bool) {
+	body, finalURL, err := d.get(ctx, candidate)
+	if err != nil {
+		return Feed{}, false
+	}
+	return d.parseFeed(finalURL.String(), body)
+}
+
+func (d *Discoverer) parseFeed(feedURL string, body []byte) (Feed, bool) {
+	parsed, err := d.parser.Parse(bytes.NewReader(body))
+	if err != nil {
+		return Feed{}, false
+	}
+	return Feed{
+		URL:   feedURL,
+		Title: strings.TrimSpace(parsed.Title),
+		Type:  parsed.FeedType,
+	}, true
+}
+
+// get performs an HTTP GET request, returning the response body and the
+// final URL, after any redirection.
+func (d *Discoverer) get(ctx context.Context, rawURL string) (_ []byte, _ *url.URL, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(d.conf.UserAgent) > 0 {
+		req.Header.Set("User-Agent", d.conf.UserAgent)
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if e := resp.Body.Close(); e != nil && err == nil {
+			err = fmt.Errorf("error closing response body: %w", e)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("GET %s: unexpected status %s", rawURL, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, nil, fmt.Errorf("GET %s: error reading response body: %w", rawURL, err)
+	}
+	return body, resp.Request.URL, nil
+}
+
+// findAlternateLinks returns the absolute URLs of the feeds linked from the
+// HTML page, in order of appearance.
+func findAlternateLinks(body []byte, pageURL *url.URL) ([]string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HTML: %w", err)
+	}
+
+	baseURL := pageURL
+	if href, ok := doc.Find("base[href]").First().Attr("href"); ok {
+		if u, err := pageURL.Parse(strings.TrimSpace(href)); err == nil {
+			baseURL = u
+		}
+	}
+
+	links := make([]string, 0)
+	seen := sets.NewStringSet()
+	doc.Find("link[rel][href]").Each(func(_ int, s *goquery.Selection) {
+		rel := strings.Fields(strings.ToLower(s.AttrOr("rel", "")))
+		if !containsString(rel, "alternate") {
+			return
+		}
+		mimeType := strings.ToLower(strings.TrimSpace(s.AttrOr("type", "")))
+		if !feedMIMETypes.Has(mimeType) {
+			return
+		}
+		u, err := baseURL.Parse(strings.TrimSpace(s.AttrOr("href", "")))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return
+		}
+		u.Fragment = ""
+		link := u.String()
+		if !seen.Has(link) {
+			seen.Add(link)
+			links = append(links, link)
+		}
+	})
+	return links, nil
+}
+
+func commonPathsURLs(siteURL *url.URL) []string {
+	urls := make([]string, len(commonPaths))
+	for i, path := range commonPaths {
+		u, _ := siteURL.Parse(path)
+		urls[i] = u.String()
+	}
+	return urls
+}
+
+// normalizeURL parses the URL, assuming the HTTPS scheme if missing.
+func normalizeURL(rawURL string) (*url.URL, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if len(rawURL) == 0 {
+		return nil, fmt.Errorf("the URL is empty")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %#v: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid URL %#v: unsupported scheme", rawURL)
+	}
+	if len(u.Host) == 0 {
+		return nil, fmt.Errorf("invalid URL %#v: missing host", rawURL)
+	}
+	return u, nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package feeddiscovery
+
+import (
+	"context"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testRSS = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>%s</title><link>https://example.com</link></channel></rss>`
+
+const testAtom = `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>%s</title></feed>`
+
+func TestDiscoverer_Discover(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/with-links", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><head>
+			<link rel="alternate" type="application/rss+xml" href="/news.rss">
+			<link rel="Alternate" type="application/atom+xml" href="https://FOREIGN/atom">
+			<link rel="alternate" type="application/atom+xml" href="atom.xml#foo">
+			<link rel="alternate" type="application/rss+xml" href="/broken.rss">
+			<link rel="alternate" type="text/html" href="/other">
+			<link rel="stylesheet" type="text/css" href="/style.css">
+		</head></html>`)
+	})
+	mux.HandleFunc("/with-broken-links", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><head>
+			<link rel="alternate" type="application/rss+xml" href="/broken.rss">
+			<link rel="alternate" type="application/json" href="/api/posts">
+		</head></html>`)
+	})
+	mux.HandleFunc("/without-links", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><head><title>Home</title></head></html>`)
+	})
+	mux.HandleFunc("/news.rss", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, testRSS, "News")
+	})
+	mux.HandleFunc("/atom.xml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, testAtom, "Atom")
+	})
+	mux.HandleFunc("/broken.rss", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, "not a feed")
+	})
+	mux.HandleFunc("/feed", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/feed.xml", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/feed.xml", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, testRSS, "Common")
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	d := New(config.FeedDiscovery{
+		RequestTimeout: 5 * time.Second,
+		UserAgent:      "test",
+	})
+	ctx := context.Background()
+
+	t.Run("alternate links", func(t *testing.T) {
+		t.Parallel()
+		feeds, err := d.Discover(ctx, server.URL+"/with-links")
+		require.NoError(t, err)
+		assert.Equal(t, []Feed{
+			{URL: server.URL + "/news.rss", Title: "News", Type: "rss"},
+			{URL: server.URL + "/atom.xml", Title: "Atom", Type: "atom"},
+		}, feeds)
+	})
+
+	t.Run("common paths", func(t *testing.T) {
+		t.Parallel()
+		feeds, err := d.Discover(ctx, server.URL+"/without-links")
+		require.NoError(t, err)
+		assert.Equal(t, []Feed{
+			{URL: server.URL + "/feed.xml", Title: "Common", Type: "rss"},
+			{URL: server.URL + "/atom.xml", Title: "Atom", Type: "atom"},
+		}, feeds)
+	})
+
+	t.Run("common paths after invalid links", func(t *testing.T) {
+		t.Parallel()
+		feeds, err := d.Discover(ctx, server.URL+"/with-broken-links")
+		require.NoError(t, err)
+		assert.Equal(t, []Feed{
+			{URL: server.URL + "/feed.xml", Title: "Common", Type: "rss"},
+			{URL: server.URL + "/atom.xml", Title: "Atom", Type: "atom"},
+		}, feeds)
+	})
+
+	t.Run("feed URL", func(t *testing.T) {
+		t.Parallel()
+		feeds, err := d.Discover(ctx, server.URL+"/news.rss")
+		require.NoError(t, err)
+		assert.Equal(t, []Feed{
+			{URL: server.URL + "/news.rss", Title: "News", Type: "rss"},
+		}, feeds)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		_, err := d.Discover(ctx, server.URL+"/missing")
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		t.Parallel()
+		_, err := d.Discover(ctx, "ftp://example.com")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package feeddiscovery
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// A StoredFeed is a discovered Feed, associated to its database record.
+type StoredFeed struct {
+	Feed
+	// FeedID is the ID of the models.Feed with the same URL.
+	FeedID uint
+	// Created reports whether the models.Feed was created, or it was
+	// already existing.
+	Created bool
+}
+
+// Store creates a new enabled models.Feed, with the given tags, for each
+// discovered Feed whose URL is not yet in the database.
+func Store(tx *gorm.DB, feeds []Feed, tags []string) ([]StoredFeed, error) {
+	stored := make([]StoredFeed, len(feeds))
+	for i, feed := range feeds {
+		id, created, err := storeFeed(tx, feed, tags)
+		if err != nil {
+			return nil, err
+		}
+		stored[i] = StoredFeed{Feed: feed, FeedID: id, Created: created}
+	}
+	return stored, nil
+}
+
+func storeFeed(tx *gorm.DB, feed Feed, tags []string) (uint, bool, error) {
+	var existing models.Feed
+	res := tx.Select("id").Limit(1).Find(&existing, "url = ?", feed.URL)
+	if res.Error != nil {
+		return 0, false, fmt.Errorf("error fetching Feed by URL %#v: %w", feed.URL, res.Error)
+	}
+	if res.RowsAffected > 0 {
+		return existing.ID, false, nil
+	}
+
+	newFeed := &models.Feed{
+		URL:     feed.URL,
+		Enabled: true,
+		Title:   sql.NullString{String: feed.Title, Valid: len(feed.Title) > 0},
+	}
+	err := newFeed.SetTags(tags)
+	if err != nil {
+		return 0, false, err
+	}
+
+	res = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(newFeed)
+	if res.Error != nil {
+		return 0, false, fmt.Errorf("error creating Feed %#v: %w", feed.URL, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return 0, false, fmt.Errorf("a deleted Feed with URL %#v already exists", feed.URL)
+	}
+	return newFeed.ID, true, nil
+}
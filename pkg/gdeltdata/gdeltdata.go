@@ -0,0 +1,241 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gdeltdata reads the GDELT 2.0 data files, which are published
+// every 15 minutes, at any given update time.
+package gdeltdata
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"github.com/SpecializedGeneralist/gdelt/eventcsv"
+	"github.com/SpecializedGeneralist/gdelt/events"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the base URL of the GDELT 2.0 data files.
+const DefaultBaseURL = "http://data.gdeltproject.org/gdeltv2/"
+
+// UpdateInterval is the time interval between two GDELT updates.
+const UpdateInterval = 15 * time.Minute
+
+// timestampLayout is the layout of the update timestamp in file names.
+const timestampLayout = "20060102150405"
+
+// ErrNotFound is returned when a data file does not exist. GDELT misses a
+// few updates, for which no file is published.
+var ErrNotFound = errors.New("GDELT data file not found")
+
+// ErrInvalidFile is returned when a data file was downloaded, but it is
+// corrupted (wrong checksum, or invalid Zip archive) or malformed. Unlike
+// network errors, downloading the file again is not expected to help.
+var ErrInvalidFile = errors.New("invalid GDELT data file")
+
+// FileType identifies one of the data files published on each update.
+type FileType string
+
+const (
+	// Export is the events table ("export.CSV.zip").
+	Export FileType = "export.CSV.zip"
+	// Mentions is the mentions table ("mentions.CSV.zip").
+	Mentions FileType = "mentions.CSV.zip"
+	// GKG is the Global Knowledge Graph ("gkg.csv.zip").
+	GKG FileType = "gkg.csv.zip"
+)
+
+// Update identifies a data file of a GDELT update.
+type Update struct {
+	Time time.Time
+	URL  string
+	// MD5Sum is the expected checksum of the file, if known.
+	MD5Sum string
+}
+
+// Client reads the GDELT data files.
+type Client struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewClient creates a new Client. The baseURL is usually DefaultBaseURL.
+//
+// A baseURL without an "http" or "https" scheme is the path of a local
+// directory (or a "file://" URL) containing the data files and, optionally,
+// "lastupdate.txt", which is especially useful for processing sample files.
+func NewClient(baseURL string, requestTimeout time.Duration) *Client {
+	transport := http.DefaultTransport
+	if !reHTTPURL.MatchString(baseURL) {
+		baseURL = localBaseURL(baseURL)
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+		transport = t
+	}
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return &Client{
+		client:  &http.Client{Timeout: requestTimeout, Transport: transport},
+		baseURL: baseURL,
+	}
+}
+
+var reHTTPURL = regexp.MustCompile(`(?i)^https?://`)
+
+func localBaseURL(dir string) string {
+	dir = strings.TrimPrefix(dir, "file://")
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(dir)}).String()
+}
+
+// Update returns the Update of the given file type at time t, which is
+// truncated to the update interval.
+func (c *Client) Update(t time.Time, ft FileType) Update {
+	t = t.UTC().Truncate(UpdateInterval)
+	return Update{
+		Time: t,
+		URL:  fmt.Sprintf("%s%s.%s", c.baseURL, t.Format(timestampLayout), ft),
+	}
+}
+
+var reLastUpdate = regexp.MustCompile(`(?m)^\d+ ([0-9a-f]{32}) \S+/(\d{14})\.(\S+)$`)
+
+// LatestUpdate reads the list of the files of the last GDELT update
+// ("lastupdate.txt"), returning the Update of the given file type.
+func (c *Client) LatestUpdate(ctx context.Context, ft FileType) (Update, error) {
+	content, err := c.get(ctx, c.baseURL+"lastupdate.txt")
+	if err != nil {
+		return Update{}, err
+	}
+
+	for _, m := range reLastUpdate.FindAllStringSubmatch(string(content), -1) {
+		if FileType(m[3]) != ft {
+			continue
+		}
+		t, err := time.Parse(timestampLayout, m[2])
+		if err != nil {
+			return Update{}, fmt.Errorf("invalid GDELT update timestamp %#v: %w", m[2], err)
+		}
+		// The file is read relative to the base URL, which might differ
+		// from the one in the list (e.g. with local files).
+		u := c.Update(t, ft)
+		u.MD5Sum = m[1]
+		return u, nil
+	}
+	return Update{}, fmt.Errorf("GDELT %s file not found in last update list", ft)
+}
+
+// Range returns the update times from "from" to "to", both included.
+// The "from" time is rounded up to the update interval.
+func Range(from, to time.Time) []time.Time {
+	from = from.UTC()
+	start := from.Truncate(UpdateInterval)
+	if start.Before(from) {
+		start = start.Add(UpdateInterval)
+	}
+
+	var times []time.Time
+	for t := start; !t.After(to); t = t.Add(UpdateInterval) {
+		times = append(times, t)
+	}
+	return times
+}
+
+// Events downloads an export file, returning all its events.
+func (c *Client) Events(ctx context.Context, u Update) ([]*events.Event, error) {
+	var evs []*events.Event
+	err := c.ReadFile(ctx, u, func(r io.Reader) error {
+		cr := eventcsv.NewReader(r)
+		for {
+			ev, err := cr.Read()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%w: error reading GDELT event: %v", ErrInvalidFile, err)
+			}
+			evs = append(evs, ev)
+		}
+	})
+	return evs, err
+}
+
+// ReadFile downloads a data file, calling fn with the reader of the CSV
+// file contained in the Zip archive.
+func (c *Client) ReadFile(ctx context.Context, u Update, fn func(io.Reader) error) error {
+	content, err := c.get(ctx, u.URL)
+	if err != nil {
+		return err
+	}
+	if len(u.MD5Sum) > 0 {
+		if actual := fmt.Sprintf("%x", md5.Sum(content)); actual != u.MD5Sum {
+			return fmt.Errorf("%w: MD5 sum of %#v: expected %s, actual %s", ErrInvalidFile, u.URL, u.MD5Sum, actual)
+		}
+	}
+	return ReadZip(content, fn)
+}
+
+// ReadZip calls fn with the reader of the single file contained in the
+// Zip-compressed content. An error wrapping ErrInvalidFile is returned if
+// the archive is invalid.
+func ReadZip(content []byte, fn func(io.Reader) error) (err error) {
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return fmt.Errorf("%w: error reading Zip archive: %v", ErrInvalidFile, err)
+	}
+	if len(zr.File) != 1 {
+		return fmt.Errorf("%w: expected one file in Zip archive, actual %d", ErrInvalidFile, len(zr.File))
+	}
+
+	f, err := zr.File[0].Open()
+	if err != nil {
+		return fmt.Errorf("%w: error opening %#v in Zip archive: %v", ErrInvalidFile, zr.File[0].Name, err)
+	}
+	defer func() {
+		if e := f.Close(); e != nil && err == nil {
+			err = e
+		}
+	}()
+	return fn(f)
+}
+
+func (c *Client) get(ctx context.Context, url string) (_ []byte, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request for %#v: %w", url, err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing request for %#v: %w", url, err)
+	}
+	defer func() {
+		if e := resp.Body.Close(); e != nil && err == nil {
+			err = fmt.Errorf("error closing response body: %w", e)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %#v", ErrNotFound, url)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request for %#v returned status code %d", url, resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body for %#v: %w", url, err)
+	}
+	return content, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdeltdata
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2021, 11, 20, 10, 5, 0, 0, time.UTC)
+	to := time.Date(2021, 11, 20, 11, 0, 0, 0, time.UTC)
+	assert.Equal(t, []time.Time{
+		time.Date(2021, 11, 20, 10, 15, 0, 0, time.UTC),
+		time.Date(2021, 11, 20, 10, 30, 0, 0, time.UTC),
+		time.Date(2021, 11, 20, 10, 45, 0, 0, time.UTC),
+		time.Date(2021, 11, 20, 11, 0, 0, 0, time.UTC),
+	}, Range(from, to))
+
+	assert.Equal(t, []time.Time{to}, Range(to, to))
+	assert.Empty(t, Range(to, from))
+}
+
+func TestClient_Update(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient("http://example.com/gdeltv2", time.Second)
+	u := c.Update(time.Date(2021, 11, 20, 10, 14, 59, 0, time.UTC), Export)
+	assert.Equal(t, Update{
+		Time: time.Date(2021, 11, 20, 10, 0, 0, 0, time.UTC),
+		URL:  "http://example.com/gdeltv2/20211120100000.export.CSV.zip",
+	}, u)
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	exportZip := makeZip(t, "20211120101500.export.CSV", makeEventRecord(1001, "http://example.com/news-1")+
+		makeEventRecord(1002, "http://example.com/news-2"))
+	exportMD5 := fmt.Sprintf("%x", md5.Sum(exportZip))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gdeltv2/lastupdate.txt":
+			_, _ = fmt.Fprintf(w, "%d %s http://%s/gdeltv2/20211120101500.export.CSV.zip\n", len(exportZip), exportMD5, r.Host)
+			_, _ = fmt.Fprintf(w, "123 0123456789abcdef0123456789abcdef http://%s/gdeltv2/20211120101500.mentions.CSV.zip\n", r.Host)
+		case "/gdeltv2/20211120101500.export.CSV.zip":
+			_, _ = w.Write(exportZip)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c := NewClient(server.URL+"/gdeltv2/", 5*time.Second)
+	ctx := context.Background()
+
+	latest, err := c.LatestUpdate(ctx, Export)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 11, 20, 10, 15, 0, 0, time.UTC), latest.Time)
+	assert.Equal(t, exportMD5, latest.MD5Sum)
+
+	evs, err := c.Events(ctx, latest)
+	require.NoError(t, err)
+	require.Len(t, evs, 2)
+	assert.Equal(t, uint64(1001), evs[0].GlobalEventID)
+	assert.Equal(t, "http://example.com/news-2", evs[1].SourceURL)
+
+	evs, err = c.Events(ctx, c.Update(latest.Time, Export))
+	require.NoError(t, err)
+	assert.Len(t, evs, 2)
+
+	_, err = c.LatestUpdate(ctx, GKG)
+	assert.Error(t, err)
+
+	wrongSum := latest
+	wrongSum.MD5Sum = "0123456789abcdef0123456789abcdef"
+	_, err = c.Events(ctx, wrongSum)
+	assert.ErrorIs(t, err, ErrInvalidFile)
+
+	_, err = c.Events(ctx, c.Update(latest.Time.Add(UpdateInterval), Export))
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestReadZip(t *testing.T) {
+	t.Parallel()
+
+	err := ReadZip(makeZip(t, "a.CSV", "content"), func(r io.Reader) error {
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "content", string(content))
+		return nil
+	})
+	assert.NoError(t, err)
+
+	err = ReadZip([]byte("not a Zip archive"), func(io.Reader) error { return nil })
+	assert.ErrorIs(t, err, ErrInvalidFile)
+}
+
+func makeZip(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(name)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func makeEventRecord(id int, sourceURL string) string {
+	fields := []string{fmt.Sprintf("%d", id), "20211120", "202111", "2021", "2021.8877"}
+	fields = append(fields, make([]string, 20)...)                                        // actors
+	fields = append(fields, "1", "043", "043", "04", "1", "1.9", "10", "1", "10", "-1.5") // event
+	for i := 0; i < 3; i++ {
+		fields = append(fields, "0", "", "", "", "", "", "", "") // geo
+	}
+	fields = append(fields, "20211120101500", sourceURL)
+	return strings.Join(fields, "\t") + "\n"
+}
@@ -0,0 +1,277 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdeltdata
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gkgColumnsCount is the number of tab-separated columns of a GKG 2.1
+// record.
+const gkgColumnsCount = 27
+
+// The indices of the GKG 2.1 columns read by ReadGKG.
+const (
+	gkgRecordID           = 0
+	gkgDate               = 1
+	gkgSourceCollection   = 2
+	gkgSourceCommonName   = 3
+	gkgDocumentIdentifier = 4
+	gkgThemes             = 7
+	gkgLocations          = 9
+	gkgPersons            = 11
+	gkgOrganizations      = 13
+	gkgTone               = 15
+	gkgSharingImage       = 18
+)
+
+// GKGWebSource is the GKG source collection identifier of the documents
+// from the open web, whose DocumentIdentifier is their URL.
+const GKGWebSource = 1
+
+// GKGRecord is a record of the GDELT Global Knowledge Graph (GKG 2.1),
+// which describes a single source document.
+type GKGRecord struct {
+	// RecordID is the globally unique identifier of the record.
+	RecordID string
+	// Date is the time the document was processed.
+	Date time.Time
+	// SourceCollection identifies the source collection of the document
+	// (see GKGWebSource).
+	SourceCollection int
+	// SourceCommonName is a human-friendly identifier of the source.
+	SourceCommonName string
+	// DocumentIdentifier is the URL of the document, for web sources.
+	DocumentIdentifier string
+	// Themes is the list of unique themes found in the document.
+	Themes []string
+	// Locations is the list of unique locations found in the document.
+	Locations []GKGLocation
+	// Persons is the list of unique person names found in the document.
+	Persons []string
+	// Organizations is the list of unique organization names found in the
+	// document.
+	Organizations []string
+	// Tone holds the emotional dimensions of the document.
+	Tone GKGTone
+	// SharingImage is the URL of the image the document suggests for
+	// sharing on social media, if any.
+	SharingImage string
+}
+
+// GKGLocation is a location mentioned in a GKG document.
+type GKGLocation struct {
+	Type        int     `json:"type"`
+	FullName    string  `json:"full_name"`
+	CountryCode string  `json:"country_code"` // FIPS 10-4
+	ADM1Code    string  `json:"adm1_code"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	FeatureID   string  `json:"feature_id"`
+}
+
+// GKGTone holds the tone scores of a GKG document.
+type GKGTone struct {
+	// Tone is the average tone of the document, from -100 (extremely
+	// negative) to +100 (extremely positive).
+	Tone float64
+	// Positive is the percentage of all words with positive emotional
+	// connotation.
+	Positive float64
+	// Negative is the percentage of all words with negative emotional
+	// connotation.
+	Negative float64
+	// Polarity is the percentage of words with emotional connotation.
+	Polarity float64
+	// WordCount is the total number of words in the document.
+	WordCount int
+}
+
+// InvalidGKGRecordError describes a malformed GKG record, which was skipped.
+type InvalidGKGRecordError struct {
+	// Line is the line number of the record, starting from 1.
+	Line int
+	// Err is the parsing error.
+	Err error
+}
+
+// Error satisfies the error interface.
+func (e *InvalidGKGRecordError) Error() string {
+	return fmt.Sprintf("invalid GKG record at line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the parsing error.
+func (e *InvalidGKGRecordError) Unwrap() error {
+	return e.Err
+}
+
+// GKGRecords downloads a GKG file, returning all its valid records.
+// Malformed records are skipped and reported to onInvalid, if not nil.
+func (c *Client) GKGRecords(ctx context.Context, u Update, onInvalid func(*InvalidGKGRecordError)) ([]*GKGRecord, error) {
+	var records []*GKGRecord
+	err := c.ReadFile(ctx, u, func(r io.Reader) (err error) {
+		records, err = ReadGKG(r, onInvalid)
+		return err
+	})
+	return records, err
+}
+
+// ReadGKG reads all the valid records of an uncompressed GKG 2.1 CSV file.
+//
+// A single malformed record does not invalidate the whole file: it is
+// skipped and reported to onInvalid, if not nil. An error is returned only
+// if the file cannot be read.
+func ReadGKG(r io.Reader, onInvalid func(*InvalidGKGRecordError)) ([]*GKGRecord, error) {
+	var records []*GKGRecord
+	br := bufio.NewReader(r)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("error reading GKG file: %w", err)
+		}
+		if line = strings.TrimRight(line, "\r\n"); len(line) > 0 {
+			record, parseErr := parseGKGRecord(line)
+			switch {
+			case parseErr == nil:
+				records = append(records, record)
+			case onInvalid != nil:
+				onInvalid(&InvalidGKGRecordError{Line: lineNumber, Err: parseErr})
+			}
+		}
+		if err == io.EOF {
+			return records, nil
+		}
+	}
+}
+
+func parseGKGRecord(line string) (*GKGRecord, error) {
+	// GKG files are not guaranteed to be valid UTF-8.
+	cols := strings.Split(strings.ToValidUTF8(line, ""), "\t")
+	if len(cols) != gkgColumnsCount {
+		return nil, fmt.Errorf("expected %d columns, actual %d", gkgColumnsCount, len(cols))
+	}
+
+	date, err := time.Parse(timestampLayout, cols[gkgDate])
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %#v: %w", cols[gkgDate], err)
+	}
+	sourceCollection, err := strconv.Atoi(cols[gkgSourceCollection])
+	if err != nil {
+		return nil, fmt.Errorf("invalid source collection identifier %#v: %w", cols[gkgSourceCollection], err)
+	}
+	locations, err := parseGKGLocations(cols[gkgLocations])
+	if err != nil {
+		return nil, err
+	}
+	tone, err := parseGKGTone(cols[gkgTone])
+	if err != nil {
+		return nil, err
+	}
+
+	return &GKGRecord{
+		RecordID:           cols[gkgRecordID],
+		Date:               date,
+		SourceCollection:   sourceCollection,
+		SourceCommonName:   cols[gkgSourceCommonName],
+		DocumentIdentifier: cols[gkgDocumentIdentifier],
+		Themes:             splitGKGList(cols[gkgThemes]),
+		Locations:          locations,
+		Persons:            splitGKGList(cols[gkgPersons]),
+		Organizations:      splitGKGList(cols[gkgOrganizations]),
+		Tone:               tone,
+		SharingImage:       cols[gkgSharingImage],
+	}, nil
+}
+
+// splitGKGList splits a semicolon-delimited list, omitting empty and
+// repeated values.
+func splitGKGList(s string) []string {
+	var values []string
+	seen := make(map[string]struct{})
+	for _, v := range strings.Split(s, ";") {
+		if _, ok := seen[v]; ok || len(v) == 0 {
+			continue
+		}
+		seen[v] = struct{}{}
+		values = append(values, v)
+	}
+	return values
+}
+
+// parseGKGLocations parses the V1 locations: a semicolon-delimited list
+// of locations, whose fields are delimited by "#".
+func parseGKGLocations(s string) ([]GKGLocation, error) {
+	var locations []GKGLocation
+	for _, v := range splitGKGList(s) {
+		fields := strings.Split(v, "#")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("invalid location %#v", v)
+		}
+		loc := GKGLocation{
+			FullName:    fields[1],
+			CountryCode: fields[2],
+			ADM1Code:    fields[3],
+			FeatureID:   fields[6],
+		}
+		var err error
+		if loc.Type, err = strconv.Atoi(fields[0]); err != nil {
+			return nil, fmt.Errorf("invalid location type in %#v: %w", v, err)
+		}
+		if loc.Latitude, err = parseOptionalFloat(fields[4]); err != nil {
+			return nil, fmt.Errorf("invalid location latitude in %#v: %w", v, err)
+		}
+		if loc.Longitude, err = parseOptionalFloat(fields[5]); err != nil {
+			return nil, fmt.Errorf("invalid location longitude in %#v: %w", v, err)
+		}
+		locations = append(locations, loc)
+	}
+	return locations, nil
+}
+
+// parseGKGTone parses the comma-delimited tone scores. Only the scores
+// stored in GKGTone are read; an empty value results in zero scores.
+func parseGKGTone(s string) (GKGTone, error) {
+	if len(s) == 0 {
+		return GKGTone{}, nil
+	}
+	fields := strings.Split(s, ",")
+	if len(fields) < 7 {
+		return GKGTone{}, fmt.Errorf("invalid tone %#v", s)
+	}
+
+	var scores [4]float64
+	for i := range scores {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return GKGTone{}, fmt.Errorf("invalid tone %#v: %w", s, err)
+		}
+		scores[i] = v
+	}
+	wordCount, err := strconv.Atoi(fields[6])
+	if err != nil {
+		return GKGTone{}, fmt.Errorf("invalid tone word count %#v: %w", s, err)
+	}
+
+	return GKGTone{
+		Tone:      scores[0],
+		Positive:  scores[1],
+		Negative:  scores[2],
+		Polarity:  scores[3],
+		WordCount: wordCount,
+	}, nil
+}
+
+func parseOptionalFloat(s string) (float64, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdeltdata
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_GKGRecords_LocalFiles(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient("testdata", time.Second)
+	ctx := context.Background()
+
+	latest, err := c.LatestUpdate(ctx, GKG)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 11, 20, 10, 15, 0, 0, time.UTC), latest.Time)
+	assert.True(t, strings.HasPrefix(latest.URL, "file:///"))
+	assert.True(t, strings.HasSuffix(latest.URL, "/testdata/20211120101500.gkg.csv.zip"))
+
+	records, err := c.GKGRecords(ctx, latest, nil)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, &GKGRecord{
+		RecordID:           "20211120101500-0",
+		Date:               time.Date(2021, 11, 20, 10, 15, 0, 0, time.UTC),
+		SourceCollection:   GKGWebSource,
+		SourceCommonName:   "example.com",
+		DocumentIdentifier: "https://example.com/news/elections",
+		Themes:             []string{"GENERAL_GOVERNMENT", "ELECTION", "LEADER"},
+		Locations: []GKGLocation{
+			{Type: 1, FullName: "France", CountryCode: "FR", ADM1Code: "FR", Latitude: 46, Longitude: 2, FeatureID: "FR"},
+			{Type: 4, FullName: "Paris, Ile-De-France, France", CountryCode: "FR", ADM1Code: "FRA8", Latitude: 48.8667, Longitude: 2.33333, FeatureID: "-1456928"},
+		},
+		Persons:       []string{"emmanuel macron"},
+		Organizations: []string{"european union", "united nations"},
+		Tone: GKGTone{
+			Tone:      1.96078431372549,
+			Positive:  3.92156862745098,
+			Negative:  1.96078431372549,
+			Polarity:  5.88235294117647,
+			WordCount: 510,
+		},
+		SharingImage: "https://example.com/images/elections.jpg",
+	}, records[0])
+
+	assert.Nil(t, records[1].Locations)
+	assert.Equal(t, -7.5, records[1].Tone.Tone)
+	assert.Equal(t, 2, records[2].SourceCollection)
+
+	_, err = c.GKGRecords(ctx, c.Update(latest.Time.Add(UpdateInterval), GKG), nil)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = c.LatestUpdate(ctx, Export)
+	assert.Error(t, err)
+}
+
+func TestReadGKG(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+		records, err := ReadGKG(strings.NewReader(""), nil)
+		require.NoError(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("invalid UTF-8 and missing final newline", func(t *testing.T) {
+		cols := make([]string, gkgColumnsCount)
+		cols[gkgRecordID] = "20211120101500-7"
+		cols[gkgDate] = "20211120101500"
+		cols[gkgSourceCollection] = "1"
+		cols[gkgPersons] = "jos\xe9;"
+		records, err := ReadGKG(strings.NewReader(strings.Join(cols, "\t")), nil)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, []string{"jos"}, records[0].Persons)
+		assert.Equal(t, GKGTone{}, records[0].Tone)
+	})
+
+	t.Run("malformed records are skipped", func(t *testing.T) {
+		valid := make([]string, gkgColumnsCount)
+		valid[gkgRecordID] = "20211120101500-1"
+		valid[gkgDate] = "20211120101500"
+		valid[gkgSourceCollection] = "1"
+
+		badLocation := append([]string(nil), valid...)
+		badLocation[gkgLocations] = "1#France"
+		badTone := append([]string(nil), valid...)
+		badTone[gkgTone] = "x,1,2,3,4,5,6"
+
+		lines := []string{
+			"a\tb",
+			strings.Join(valid, "\t"),
+			strings.Join(badLocation, "\t"),
+			strings.Join(badTone, "\t"),
+		}
+
+		var invalid []int
+		records, err := ReadGKG(strings.NewReader(strings.Join(lines, "\n")), func(e *InvalidGKGRecordError) {
+			assert.Error(t, e.Err)
+			invalid = append(invalid, e.Line)
+		})
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, "20211120101500-1", records[0].RecordID)
+		assert.Equal(t, []int{1, 3, 4}, invalid)
+	})
+
+	t.Run("malformed records without callback", func(t *testing.T) {
+		records, err := ReadGKG(strings.NewReader("a\tb\n"), nil)
+		require.NoError(t, err)
+		assert.Empty(t, records)
+	})
+}
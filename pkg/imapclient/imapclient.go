@@ -0,0 +1,312 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package imapclient implements a minimal IMAP4rev1 client (RFC 3501),
+// which only provides the commands for reading the messages of a mailbox.
+//
+// A full-featured library, such as github.com/emersion/go-imap, was not
+// adopted because it is not among the dependencies of the module, while the
+// newsletter fetcher only needs a read-only subset of the protocol: LOGIN,
+// EXAMINE, UID SEARCH, UID FETCH and LOGOUT, over a TLS or plain
+// connection. Anything else (e.g. STARTTLS, SASL authentication, IDLE, or
+// changing flags and mailboxes) is not supported.
+package imapclient
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLiteralSize is the maximum allowed size of a literal string sent by
+// the server, such as the content of a message.
+const maxLiteralSize = 50 << 20
+
+// Client is an IMAP client connection.
+type Client struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+	tag     int
+}
+
+// Mailbox holds the status of a selected mailbox.
+type Mailbox struct {
+	// Name is the name of the mailbox.
+	Name string
+	// Messages is the number of messages in the mailbox.
+	Messages int
+	// UIDValidity is the unique identifier validity value. When it changes,
+	// the UIDs previously read from the mailbox are no longer valid.
+	UIDValidity uint32
+}
+
+// Dial connects to the IMAP server at the given address ("host:port"),
+// using implicit TLS if useTLS is true. The timeout applies to the
+// connection and to each command.
+func Dial(ctx context.Context, addr string, useTLS bool, timeout time.Duration) (*Client, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to IMAP server %s: %w", addr, err)
+	}
+
+	if useTLS {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("invalid IMAP server address %#v: %w", addr, err)
+		}
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host})
+		hsCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		if err = tlsConn.HandshakeContext(hsCtx); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("TLS handshake with IMAP server %s failed: %w", addr, err)
+		}
+		conn = tlsConn
+	}
+
+	c := &Client{
+		conn:    conn,
+		r:       bufio.NewReader(conn),
+		timeout: timeout,
+	}
+	if err = c.readGreeting(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes the connection, without logging out.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Login authenticates the client with the LOGIN command.
+func (c *Client) Login(username, password string) error {
+	u, err := Quote(username)
+	if err != nil {
+		return fmt.Errorf("invalid IMAP username: %w", err)
+	}
+	p, err := Quote(password)
+	if err != nil {
+		return fmt.Errorf("invalid IMAP password: %w", err)
+	}
+	_, err = c.execute("LOGIN " + u + " " + p)
+	return err
+}
+
+// Logout ends the session with the LOGOUT command.
+func (c *Client) Logout() error {
+	_, err := c.execute("LOGOUT")
+	return err
+}
+
+var (
+	reExists      = regexp.MustCompile(`^\* (\d+) EXISTS`)
+	reUIDValidity = regexp.MustCompile(`\[UIDVALIDITY (\d+)\]`)
+)
+
+// Examine selects a mailbox in read-only mode, with the EXAMINE command.
+func (c *Client) Examine(name string) (*Mailbox, error) {
+	quoted, err := Quote(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid IMAP mailbox name: %w", err)
+	}
+	responses, err := c.execute("EXAMINE " + quoted)
+	if err != nil {
+		return nil, err
+	}
+
+	mb := &Mailbox{Name: name}
+	for _, resp := range responses {
+		if m := reExists.FindStringSubmatch(resp.line); m != nil {
+			mb.Messages, _ = strconv.Atoi(m[1])
+		}
+		if m := reUIDValidity.FindStringSubmatch(resp.line); m != nil {
+			v, err := strconv.ParseUint(m[1], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UIDVALIDITY %#v: %w", m[1], err)
+			}
+			mb.UIDValidity = uint32(v)
+		}
+	}
+	return mb, nil
+}
+
+// SearchUIDs returns, in ascending order, the UIDs of the messages of the
+// selected mailbox which are greater than afterUID. If from is not empty,
+// only the messages whose "From" header contains it are returned.
+func (c *Client) SearchUIDs(afterUID uint32, from string) ([]uint32, error) {
+	criteria := fmt.Sprintf("UID %d:*", afterUID+1)
+	if len(from) > 0 {
+		quoted, err := Quote(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IMAP search sender: %w", err)
+		}
+		criteria += " FROM " + quoted
+	}
+	responses, err := c.execute("UID SEARCH " + criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	var uids []uint32
+	for _, resp := range responses {
+		if !strings.HasPrefix(resp.line, "* SEARCH") {
+			continue
+		}
+		for _, f := range strings.Fields(resp.line[len("* SEARCH"):]) {
+			v, err := strconv.ParseUint(f, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UID %#v in search response: %w", f, err)
+			}
+			// The range "n:*" always includes the last message, even if
+			// its UID is lower than n.
+			if uint32(v) > afterUID {
+				uids = append(uids, uint32(v))
+			}
+		}
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	return uids, nil
+}
+
+var reFetchUID = regexp.MustCompile(`^\* \d+ FETCH \(.*\bUID (\d+)\b`)
+
+// FetchMessage returns the full raw content (RFC 5322) of the message with
+// the given UID, without setting the \Seen flag.
+func (c *Client) FetchMessage(uid uint32) ([]byte, error) {
+	responses, err := c.execute(fmt.Sprintf("UID FETCH %d (UID BODY.PEEK[])", uid))
+	if err != nil {
+		return nil, err
+	}
+	for _, resp := range responses {
+		m := reFetchUID.FindStringSubmatch(resp.line)
+		if m == nil || m[1] != strconv.FormatUint(uint64(uid), 10) || len(resp.literals) == 0 {
+			continue
+		}
+		return resp.literals[0], nil
+	}
+	return nil, fmt.Errorf("IMAP message with UID %d not found", uid)
+}
+
+// Quote returns s as an IMAP quoted string. Strings containing characters
+// which cannot be quoted (CR, LF, NUL and non-ASCII) are not supported.
+func Quote(s string) (string, error) {
+	for i := 0; i < len(s); i++ {
+		if b := s[i]; b == '\r' || b == '\n' || b == 0 || b > 0x7f {
+			return "", fmt.Errorf("unsupported character in %#v", s)
+		}
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`, nil
+}
+
+// response is a server response line, whose literal strings are collected
+// separately.
+type response struct {
+	line     string
+	literals [][]byte
+}
+
+func (c *Client) readGreeting() error {
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return err
+	}
+	resp, err := c.readResponse()
+	if err != nil {
+		return fmt.Errorf("error reading IMAP greeting: %w", err)
+	}
+	if !strings.HasPrefix(resp.line, "* OK") && !strings.HasPrefix(resp.line, "* PREAUTH") {
+		return fmt.Errorf("unexpected IMAP greeting %#v", resp.line)
+	}
+	return nil
+}
+
+// execute sends a command and reads all the responses, up to the tagged
+// completion result. Only the untagged responses are returned.
+func (c *Client) execute(command string) ([]*response, error) {
+	// Only the command name is reported in errors, since the arguments
+	// might contain credentials.
+	name := strings.SplitN(command, " ", 2)[0]
+	if name == "UID" {
+		name = strings.Join(strings.SplitN(command, " ", 3)[:2], " ")
+	}
+
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, err
+	}
+
+	c.tag++
+	tag := fmt.Sprintf("A%d", c.tag)
+	if _, err := io.WriteString(c.conn, tag+" "+command+"\r\n"); err != nil {
+		return nil, fmt.Errorf("error sending IMAP command %s: %w", name, err)
+	}
+
+	var untagged []*response
+	for {
+		resp, err := c.readResponse()
+		if err != nil {
+			return nil, fmt.Errorf("error reading IMAP %s response: %w", name, err)
+		}
+		if strings.HasPrefix(resp.line, tag+" ") {
+			status := resp.line[len(tag)+1:]
+			if !strings.HasPrefix(status, "OK") {
+				return nil, fmt.Errorf("IMAP command %s failed: %s", name, status)
+			}
+			return untagged, nil
+		}
+		if strings.HasPrefix(resp.line, "+") {
+			return nil, fmt.Errorf("unexpected IMAP continuation request for %s", name)
+		}
+		untagged = append(untagged, resp)
+	}
+}
+
+var reLiteral = regexp.MustCompile(`\{(\d+)\+?\}$`)
+
+var errLiteralTooLarge = errors.New("IMAP literal too large")
+
+// readResponse reads a response line, including any literal string.
+func (c *Client) readResponse() (*response, error) {
+	resp := &response{}
+	var sb strings.Builder
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		sb.WriteString(line)
+
+		m := reLiteral.FindStringSubmatch(line)
+		if m == nil {
+			break
+		}
+		size, err := strconv.Atoi(m[1])
+		if err != nil || size > maxLiteralSize {
+			return nil, errLiteralTooLarge
+		}
+		literal := make([]byte, size)
+		if _, err = io.ReadFull(c.r, literal); err != nil {
+			return nil, err
+		}
+		resp.literals = append(resp.literals, literal)
+	}
+	resp.line = sb.String()
+	return resp, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package imapclient
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testMessage = "Message-ID: <1@example.com>\r\nSubject: Hello\r\n\r\nHello, world!\r\n"
+
+// serveIMAP runs a fake IMAP server, which replies to each command with the
+// responses returned by reply. The received commands (without tags) are
+// sent to the returned channel.
+func serveIMAP(t *testing.T, reply func(command string) string) (string, <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	commands := make(chan string, 100)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = fmt.Fprint(conn, "* OK IMAP4rev1 ready\r\n")
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			parts := strings.SplitN(strings.TrimRight(line, "\r\n"), " ", 2)
+			commands <- parts[1]
+			_, _ = fmt.Fprint(conn, strings.ReplaceAll(reply(parts[1]), "TAG", parts[0]))
+		}
+	}()
+	return ln.Addr().String(), commands
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	addr, commands := serveIMAP(t, func(command string) string {
+		switch {
+		case strings.HasPrefix(command, "LOGIN"):
+			return "TAG OK LOGIN completed\r\n"
+		case strings.HasPrefix(command, "EXAMINE"):
+			return "* 3 EXISTS\r\n* OK [UIDVALIDITY 1637400000] UIDs valid\r\nTAG OK [READ-ONLY] EXAMINE completed\r\n"
+		case strings.HasPrefix(command, "UID SEARCH"):
+			return "* SEARCH 12 10\r\nTAG OK SEARCH completed\r\n"
+		case command == "UID FETCH 12 (UID BODY.PEEK[])":
+			return fmt.Sprintf("* 3 FETCH (FLAGS (\\Seen))\r\n* 3 FETCH (UID 12 BODY[] {%d}\r\n%s)\r\nTAG OK FETCH completed\r\n",
+				len(testMessage), testMessage)
+		case strings.HasPrefix(command, "UID FETCH"):
+			return "TAG OK FETCH completed\r\n"
+		case command == "LOGOUT":
+			return "* BYE logging out\r\nTAG OK LOGOUT completed\r\n"
+		default:
+			return "TAG BAD unknown command\r\n"
+		}
+	})
+
+	c, err := Dial(context.Background(), addr, false, 5*time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	require.NoError(t, c.Login("user@example.com", `pa"ss`))
+	assert.Equal(t, `LOGIN "user@example.com" "pa\"ss"`, <-commands)
+
+	mb, err := c.Examine("Newsletters")
+	require.NoError(t, err)
+	assert.Equal(t, &Mailbox{Name: "Newsletters", Messages: 3, UIDValidity: 1637400000}, mb)
+	assert.Equal(t, `EXAMINE "Newsletters"`, <-commands)
+
+	uids, err := c.SearchUIDs(10, "news@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []uint32{12}, uids)
+	assert.Equal(t, `UID SEARCH UID 11:* FROM "news@example.com"`, <-commands)
+
+	msg, err := c.FetchMessage(12)
+	require.NoError(t, err)
+	assert.Equal(t, testMessage, string(msg))
+	<-commands
+
+	_, err = c.FetchMessage(13)
+	assert.Error(t, err)
+	<-commands
+
+	require.NoError(t, c.Logout())
+}
+
+func TestClient_CommandFailure(t *testing.T) {
+	t.Parallel()
+
+	addr, _ := serveIMAP(t, func(string) string {
+		return "TAG NO [AUTHENTICATIONFAILED] Invalid credentials\r\n"
+	})
+
+	c, err := Dial(context.Background(), addr, false, 5*time.Second)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	err = c.Login("user", "secret")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AUTHENTICATIONFAILED")
+	assert.NotContains(t, err.Error(), "secret")
+}
+
+func TestQuote(t *testing.T) {
+	t.Parallel()
+
+	q, err := Quote(`a\b"c`)
+	require.NoError(t, err)
+	assert.Equal(t, `"a\\b\"c"`, q)
+
+	_, err = Quote("a\r\nb")
+	assert.Error(t, err)
+	_, err = Quote("caffè")
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package jsonpath implements a small subset of JSONPath, for selecting
+// single values from decoded JSON documents.
+//
+// A path is a sequence of object keys and array indices, optionally
+// starting with "$", such as "$.data.items", "items[0].title", or
+// "$['dc:title']". An empty path (or just "$") selects the whole document.
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path is a compiled JSONPath expression.
+type Path struct {
+	raw   string
+	steps []step
+}
+
+// step is either an object key or an array index.
+type step struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// Compile parses a JSONPath expression.
+func Compile(expr string) (Path, error) {
+	p := Path{raw: expr}
+	s := strings.TrimSpace(expr)
+	s = strings.TrimPrefix(s, "$")
+
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			n := strings.IndexAny(s, ".[]")
+			if n == -1 {
+				n = len(s)
+			}
+			if n == 0 {
+				return Path{}, fmt.Errorf("invalid JSONPath %#v: empty key", expr)
+			}
+			if n < len(s) && s[n] == ']' {
+				return Path{}, fmt.Errorf("invalid JSONPath %#v: unexpected \"]\"", expr)
+			}
+			p.steps = append(p.steps, step{key: s[:n]})
+			s = s[n:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end == -1 {
+				return Path{}, fmt.Errorf("invalid JSONPath %#v: missing closing bracket", expr)
+			}
+			st, err := parseBracket(s[1:end])
+			if err != nil {
+				return Path{}, fmt.Errorf("invalid JSONPath %#v: %w", expr, err)
+			}
+			p.steps = append(p.steps, st)
+			s = s[end+1:]
+		default:
+			if len(p.steps) > 0 {
+				return Path{}, fmt.Errorf("invalid JSONPath %#v: unexpected %#v", expr, s[:1])
+			}
+			// A leading key without the dot, such as "items.title".
+			s = "." + s
+		}
+	}
+	return p, nil
+}
+
+// MustCompile is like Compile, but panics if the expression cannot be
+// parsed.
+func MustCompile(expr string) Path {
+	p, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func parseBracket(s string) (step, error) {
+	s = strings.TrimSpace(s)
+	if n := len(s); n >= 2 && (s[0] == '\'' || s[0] == '"') && s[n-1] == s[0] {
+		return step{key: s[1 : n-1]}, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 0 {
+		return step{}, fmt.Errorf("invalid array index %#v", s)
+	}
+	return step{index: i, isIndex: true}, nil
+}
+
+// String returns the original expression.
+func (p Path) String() string {
+	return p.raw
+}
+
+// IsEmpty reports whether the Path selects the whole document.
+func (p Path) IsEmpty() bool {
+	return len(p.steps) == 0
+}
+
+// Get returns the value selected by the Path from a document decoded with
+// encoding/json into an interface{}. The boolean result is false if any
+// step of the path does not match.
+func (p Path) Get(doc interface{}) (interface{}, bool) {
+	v := doc
+	for _, st := range p.steps {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			if st.isIndex {
+				return nil, false
+			}
+			var ok bool
+			if v, ok = t[st.key]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			if !st.isIndex || st.index >= len(t) {
+				return nil, false
+			}
+			v = t[st.index]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// GetString returns the value selected by the Path, converted to a string.
+// Numbers (including json.Number) and booleans are formatted; null, objects
+// and arrays are not converted, and false is returned.
+func (p Path) GetString(doc interface{}) (string, bool) {
+	v, ok := p.Get(doc)
+	if !ok {
+		return "", false
+	}
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), true
+	case json.Number:
+		return t.String(), true
+	case bool:
+		return strconv.FormatBool(t), true
+	default:
+		return "", false
+	}
+}
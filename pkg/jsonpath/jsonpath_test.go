@@ -0,0 +1,98 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsonpath
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPath(t *testing.T) {
+	t.Parallel()
+
+	var doc interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"data": {
+			"items": [
+				{"title": "Foo", "id": 42, "meta": {"dc:date": "2021-11-20"}},
+				{"title": "Bar", "draft": true, "tags": null}
+			],
+			"next": "abc"
+		}
+	}`), &doc))
+
+	testCases := []struct {
+		expr     string
+		expected interface{}
+		ok       bool
+	}{
+		{"$.data.next", "abc", true},
+		{"data.next", "abc", true},
+		{"$['data']['next']", "abc", true},
+		{`$["data"].next`, "abc", true},
+		{"$.data.items[0].title", "Foo", true},
+		{"$.data.items[1].title", "Bar", true},
+		{"$.data.items[0].meta['dc:date']", "2021-11-20", true},
+		{"$.data.items[2].title", nil, false},
+		{"$.data.items.title", nil, false},
+		{"$.data[0]", nil, false},
+		{"$.data.missing", nil, false},
+		{"$.data.next.foo", nil, false},
+		{"$.data.items[1].tags", nil, true},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.expr, func(t *testing.T) {
+			t.Parallel()
+			p, err := Compile(tc.expr)
+			require.NoError(t, err)
+			v, ok := p.Get(doc)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, v)
+		})
+	}
+
+	t.Run("whole document", func(t *testing.T) {
+		t.Parallel()
+		for _, expr := range []string{"", "$", " $ "} {
+			p, err := Compile(expr)
+			require.NoError(t, err)
+			assert.True(t, p.IsEmpty())
+			v, ok := p.Get(doc)
+			assert.True(t, ok)
+			assert.Equal(t, doc, v)
+		}
+	})
+
+	t.Run("GetString", func(t *testing.T) {
+		t.Parallel()
+		s, ok := MustCompile("$.data.items[0].id").GetString(doc)
+		assert.True(t, ok)
+		assert.Equal(t, "42", s)
+
+		s, ok = MustCompile("$.data.items[1].draft").GetString(doc)
+		assert.True(t, ok)
+		assert.Equal(t, "true", s)
+
+		s, ok = MustCompile("$[0]").GetString([]interface{}{json.Number("12345678901234567890")})
+		assert.True(t, ok)
+		assert.Equal(t, "12345678901234567890", s)
+
+		_, ok = MustCompile("$.data.items[1].tags").GetString(doc)
+		assert.False(t, ok)
+		_, ok = MustCompile("$.data.items").GetString(doc)
+		assert.False(t, ok)
+	})
+
+	t.Run("invalid expressions", func(t *testing.T) {
+		t.Parallel()
+		for _, expr := range []string{"$.", "$..a", "$.a[", "$.a[-1]", "$.a[x]", "$.a]b"} {
+			_, err := Compile(expr)
+			assert.Error(t, err, expr)
+		}
+	})
+}
@@ -0,0 +1,298 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package mastodon implements a minimal client for the public REST API of
+// Mastodon instances, for reading the statuses (posts) of an account or of
+// a hashtag timeline.
+package mastodon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPageSize is the maximum number of statuses returned by the API with a
+// single request.
+const maxPageSize = 40
+
+// maxResponseSize is the maximum size of a response body.
+const maxResponseSize = 10 * 1024 * 1024
+
+// Account is a Mastodon account.
+type Account struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	// Acct is the username for local accounts, or "username@domain" for
+	// remote accounts.
+	Acct string `json:"acct"`
+	URL  string `json:"url"`
+}
+
+// Status is a Mastodon status (post).
+type Status struct {
+	ID string `json:"id"`
+	// URI is the globally unique ActivityPub identifier of the status.
+	URI string `json:"uri"`
+	// URL is the address of the HTML representation of the status.
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+	// Content is the HTML content of the status.
+	Content string `json:"content"`
+	// Language is the ISO 639 code of the status language, if known.
+	Language         string            `json:"language"`
+	SpoilerText      string            `json:"spoiler_text"`
+	Account          Account           `json:"account"`
+	Reblog           *Status           `json:"reblog"`
+	MediaAttachments []MediaAttachment `json:"media_attachments"`
+	Card             *Card             `json:"card"`
+}
+
+// MediaAttachment is a media file attached to a Status.
+type MediaAttachment struct {
+	// Type is one of "image", "gifv", "video", "audio" or "unknown".
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Card is the preview of the link shared by a Status.
+type Card struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+// Client performs the requests to Mastodon instances.
+type Client struct {
+	client         *http.Client
+	requestTimeout time.Duration
+	userAgent      string
+}
+
+// NewClient creates a new Client.
+func NewClient(requestTimeout time.Duration, userAgent string) *Client {
+	return &Client{
+		client:         &http.Client{},
+		requestTimeout: requestTimeout,
+		userAgent:      userAgent,
+	}
+}
+
+// LookupAccount finds an account of the instance by its acct, that is the
+// username for local accounts, or "username@domain" for remote accounts.
+func (c *Client) LookupAccount(ctx context.Context, instanceURL, acct string) (*Account, error) {
+	acct = strings.TrimPrefix(strings.TrimSpace(acct), "@")
+	u, err := endpointURL(instanceURL, "/api/v1/accounts/lookup", url.Values{"acct": {acct}})
+	if err != nil {
+		return nil, err
+	}
+
+	var account *Account
+	err = c.get(ctx, u, &account)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil || len(account.ID) == 0 {
+		return nil, fmt.Errorf("account %#v not found on %#v", acct, instanceURL)
+	}
+	return account, nil
+}
+
+// AccountStatuses returns up to maxStatuses most recent statuses of an
+// account, excluding reblogs.
+func (c *Client) AccountStatuses(
+	ctx context.Context,
+	instanceURL, accountID string,
+	maxStatuses int,
+) ([]Status, error) {
+	path := fmt.Sprintf("/api/v1/accounts/%s/statuses", url.PathEscape(accountID))
+	query := url.Values{"exclude_reblogs": {"true"}}
+	return c.statuses(ctx, instanceURL, path, query, maxStatuses)
+}
+
+// HashtagStatuses returns up to maxStatuses most recent statuses of the
+// public timeline of a hashtag.
+func (c *Client) HashtagStatuses(
+	ctx context.Context,
+	instanceURL, hashtag string,
+	maxStatuses int,
+) ([]Status, error) {
+	hashtag = strings.TrimPrefix(strings.TrimSpace(hashtag), "#")
+	path := fmt.Sprintf("/api/v1/timelines/tag/%s", url.PathEscape(hashtag))
+	return c.statuses(ctx, instanceURL, path, url.Values{}, maxStatuses)
+}
+
+// statuses requests the pages of a timeline, from the most recent status
+// backwards, until maxStatuses are read or the timeline ends.
+func (c *Client) statuses(
+	ctx context.Context,
+	instanceURL, path string,
+	query url.Values,
+	maxStatuses int,
+) ([]Status, error) {
+	var result []Status
+	for len(result) < maxStatuses {
+		limit := maxStatuses - len(result)
+		if limit > maxPageSize {
+			limit = maxPageSize
+		}
+		query.Set("limit", strconv.Itoa(limit))
+		if len(result) > 0 {
+			query.Set("max_id", result[len(result)-1].ID)
+		}
+
+		u, err := endpointURL(instanceURL, path, query)
+		if err != nil {
+			return nil, err
+		}
+		var page []Status
+		err = c.get(ctx, u, &page)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		result = append(result, page...)
+	}
+	if len(result) > maxStatuses {
+		result = result[:maxStatuses]
+	}
+	return result, nil
+}
+
+func endpointURL(instanceURL, path string, query url.Values) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(instanceURL))
+	if err != nil {
+		return nil, fmt.Errorf("invalid instance URL %#v: %w", instanceURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid instance URL %#v: unsupported scheme", instanceURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	u.RawPath = ""
+	u.RawQuery = query.Encode()
+	return u, nil
+}
+
+func (c *Client) get(ctx context.Context, u *url.URL, v interface{}) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating HTTP request for %#v: %w", u.String(), err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error performing request for %#v: %w", u.String(), err)
+	}
+	defer func() {
+		if e := resp.Body.Close(); e != nil && err == nil {
+			err = fmt.Errorf("error closing response body: %w", e)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request for %#v returned status code %d", u.String(), resp.StatusCode)
+	}
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v)
+	if err != nil {
+		return fmt.Errorf("error decoding JSON response for %#v: %w", u.String(), err)
+	}
+	return nil
+}
+
+// PermanentURL returns the URL of the HTML representation of the status,
+// or its URI, if the former is missing.
+func (s Status) PermanentURL() string {
+	if len(s.URL) > 0 {
+		return s.URL
+	}
+	return s.URI
+}
+
+// Text returns the plain text of the status, converted from its HTML
+// Content. The spoiler text (content warning), if any, is prepended.
+func (s Status) Text() string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s.Content))
+	if err != nil {
+		return strings.TrimSpace(s.SpoilerText)
+	}
+	doc.Find("br").ReplaceWithHtml("\n")
+
+	var paragraphs []string
+	if p := strings.TrimSpace(s.SpoilerText); len(p) > 0 {
+		paragraphs = append(paragraphs, p)
+	}
+	ps := doc.Find("p")
+	if ps.Length() == 0 {
+		ps = doc.Find("body")
+	}
+	ps.Each(func(_ int, p *goquery.Selection) {
+		if text := strings.TrimSpace(p.Text()); len(text) > 0 {
+			paragraphs = append(paragraphs, text)
+		}
+	})
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// Links returns the absolute HTTP(S) URLs linked by the status, excluding
+// mentions and hashtags, followed by the URL of its preview card, if any.
+// Duplicates are removed.
+func (s Status) Links() []string {
+	var links []string
+	seen := make(map[string]struct{})
+	add := func(rawURL string) {
+		u, err := url.Parse(strings.TrimSpace(rawURL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			return
+		}
+		v := u.String()
+		if _, ok := seen[v]; ok {
+			return
+		}
+		seen[v] = struct{}{}
+		links = append(links, v)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s.Content))
+	if err == nil {
+		doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+			if a.HasClass("mention") || a.HasClass("hashtag") || a.HasClass("u-url") {
+				return
+			}
+			if rel, _ := a.Attr("rel"); strings.Contains(" "+rel+" ", " tag ") {
+				return
+			}
+			href, _ := a.Attr("href")
+			add(href)
+		})
+	}
+	if s.Card != nil {
+		add(s.Card.URL)
+	}
+	return links
+}
+
+// ImageURL returns the URL of the first image attached to the status, or
+// an empty string.
+func (s Status) ImageURL() string {
+	for _, m := range s.MediaAttachments {
+		if m.Type == "image" && len(m.URL) > 0 {
+			return m.URL
+		}
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mastodon
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_LookupAccount(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/accounts/lookup", r.URL.Path)
+		assert.Equal(t, "test-agent", r.Header.Get("User-Agent"))
+		if r.URL.Query().Get("acct") != "foo@example.com" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"id": "42", "username": "foo", "acct": "foo@example.com", "url": "https://example.com/@foo"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(5*time.Second, "test-agent")
+
+	account, err := client.LookupAccount(context.Background(), server.URL+"/", "@foo@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, &Account{
+		ID:       "42",
+		Username: "foo",
+		Acct:     "foo@example.com",
+		URL:      "https://example.com/@foo",
+	}, account)
+
+	_, err = client.LookupAccount(context.Background(), server.URL, "bar")
+	assert.Error(t, err)
+}
+
+func TestClient_AccountStatuses(t *testing.T) {
+	t.Parallel()
+
+	// The server has 100 statuses, with IDs from 100 down to 1.
+	var limits []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/accounts/42/statuses", r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("exclude_reblogs"))
+
+		q := r.URL.Query()
+		limits = append(limits, q.Get("limit"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		maxID := 101
+		if v := q.Get("max_id"); len(v) > 0 {
+			maxID, _ = strconv.Atoi(v)
+		}
+
+		var items []string
+		for id := maxID - 1; id > 0 && len(items) < limit; id-- {
+			items = append(items, fmt.Sprintf(`{"id": "%d", "created_at": "2021-11-20T10:00:00.000Z"}`, id))
+		}
+		_, _ = fmt.Fprintf(w, "[%s]", strings.Join(items, ","))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(5*time.Second, "test-agent")
+
+	t.Run("with limit", func(t *testing.T) {
+		limits = nil
+		statuses, err := client.AccountStatuses(context.Background(), server.URL, "42", 90)
+		require.NoError(t, err)
+		require.Len(t, statuses, 90)
+		assert.Equal(t, "100", statuses[0].ID)
+		assert.Equal(t, "11", statuses[89].ID)
+		assert.Equal(t, []string{"40", "40", "10"}, limits)
+	})
+
+	t.Run("until the end of the timeline", func(t *testing.T) {
+		limits = nil
+		statuses, err := client.AccountStatuses(context.Background(), server.URL, "42", 200)
+		require.NoError(t, err)
+		require.Len(t, statuses, 100)
+		assert.Equal(t, "1", statuses[99].ID)
+		assert.Equal(t, []string{"40", "40", "40", "40"}, limits)
+	})
+}
+
+func TestClient_HashtagStatuses(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/timelines/tag/golang", r.URL.Path)
+		if len(r.URL.Query().Get("max_id")) > 0 {
+			_, _ = fmt.Fprint(w, `[]`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `[{"id": "2", "language": "en"}, {"id": "1", "language": null}]`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient(5*time.Second, "test-agent")
+
+	statuses, err := client.HashtagStatuses(context.Background(), server.URL, "#golang", 100)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "en", statuses[0].Language)
+	assert.Equal(t, "", statuses[1].Language)
+
+	server.Close()
+	_, err = client.HashtagStatuses(context.Background(), server.URL, "golang", 100)
+	assert.Error(t, err)
+}
+
+const sampleContent = `<p>Hello <span class="h-card"><a href="https://example.com/@bar" class="u-url mention">@<span>bar</span></a></span>,<br>read this: ` +
+	`<a href="https://news.example.org/article?id=1" rel="nofollow noopener noreferrer" target="_blank"><span class="invisible">https://</span><span class="ellipsis">news.example.org/article</span><span class="invisible">?id=1</span></a></p>` +
+	`<p><a href="https://example.com/tags/golang" class="mention hashtag" rel="tag">#<span>golang</span></a> <a href="mailto:a@example.com">mail</a> <a href="https://news.example.org/article?id=1">again</a></p>`
+
+func TestStatus_Text(t *testing.T) {
+	t.Parallel()
+
+	s := Status{Content: sampleContent}
+	assert.Equal(t, "Hello @bar,\nread this: https://news.example.org/article?id=1\n\n#golang mail again", s.Text())
+
+	s.SpoilerText = " CW "
+	assert.Equal(t, "CW\n\nHello @bar,\nread this: https://news.example.org/article?id=1\n\n#golang mail again", s.Text())
+
+	assert.Equal(t, "Plain text", Status{Content: "Plain text"}.Text())
+}
+
+func TestStatus_Links(t *testing.T) {
+	t.Parallel()
+
+	s := Status{
+		Content: sampleContent,
+		Card:    &Card{URL: "https://blog.example.net/post"},
+	}
+	assert.Equal(t, []string{
+		"https://news.example.org/article?id=1",
+		"https://blog.example.net/post",
+	}, s.Links())
+
+	assert.Nil(t, Status{Content: "<p>No links</p>"}.Links())
+}
+
+func TestStatus_PermanentURL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "https://a.example/@x/1", Status{URL: "https://a.example/@x/1", URI: "https://a.example/users/x/statuses/1"}.PermanentURL())
+	assert.Equal(t, "https://a.example/users/x/statuses/1", Status{URI: "https://a.example/users/x/statuses/1"}.PermanentURL())
+}
+
+func TestStatus_ImageURL(t *testing.T) {
+	t.Parallel()
+
+	s := Status{MediaAttachments: []MediaAttachment{
+		{Type: "video", URL: "https://a.example/v.mp4"},
+		{Type: "image", URL: "https://a.example/i.png"},
+	}}
+	assert.Equal(t, "https://a.example/i.png", s.ImageURL())
+	assert.Equal(t, "", Status{}.ImageURL())
+}
@@ -0,0 +1,29 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"database/sql"
+)
+
+// APIItem extends a WebResource representing an item read from an
+// APISource.
+type APIItem struct {
+	Model
+
+	// Association to the APISource this item belongs to.
+	APISourceID uint `gorm:"not null;index"`
+
+	// WebResourceID allows the has-one relation with a WebResource.
+	WebResourceID uint `gorm:"not null;uniqueIndex"`
+
+	Title       string `gorm:"not null"`
+	Description string `gorm:"not null"`
+
+	// Language is the forced language of the APISource, or the language
+	// detected from the Title. It is empty when unknown.
+	Language    string `gorm:"not null"`
+	PublishedAt sql.NullTime
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// APISourcePagination acts as an enumeration type to identify how the
+// pages of an APISource are requested.
+type APISourcePagination string
+
+const (
+	// NoPagination means that a single request is performed.
+	NoPagination APISourcePagination = "none"
+
+	// PagePagination identifies a page number query parameter, starting
+	// from PaginationStart and incremented by one for each page.
+	PagePagination APISourcePagination = "page"
+
+	// OffsetPagination identifies an offset query parameter, starting from
+	// PaginationStart and incremented by the number of items of each page.
+	OffsetPagination APISourcePagination = "offset"
+
+	// CursorPagination identifies a cursor query parameter, whose value is
+	// read from each response (at NextCursorPath) for requesting the next
+	// page.
+	CursorPagination APISourcePagination = "cursor"
+)
+
+// APISource represents a generic JSON HTTP API, whose responses list web
+// resources (for example, the articles of a CMS or of a press-release
+// service). The items are extracted from the responses with JSONPath
+// expressions.
+type APISource struct {
+	Model
+
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	// The unique name of the source.
+	Name string `gorm:"not null;uniqueIndex"`
+
+	// The URL of the API endpoint.
+	URL string `gorm:"not null"`
+
+	// The HTTP method, either "GET" or "POST".
+	Method string `gorm:"not null;default:'GET'"`
+
+	// Headers is a JSON object of additional HTTP request headers (e.g.
+	// for authentication), with string values.
+	Headers datatypes.JSON `gorm:"not null;default:'{}'"`
+
+	// Body is the JSON request body, for POST requests.
+	Body sql.NullString
+
+	// ItemsPath is the JSONPath of the array of items in the response. When
+	// empty, the response itself must be the array.
+	ItemsPath string `gorm:"not null;default:''"`
+
+	// The JSONPaths of the fields of each item, relative to the item
+	// itself. Only URLPath is required.
+	URLPath         string `gorm:"not null"`
+	TitlePath       string `gorm:"not null;default:''"`
+	DescriptionPath string `gorm:"not null;default:''"`
+	PublishedAtPath string `gorm:"not null;default:''"`
+
+	// PublishedAtLayout is the Go time layout of the published time, or
+	// "unix" / "unix_ms" for timestamps in seconds / milliseconds. When
+	// empty, some common formats are tried.
+	PublishedAtLayout string `gorm:"not null;default:''"`
+
+	Pagination APISourcePagination `gorm:"not null;default:'none'"`
+
+	// PaginationParam is the name of the page, offset or cursor query
+	// parameter.
+	PaginationParam string `gorm:"not null;default:''"`
+
+	// PaginationStart is the first page number or offset.
+	PaginationStart int `gorm:"not null;default:0"`
+
+	// NextCursorPath is the JSONPath of the next page cursor in the
+	// response, for cursor pagination.
+	NextCursorPath string `gorm:"not null;default:''"`
+
+	// MaxPages is the maximum number of pages requested each time the
+	// source is visited.
+	MaxPages int `gorm:"not null;default:1"`
+
+	// The system will look for new items from this source only when it is
+	// Enabled. Otherwise, the source is simply ignored.
+	Enabled bool `gorm:"not null;index"`
+
+	// The date and time when this source was last visited to successfully
+	// retrieve its content (items), store it, and schedule further
+	// processing jobs.
+	LastRetrievedAt sql.NullTime `gorm:"index"`
+
+	// Counter of consecutive fetching failures.
+	FailuresCount int `gorm:"not null;default:0"`
+
+	// When FailuresCount is not 0, this field should contain the error message
+	// that caused the last failure. It is mostly useful for manual inspection.
+	LastError sql.NullString
+
+	// When Language is set, it is assigned to all items, instead of
+	// detecting the language of each item.
+	Language sql.NullString
+
+	// An APISource has many models.APIItem models.
+	APIItems []APIItem `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// HeadersAsMap converts the Headers to a map.
+func (s APISource) HeadersAsMap() (map[string]string, error) {
+	headers := make(map[string]string)
+	if len(s.Headers) == 0 {
+		return headers, nil
+	}
+	err := json.Unmarshal(s.Headers, &headers)
+	if err != nil {
+		return nil, fmt.Errorf("error converting APISource.Headers to map: %w", err)
+	}
+	return headers, nil
+}
+
+// SetHeaders sets the Headers from a map.
+func (s *APISource) SetHeaders(headers map[string]string) error {
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	data, err := json.Marshal(headers)
+	if err != nil {
+		return fmt.Errorf("error setting APISource.Headers: %w", err)
+	}
+	s.Headers = data
+	return nil
+}
@@ -0,0 +1,20 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+// CompletedStage records that a WebArticle was successfully processed by
+// a certain stage of the pipeline (usually a worker).
+//
+// It allows the retrieval of the WebArticles which already passed a given
+// stage, for example when a client resumes a streaming subscription.
+type CompletedStage struct {
+	Model
+
+	// Association to the WebArticle.
+	WebArticleID uint `gorm:"not null;index;index:idx_web_article_id_stage,unique"`
+
+	// The name of the pipeline stage, such as "duplicate_detector".
+	Stage string `gorm:"not null;index:idx_web_article_id_stage,unique"`
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"gorm.io/datatypes"
+	"time"
+)
+
+// DomainPolicy represents the rules to follow when scraping the web pages
+// of a domain, including all its subdomains. It takes precedence over a
+// policy for the same domain from the configuration.
+type DomainPolicy struct {
+	Model
+
+	// Domain is the lowercase domain name (e.g. "example.com").
+	Domain string `gorm:"not null;uniqueIndex"`
+
+	// UserAgent, when not empty, replaces the default User-Agent header.
+	UserAgent string `gorm:"not null;default:''"`
+
+	// Headers is a JSON object of additional HTTP request headers.
+	Headers datatypes.JSON `gorm:"not null;default:'{}'"`
+
+	// Cookies is a JSON object of cookies (name to value) sent with each
+	// request, for example to accept a consent banner.
+	Cookies datatypes.JSON `gorm:"not null;default:'{}'"`
+
+	// ProxyURL, when not empty, is the URL of the proxy to use (e.g.
+	// "http://proxy.example.com:3128" or "socks5://127.0.0.1:1080").
+	ProxyURL string `gorm:"not null;default:''"`
+
+	// MinRequestInterval is the minimum interval between two requests to
+	// the same host. Zero means the default interval from the configuration.
+	MinRequestInterval time.Duration `gorm:"not null;default:0"`
+
+	// IgnoreRobotsTxt, when valid, tells whether the rules from the
+	// robots.txt files are ignored. NULL means the default setting from the
+	// configuration.
+	IgnoreRobotsTxt sql.NullBool
+
+	// InsecureSkipVerify, when valid, tells whether the verification of TLS
+	// certificates is disabled. NULL means the default setting from the
+	// configuration.
+	InsecureSkipVerify sql.NullBool
+}
+
+// HeadersAsMap converts the Headers to a map.
+func (p DomainPolicy) HeadersAsMap() (map[string]string, error) {
+	m, err := jsonToStringMap(p.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("error converting DomainPolicy.Headers to map: %w", err)
+	}
+	return m, nil
+}
+
+// SetHeaders sets the Headers from a map.
+func (p *DomainPolicy) SetHeaders(headers map[string]string) (err error) {
+	p.Headers, err = stringMapToJSON(headers)
+	if err != nil {
+		return fmt.Errorf("error setting DomainPolicy.Headers: %w", err)
+	}
+	return nil
+}
+
+// CookiesAsMap converts the Cookies to a map.
+func (p DomainPolicy) CookiesAsMap() (map[string]string, error) {
+	m, err := jsonToStringMap(p.Cookies)
+	if err != nil {
+		return nil, fmt.Errorf("error converting DomainPolicy.Cookies to map: %w", err)
+	}
+	return m, nil
+}
+
+// SetCookies sets the Cookies from a map.
+func (p *DomainPolicy) SetCookies(cookies map[string]string) (err error) {
+	p.Cookies, err = stringMapToJSON(cookies)
+	if err != nil {
+		return fmt.Errorf("error setting DomainPolicy.Cookies: %w", err)
+	}
+	return nil
+}
+
+func jsonToStringMap(data datatypes.JSON) (map[string]string, error) {
+	m := make(map[string]string)
+	if len(data) == 0 {
+		return m, nil
+	}
+	err := json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func stringMapToJSON(m map[string]string) (datatypes.JSON, error) {
+	if m == nil {
+		m = make(map[string]string)
+	}
+	return json.Marshal(m)
+}
@@ -14,6 +14,7 @@ type ExtractedInfo struct {
 
 	// Association to the InfoExtractionRule.
 	InfoExtractionRuleID uint `gorm:"not null;index;index:idx_web_article_id_info_extraction_rule_id,unique"`
+	InfoExtractionRule   *InfoExtractionRule
 
 	Text       string  `gorm:"not null"`
 	Confidence float32 `gorm:"not null"`
@@ -6,7 +6,10 @@ package models
 
 import (
 	"database/sql"
+	"fmt"
+	"github.com/jackc/pgtype"
 	"gorm.io/gorm"
+	"strings"
 )
 
 // Feed is a model representing an RSS or Atom feed.
@@ -34,6 +37,66 @@ type Feed struct {
 	// that caused the last failure. It is mostly useful for manual inspection.
 	LastError sql.NullString
 
+	// The title and the website URL, as read from the feed itself the last
+	// time it was successfully retrieved.
+	Title   sql.NullString
+	SiteURL sql.NullString
+
+	// Free-form tags, for organizing the feeds.
+	Tags pgtype.TextArray `gorm:"type:text[];not null;default:'{}'"`
+
+	// When Language is set, it is assigned to all feed items, instead of
+	// detecting the language of each item.
+	Language sql.NullString
+
+	// FetchIntervalSeconds is the minimum time between two consecutive
+	// schedulings of this feed. When null, the global feed scheduler time
+	// interval is used.
+	FetchIntervalSeconds sql.NullInt64
+
+	// The date and time when the jobs for this feed were last scheduled.
+	LastScheduledAt sql.NullTime `gorm:"index"`
+
+	// The validators of the last successful response, sent back with
+	// conditional requests (If-None-Match and If-Modified-Since headers).
+	ETag         sql.NullString
+	LastModified sql.NullString
+
+	// ItemsPerDay is the observed publication rate of the feed items, used
+	// for adapting the scheduling interval. It is null when unknown.
+	ItemsPerDay sql.NullFloat64
+
 	// A Feed has many models.FeedItem models.
 	FeedItems []FeedItem `gorm:"constraint:OnDelete:CASCADE"`
 }
+
+// TagsAsStringSlice converts the Tags to a slice of strings.
+func (f Feed) TagsAsStringSlice() ([]string, error) {
+	var tags []string
+	err := f.Tags.AssignTo(&tags)
+	if err != nil {
+		return nil, fmt.Errorf("error converting Feed.Tags to []string: %w", err)
+	}
+	return tags, nil
+}
+
+// SetTags sets the Tags from a slice of strings, trimming them and removing
+// the empty and duplicate ones.
+func (f *Feed) SetTags(tags []string) error {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if _, ok := seen[tag]; ok || len(tag) == 0 {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+
+	err := f.Tags.Set(normalized)
+	if err != nil {
+		return fmt.Errorf("error setting Feed.Tags: %w", err)
+	}
+	return nil
+}
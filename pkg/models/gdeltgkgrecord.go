@@ -0,0 +1,65 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"database/sql"
+	"github.com/jackc/pgtype"
+	"gorm.io/datatypes"
+	"time"
+)
+
+// GDELTGKGRecord represents a record of the GDELT Global Knowledge Graph
+// (GKG), and it extends the WebResource of the source document it
+// describes.
+type GDELTGKGRecord struct {
+	Model
+
+	// WebResourceID allows the has-one relation with a WebResource.
+	WebResourceID uint `gorm:"not null;uniqueIndex"`
+
+	// GKGRecordID is the globally unique identifier of the GKG record.
+	GKGRecordID string `gorm:"not null;uniqueIndex"`
+
+	// Date is the time the document was processed by GDELT.
+	Date time.Time `gorm:"not null"`
+
+	// SourceCommonName is a human-friendly identifier of the source.
+	SourceCommonName sql.NullString
+
+	// Themes is the list of GKG themes found in the document.
+	Themes pgtype.TextArray `gorm:"type:text[];not null"`
+
+	// Persons is the list of person names found in the document.
+	Persons pgtype.TextArray `gorm:"type:text[];not null"`
+
+	// Organizations is the list of organization names found in the
+	// document.
+	Organizations pgtype.TextArray `gorm:"type:text[];not null"`
+
+	// Locations is a JSON array of the locations found in the document
+	// (see gdeltdata.GKGLocation).
+	Locations datatypes.JSON `gorm:"not null;default:'[]'"`
+
+	// Tone is the average tone of the document, from -100 (extremely
+	// negative) to +100 (extremely positive).
+	Tone float64 `gorm:"not null"`
+
+	// PositiveScore is the percentage of words with positive connotation.
+	PositiveScore float64 `gorm:"not null"`
+
+	// NegativeScore is the percentage of words with negative connotation.
+	NegativeScore float64 `gorm:"not null"`
+
+	// Polarity is the percentage of words with emotional connotation.
+	Polarity float64 `gorm:"not null"`
+
+	// WordCount is the total number of words in the document.
+	WordCount int `gorm:"not null"`
+
+	// SharingImage is the URL of the image suggested for sharing the
+	// document on social media.
+	SharingImage sql.NullString
+}
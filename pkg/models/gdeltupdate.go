@@ -0,0 +1,31 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// GDELTUpdate records that a GDELT data file, published every 15 minutes,
+// was processed, or that it is invalid and must not be processed again.
+type GDELTUpdate struct {
+	Model
+
+	// Type identifies the kind of data file, such as "export.CSV.zip" for
+	// the events.
+	Type string `gorm:"not null;index:idx_gdelt_update_type_time,unique"`
+
+	// Time is the GDELT update timestamp of the file.
+	Time time.Time `gorm:"not null;index:idx_gdelt_update_type_time,unique"`
+
+	// RecordsCount is the number of records read from the file.
+	RecordsCount int `gorm:"not null"`
+
+	// Failure, when valid, describes why the file is invalid (for example,
+	// a checksum mismatch or a corrupted archive), so no records were
+	// read from it.
+	Failure sql.NullString
+}
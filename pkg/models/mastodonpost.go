@@ -0,0 +1,34 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import "time"
+
+// MastodonPost extends a WebResource which is the item of a MastodonSource.
+type MastodonPost struct {
+	Model
+
+	// MastodonSourceID is the association to the MastodonSource this item
+	// belongs to.
+	MastodonSourceID uint `gorm:"not null;index"`
+
+	// WebResourceID allows the has-one relation with a WebResource.
+	WebResourceID uint `gorm:"not null;uniqueIndex"`
+
+	// UpstreamID is the ActivityPub URI of the post, which is unique across
+	// all instances.
+	UpstreamID  string    `gorm:"not null;uniqueIndex"`
+	Text        string    `gorm:"not null"`
+	PublishedAt time.Time `gorm:"not null"`
+
+	// Username is the account of the author, as "username@domain" for
+	// accounts of other instances.
+	Username   string `gorm:"not null;index"`
+	AccountURL string `gorm:"not null"`
+
+	// Links is the has-many relation with the WebResources shared by the
+	// post, when links are followed.
+	Links []MastodonPostLink `gorm:"constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,19 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+// MastodonPostLink records that a MastodonPost shared the URL of a
+// WebResource (usually a news article), allowing to measure how widely the
+// latter spreads on Mastodon.
+type MastodonPostLink struct {
+	Model
+
+	// MastodonPostID is the association to the MastodonPost which shared
+	// the link.
+	MastodonPostID uint `gorm:"not null;index:idx_mastodon_post_link_post_web_resource,unique"`
+
+	// WebResourceID is the association to the linked WebResource.
+	WebResourceID uint `gorm:"not null;index:idx_mastodon_post_link_post_web_resource,unique;index"`
+}
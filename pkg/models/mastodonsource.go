@@ -0,0 +1,63 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"database/sql"
+	"gorm.io/gorm"
+)
+
+// MastodonSourceType acts as an enumeration type to identify different kind
+// of Mastodon sources.
+type MastodonSourceType string
+
+const (
+	// AccountMastodonSource identifies a Mastodon source linked to the
+	// statuses (outbox) of an account.
+	AccountMastodonSource MastodonSourceType = "account"
+
+	// HashtagMastodonSource identifies a Mastodon source linked to the
+	// public timeline of a hashtag.
+	HashtagMastodonSource MastodonSourceType = "hashtag"
+)
+
+// MastodonSource represents the source of MastodonPosts / WebResources,
+// read from the public REST API of a Mastodon (or Mastodon-compatible
+// ActivityPub) instance.
+type MastodonSource struct {
+	Model
+
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	// InstanceURL is the base URL of the instance, such as
+	// "https://mastodon.social".
+	InstanceURL string `gorm:"not null;index:idx_mastodon_source_instance_type_text,unique"`
+
+	Type MastodonSourceType `gorm:"not null;index:idx_mastodon_source_instance_type_text,unique"`
+
+	// Text is either an account (username, or "username@domain" for
+	// accounts of other instances) or a hashtag (without "#"), depending on
+	// the Type.
+	Text string `gorm:"not null;index:idx_mastodon_source_instance_type_text,unique"`
+
+	// The system will look for new posts from this source only when it is
+	// Enabled. Otherwise, the mastodon source is simply ignored.
+	Enabled bool `gorm:"not null;index"`
+
+	// The date and time when this source was last visited to successfully
+	// retrieve its content (posts), store it, and schedule further
+	// processing jobs.
+	LastRetrievedAt sql.NullTime `gorm:"index"`
+
+	// Counter of consecutive fetching failures.
+	FailuresCount int `gorm:"not null;default:0"`
+
+	// When FailuresCount is not 0, this field should contain the error message
+	// that caused the last failure. It is mostly useful for manual inspection.
+	LastError sql.NullString
+
+	// MastodonPosts is the has-many relation with MastodonPost models.
+	MastodonPosts []MastodonPost `gorm:"constraint:OnDelete:CASCADE"`
+}
@@ -10,20 +10,38 @@ import "gorm.io/gorm"
 var allModels = []interface{}{
 	WebResource{},
 	WebArticle{},
+	WebArticleContent{},
+	Translation{},
 	Feed{},
 	FeedItem{},
 	GDELTEvent{},
+	GDELTGKGRecord{},
+	GDELTUpdate{},
+	Sitemap{},
+	SitemapItem{},
+	APISource{},
+	APIItem{},
 	TwitterSource{},
 	Tweet{},
+	TweetLink{},
+	MastodonSource{},
+	MastodonPost{},
+	MastodonPostLink{},
+	NewsletterSource{},
+	NewsletterMessage{},
+	NewsletterMessageLink{},
+	DomainPolicy{},
 	PendingJob{},
 	ZeroShotClass{},
 	TextClass{},
 	Vector{},
+	Story{},
 	SimilarityInfo{},
 	ExtractedInfo{},
 	ZeroShotHypothesisTemplate{},
 	ZeroShotHypothesisLabel{},
 	InfoExtractionRule{},
+	CompletedStage{},
 }
 
 // AutoMigrate performs the automatic migration of all GORM models.
@@ -0,0 +1,37 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import "time"
+
+// NewsletterMessage represents an email message read from a
+// NewsletterSource. Messages are identified by their Message-ID within
+// their source, so that each one is processed only once per source.
+type NewsletterMessage struct {
+	Model
+
+	// NewsletterSourceID is the association to the NewsletterSource this
+	// message belongs to.
+	NewsletterSourceID uint `gorm:"not null;index;index:idx_newsletter_message_source_message_id,unique"`
+
+	// WebResourceID allows the has-one relation with a WebResource. It is
+	// only set for messages turned into a WebArticle.
+	WebResourceID *uint `gorm:"uniqueIndex"`
+
+	// MessageID is the "Message-ID" header, without angle brackets.
+	MessageID string `gorm:"not null;index:idx_newsletter_message_source_message_id,unique"`
+
+	// UID is the IMAP UID of the message in the source folder.
+	UID int64 `gorm:"not null"`
+
+	Subject     string    `gorm:"not null"`
+	FromAddress string    `gorm:"not null;index"`
+	FromName    string    `gorm:"not null;default:''"`
+	PublishedAt time.Time `gorm:"not null"`
+
+	// Links is the has-many relation with the WebResources linked by the
+	// message, when links are followed.
+	Links []NewsletterMessageLink `gorm:"constraint:OnDelete:CASCADE"`
+}
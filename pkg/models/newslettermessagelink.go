@@ -0,0 +1,18 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+// NewsletterMessageLink associates a NewsletterMessage with a WebResource
+// linked by the message.
+type NewsletterMessageLink struct {
+	Model
+
+	// NewsletterMessageID is the association to the NewsletterMessage which
+	// contains the link.
+	NewsletterMessageID uint `gorm:"not null;index:idx_newsletter_message_link_message_web_resource,unique"`
+
+	// WebResourceID is the association to the linked WebResource.
+	WebResourceID uint `gorm:"not null;index:idx_newsletter_message_link_message_web_resource,unique;index"`
+}
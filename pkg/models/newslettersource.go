@@ -0,0 +1,91 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"database/sql"
+	"gorm.io/gorm"
+)
+
+// NewsletterSourceMode acts as an enumeration type to identify how the
+// messages of a NewsletterSource are turned into web resources.
+type NewsletterSourceMode string
+
+const (
+	// ArticleNewsletterMode means that each message becomes a WebArticle,
+	// whose title and content are the subject and the text of the message.
+	ArticleNewsletterMode NewsletterSourceMode = "article"
+
+	// LinksNewsletterMode means that a new WebResource is created for each
+	// link found in a message, to be scraped later.
+	LinksNewsletterMode NewsletterSourceMode = "links"
+)
+
+// NewsletterSource represents a folder of an IMAP mailbox which receives
+// email newsletters.
+type NewsletterSource struct {
+	Model
+
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	// The unique name of the source.
+	Name string `gorm:"not null;uniqueIndex"`
+
+	// Host and Port of the IMAP server.
+	Host string `gorm:"not null"`
+	Port int    `gorm:"not null;default:993"`
+
+	// DisableTLS disables implicit TLS, which should only be done for
+	// local servers.
+	DisableTLS bool `gorm:"not null;default:false"`
+
+	// Username and Password are the credentials of the mailbox. The
+	// password is stored as plain text, so a dedicated mailbox or an
+	// application-specific password should be used.
+	Username string `gorm:"not null"`
+	Password string `gorm:"not null"`
+
+	// Folder is the name of the mailbox folder to read.
+	Folder string `gorm:"not null;default:'INBOX'"`
+
+	// When SenderFilter is not empty, only the messages whose "From"
+	// header contains it are read.
+	SenderFilter string `gorm:"not null;default:''"`
+
+	Mode NewsletterSourceMode `gorm:"not null;default:'article'"`
+
+	// LinkPattern is a regular expression which, when not empty, must
+	// match the links followed with LinksNewsletterMode.
+	LinkPattern string `gorm:"not null;default:''"`
+
+	// UIDValidity and LastUID identify the last message read from the
+	// folder. Only the messages with a greater UID are read, unless the
+	// UIDValidity of the folder changes.
+	UIDValidity int64 `gorm:"not null;default:0"`
+	LastUID     int64 `gorm:"not null;default:0"`
+
+	// The system will look for new messages from this source only when it
+	// is Enabled. Otherwise, the source is simply ignored.
+	Enabled bool `gorm:"not null;index"`
+
+	// The date and time when this source was last visited to successfully
+	// retrieve its messages, store them, and schedule further processing
+	// jobs.
+	LastRetrievedAt sql.NullTime `gorm:"index"`
+
+	// Counter of consecutive fetching failures.
+	FailuresCount int `gorm:"not null;default:0"`
+
+	// When FailuresCount is not 0, this field should contain the error message
+	// that caused the last failure. It is mostly useful for manual inspection.
+	LastError sql.NullString
+
+	// When Language is set, it is assigned to all the articles, instead of
+	// detecting the language of each message.
+	Language sql.NullString
+
+	// A NewsletterSource has many models.NewsletterMessage models.
+	NewsletterMessages []NewsletterMessage `gorm:"constraint:OnDelete:CASCADE"`
+}
@@ -15,6 +15,10 @@ package models
 // If a WebArticle has a SimilarityInfo attached with no Parent (and no
 // Distance), it means that the similarity detection task was successfully
 // performed and no prior similar entities were found.
+//
+// StoryID refers to the Story the WebArticle belongs to. It might be nil
+// only for SimilarityInfo models created before Story clustering was
+// introduced.
 type SimilarityInfo struct {
 	Model
 
@@ -25,4 +29,7 @@ type SimilarityInfo struct {
 	Parent   *WebArticle
 
 	Distance *float32
+
+	StoryID *uint  `gorm:"index"`
+	Story   *Story `gorm:"constraint:OnDelete:SET NULL"`
 }
@@ -0,0 +1,45 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"database/sql"
+	"gorm.io/gorm"
+)
+
+// Sitemap is a model representing an XML sitemap, a sitemap index, or a
+// Google News sitemap of a website.
+type Sitemap struct {
+	Model
+
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+
+	// The unique URL of the sitemap.
+	URL string `gorm:"not null;uniqueIndex"`
+
+	// The system will look for new URLs from this sitemap only when it is
+	// Enabled. Otherwise, the sitemap is simply ignored.
+	Enabled bool `gorm:"not null;index"`
+
+	// The date and time when this sitemap was last visited to successfully
+	// retrieve its content (URLs), store it, and schedule further
+	// processing jobs.
+	LastRetrievedAt sql.NullTime `gorm:"index"`
+
+	// Counter of consecutive fetching failures.
+	FailuresCount int `gorm:"not null;default:0"`
+
+	// When FailuresCount is not 0, this field should contain the error message
+	// that caused the last failure. It is mostly useful for manual inspection.
+	LastError sql.NullString
+
+	// When Language is set, it is assigned to all sitemap items, instead of
+	// using the news publication language, or detecting the language of
+	// each item.
+	Language sql.NullString
+
+	// A Sitemap has many models.SitemapItem models.
+	SitemapItems []SitemapItem `gorm:"constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"database/sql"
+)
+
+// SitemapItem extends a WebResource representing a URL listed in a Sitemap.
+type SitemapItem struct {
+	Model
+
+	// Association to the Sitemap this item belongs to.
+	SitemapID uint `gorm:"not null;index"`
+
+	// WebResourceID allows the has-one relation with a WebResource.
+	WebResourceID uint `gorm:"not null;uniqueIndex"`
+
+	// Title is the news title, from Google News sitemaps. It is empty for
+	// plain sitemaps.
+	Title string `gorm:"not null"`
+
+	// Language is the forced language of the Sitemap, or the news
+	// publication language, or the language detected from the Title. It is
+	// empty when unknown.
+	Language string `gorm:"not null"`
+
+	// PublishedAt is the news publication date, from Google News sitemaps,
+	// or the last modification time of the URL otherwise.
+	PublishedAt sql.NullTime
+}
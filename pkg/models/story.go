@@ -0,0 +1,48 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"github.com/jackc/pgtype"
+	"time"
+)
+
+// Story is a cluster of similar WebArticles, maintained by the duplicate
+// detection task.
+//
+// A WebArticle with no parent (see SimilarityInfo) starts a new Story, and
+// becomes its representative. A WebArticle with a parent joins the Story of
+// its parent. The membership is stored in SimilarityInfo.StoryID.
+type Story struct {
+	Model
+
+	// RepresentativeID is the ID of the WebArticle which started the Story.
+	RepresentativeID uint        `gorm:"not null;uniqueIndex"`
+	Representative   *WebArticle `gorm:"constraint:OnDelete:CASCADE"`
+
+	// FirstSeenAt is the earliest PublishDate among all member WebArticles.
+	FirstSeenAt time.Time `gorm:"not null;index"`
+
+	// LastSeenAt is the latest PublishDate among all member WebArticles.
+	LastSeenAt time.Time `gorm:"not null;index"`
+
+	// MembersCount is the number of WebArticles belonging to the Story.
+	MembersCount int `gorm:"not null;index"`
+
+	// CountryCodes is the set of all distinct country codes of the member
+	// WebArticles (see WebArticle.CountryCode).
+	CountryCodes pgtype.TextArray `gorm:"type:text[];not null"`
+}
+
+// CountryCodesAsStringSlice converts Story.CountryCodes to []string.
+func (s Story) CountryCodesAsStringSlice() ([]string, error) {
+	var codes []string
+	err := s.CountryCodes.AssignTo(&codes)
+	if err != nil {
+		return nil, fmt.Errorf("error converting Story.CountryCodes to []string: %w", err)
+	}
+	return codes, nil
+}
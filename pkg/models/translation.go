@@ -0,0 +1,28 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+// Translation is the translation of a text field of a WebArticle into a
+// target language.
+//
+// The title translation into the first target language is also kept, for
+// historical reasons, in the WebArticle itself (see
+// WebArticle.TranslatedTitle).
+type Translation struct {
+	Model
+
+	// Association to the WebArticle whose field was translated.
+	WebArticleID uint `gorm:"not null;uniqueIndex:idx_translations_web_article_field_language"`
+
+	// Field is the translated text source (see config.TextSource).
+	Field string `gorm:"not null;uniqueIndex:idx_translations_web_article_field_language"`
+	// Language is the target language code.
+	Language string `gorm:"not null;uniqueIndex:idx_translations_web_article_field_language"`
+	// Text is the translated text.
+	Text string `gorm:"not null"`
+	// Provider is the name of the translation service which produced the
+	// translation.
+	Provider string `gorm:"not null"`
+}
@@ -21,4 +21,8 @@ type Tweet struct {
 	PublishedAt time.Time `gorm:"not null"`
 	Username    string    `gorm:"not null;index"`
 	UserID      string    `gorm:"not null;index"`
+
+	// Links is the has-many relation with the WebResources shared by the
+	// tweet, when links are followed.
+	Links []TweetLink `gorm:"constraint:OnDelete:CASCADE"`
 }
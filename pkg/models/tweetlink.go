@@ -0,0 +1,18 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+// TweetLink records that a Tweet shared the URL of a WebResource (usually
+// a news article), allowing to measure how widely the latter spreads on
+// Twitter.
+type TweetLink struct {
+	Model
+
+	// TweetID is the association to the Tweet which shared the link.
+	TweetID uint `gorm:"not null;index:idx_tweet_link_tweet_web_resource,unique"`
+
+	// WebResourceID is the association to the linked WebResource.
+	WebResourceID uint `gorm:"not null;index:idx_tweet_link_tweet_web_resource,unique;index"`
+}
@@ -48,6 +48,15 @@ type TwitterSource struct {
 	// that caused the last failure. It is mostly useful for manual inspection.
 	LastError sql.NullString
 
+	// LastTweetUpstreamID is the ID of the newest tweet read from this
+	// source. It acts as a high-water mark: on the following visits, only
+	// newer tweets are requested or read.
+	LastTweetUpstreamID sql.NullString
+
+	// LastTweetPublishedAt is the publication date and time of the tweet
+	// identified by LastTweetUpstreamID.
+	LastTweetPublishedAt sql.NullTime
+
 	// Tweets is the has-many relation with Tweet models.
 	Tweets []Tweet `gorm:"constraint:OnDelete:CASCADE"`
 }
@@ -27,6 +27,13 @@ type WebArticle struct {
 
 	CountryCode sql.NullString
 
+	// A WebArticle has one WebArticleContent, only if it was scraped from
+	// a web page.
+	Content *WebArticleContent `gorm:"constraint:OnDelete:CASCADE"`
+
+	// A WebArticle has many models.Translation models.
+	Translations []Translation `gorm:"constraint:OnDelete:CASCADE"`
+
 	// A WebArticle has many models.ZeroShotClass models.
 	ZeroShotClasses []ZeroShotClass `gorm:"constraint:OnDelete:CASCADE"`
 
@@ -41,4 +48,7 @@ type WebArticle struct {
 
 	// A WebArticle has one SimilarityInfo.
 	SimilarityInfo *SimilarityInfo `gorm:"constraint:OnDelete:CASCADE"`
+
+	// A WebArticle has many models.CompletedStage models.
+	CompletedStages []CompletedStage `gorm:"constraint:OnDelete:CASCADE"`
 }
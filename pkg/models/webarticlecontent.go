@@ -0,0 +1,62 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"database/sql"
+	"github.com/jackc/pgtype"
+)
+
+// ExtractionStrategy acts as an enumeration type to identify how the
+// content of a web page was extracted.
+type ExtractionStrategy string
+
+const (
+	// GooseExtraction means that the article was extracted from the page
+	// itself, with GoOse.
+	GooseExtraction ExtractionStrategy = "goose"
+
+	// AMPExtraction means that the article was extracted, with GoOse, from
+	// the AMP version of the page.
+	AMPExtraction ExtractionStrategy = "amp"
+
+	// JSONLDExtraction means that the article was recovered from a JSON-LD
+	// Article (or NewsArticle) block of the page.
+	JSONLDExtraction ExtractionStrategy = "json_ld"
+
+	// MetaTagsExtraction means that the article was recovered from the
+	// OpenGraph or Twitter Card meta tags of the page.
+	MetaTagsExtraction ExtractionStrategy = "meta_tags"
+
+	// FeedItemExtraction means that the article was created from the title
+	// of the feed item, either because no other strategy found a title, or
+	// because the page was a paywall or a consent wall (in which case the
+	// description of the feed item is used too).
+	FeedItemExtraction ExtractionStrategy = "feed_item"
+)
+
+// WebArticleContent holds the full content extracted from the web page of a
+// WebArticle. It is kept apart from the WebArticle, so that the most
+// frequently accessed table stays lean.
+type WebArticleContent struct {
+	Model
+
+	// Association to the WebArticle this content belongs to.
+	WebArticleID uint `gorm:"not null;uniqueIndex"`
+
+	// Body is the cleaned text of the article.
+	Body string `gorm:"not null"`
+
+	MetaDescription sql.NullString
+	CanonicalLink   sql.NullString
+
+	Authors  pgtype.TextArray `gorm:"type:text[];not null"`
+	Keywords pgtype.TextArray `gorm:"type:text[];not null"`
+
+	// ExtractionStrategy is the strategy which succeeded in extracting the
+	// article from the web page. It is empty for contents which do not
+	// come from a web page.
+	ExtractionStrategy ExtractionStrategy `gorm:"not null;default:'';index"`
+}
@@ -4,6 +4,98 @@
 
 package models
 
+import (
+	"database/sql"
+	"fmt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScrapeStatus acts as an enumeration type for the outcome of scraping a
+// WebResource.
+type ScrapeStatus string
+
+const (
+	// NotScraped is the status of WebResources which were not scraped
+	// yet, or which do not need scraping (e.g. tweets).
+	NotScraped ScrapeStatus = ""
+
+	// SucceededScrape means that a WebArticle was created.
+	SucceededScrape ScrapeStatus = "succeeded"
+
+	// SkippedScrape means that no WebArticle can be created from the
+	// resource, and the scraping is not retried.
+	SkippedScrape ScrapeStatus = "skipped"
+
+	// BlockedScrape means that a wall was served in place of the article.
+	BlockedScrape ScrapeStatus = "blocked"
+
+	// FailedScrape means that the scraping failed for a transient reason
+	// (e.g. a network error), so it can be retried later.
+	FailedScrape ScrapeStatus = "failed"
+
+	// RetryingScrape means that a new attempt of a failed scraping was
+	// scheduled.
+	RetryingScrape ScrapeStatus = "retrying"
+)
+
+// ScrapeReason acts as an enumeration type for the reason of a
+// ScrapeStatus.
+type ScrapeReason string
+
+const (
+	// PaywallScrapeReason means that the content is reserved for
+	// subscribers.
+	PaywallScrapeReason ScrapeReason = "paywall"
+
+	// ConsentWallScrapeReason means that the content is hidden until the
+	// cookies (or other privacy terms) are accepted.
+	ConsentWallScrapeReason ScrapeReason = "consent_wall"
+
+	// RobotsTxtScrapeReason means that the URL is disallowed by the
+	// robots.txt rules.
+	RobotsTxtScrapeReason ScrapeReason = "robots_txt"
+
+	// NetworkErrorScrapeReason means that the request failed, or the
+	// response could not be read.
+	NetworkErrorScrapeReason ScrapeReason = "network_error"
+
+	// HTTPStatusScrapeReason means that the response status code was not
+	// 200 (see WebResource.ScrapeHTTPStatus).
+	HTTPStatusScrapeReason ScrapeReason = "http_status"
+
+	// ContentTypeScrapeReason means that the response is neither text nor
+	// HTML.
+	ContentTypeScrapeReason ScrapeReason = "content_type"
+
+	// EmptyBodyScrapeReason means that the response body is empty.
+	EmptyBodyScrapeReason ScrapeReason = "empty_body"
+
+	// ExtractionErrorScrapeReason means that the content could not be
+	// decoded or parsed.
+	ExtractionErrorScrapeReason ScrapeReason = "extraction_error"
+
+	// NoTitleScrapeReason means that no article title was found.
+	NoTitleScrapeReason ScrapeReason = "no_title"
+
+	// DuplicateTitleScrapeReason means that a WebArticle with the same
+	// title already exists.
+	DuplicateTitleScrapeReason ScrapeReason = "duplicate_title"
+
+	// UndetectedLanguageScrapeReason means that the language of the
+	// article could not be detected.
+	UndetectedLanguageScrapeReason ScrapeReason = "undetected_language"
+
+	// LanguageNotAllowedScrapeReason means that the language of the
+	// article is not in the language filter.
+	LanguageNotAllowedScrapeReason ScrapeReason = "language_not_allowed"
+
+	// UnexpectedErrorScrapeReason means that the attempt was interrupted
+	// by an unexpected error (for example, a database error or the
+	// termination of the job), which made the job fail.
+	UnexpectedErrorScrapeReason ScrapeReason = "unexpected_error"
+)
+
 // WebResource represents a web resource, usually a web page, accessible
 // via a URL.
 type WebResource struct {
@@ -12,6 +104,17 @@ type WebResource struct {
 	// The unique URL of the web resource.
 	URL string `gorm:"not null;uniqueIndex"`
 
+	// ScrapeStatus is the outcome of the last scraping attempt of the web
+	// resource, and ScrapeReason explains it.
+	ScrapeStatus ScrapeStatus `gorm:"not null;default:'';index"`
+	ScrapeReason ScrapeReason `gorm:"not null;default:''"`
+	// ScrapeHTTPStatus is the HTTP status code of the last scraping
+	// attempt, or zero if no response was received.
+	ScrapeHTTPStatus int `gorm:"not null;default:0"`
+	// ScrapeAttempts is the number of scraping attempts so far.
+	ScrapeAttempts      int `gorm:"not null;default:0"`
+	LastScrapeAttemptAt sql.NullTime
+
 	// A WebArticle extends the WebResource with the scraped content.
 	WebArticle *WebArticle `gorm:"constraint:OnDelete:CASCADE"`
 
@@ -21,6 +124,56 @@ type WebResource struct {
 	// GDELTEvent allows the has-one relation with a models.GDELTEvent.
 	GDELTEvent *GDELTEvent `gorm:"constraint:OnDelete:CASCADE"`
 
+	// GDELTGKGRecord allows the has-one relation with a
+	// models.GDELTGKGRecord.
+	GDELTGKGRecord *GDELTGKGRecord `gorm:"constraint:OnDelete:CASCADE"`
+
+	// SitemapItem allows the has-one relation with a models.SitemapItem.
+	SitemapItem *SitemapItem `gorm:"constraint:OnDelete:CASCADE"`
+
+	// APIItem allows the has-one relation with a models.APIItem.
+	APIItem *APIItem `gorm:"constraint:OnDelete:CASCADE"`
+
 	// Tweet allows the has-one relation with a models.Tweet.
 	Tweet *Tweet `gorm:"constraint:OnDelete:CASCADE"`
+
+	// MastodonPost allows the has-one relation with a models.MastodonPost.
+	MastodonPost *MastodonPost `gorm:"constraint:OnDelete:CASCADE"`
+
+	// NewsletterMessage allows the has-one relation with a
+	// models.NewsletterMessage.
+	NewsletterMessage *NewsletterMessage `gorm:"constraint:OnDelete:CASCADE"`
+
+	// TweetLinks is the has-many relation with the models.TweetLink models
+	// of the tweets which shared this resource.
+	TweetLinks []TweetLink `gorm:"constraint:OnDelete:CASCADE"`
+
+	// MastodonPostLinks is the has-many relation with the
+	// models.MastodonPostLink models of the posts which shared this resource.
+	MastodonPostLinks []MastodonPostLink `gorm:"constraint:OnDelete:CASCADE"`
+
+	// NewsletterMessageLinks is the has-many relation with the
+	// models.NewsletterMessageLink models of the newsletter messages which
+	// linked this resource.
+	NewsletterMessageLinks []NewsletterMessageLink `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// FindOrCreateWebResource returns the ID of the WebResource with the given
+// URL, creating it if it does not exist yet. The returned "created" flag
+// reports whether a new WebResource was created.
+func FindOrCreateWebResource(tx *gorm.DB, url string) (id uint, created bool, err error) {
+	wr := &WebResource{URL: url}
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(wr)
+	if res.Error != nil {
+		return 0, false, fmt.Errorf("error creating WebResource for URL %#v: %w", url, res.Error)
+	}
+	if wr.ID != 0 {
+		return wr.ID, true, nil
+	}
+
+	res = tx.Select("id").First(wr, "url = ?", url)
+	if res.Error != nil {
+		return 0, false, fmt.Errorf("error fetching WebResource by URL %#v: %w", url, res.Error)
+	}
+	return wr.ID, false, nil
 }
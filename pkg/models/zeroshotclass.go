@@ -14,6 +14,7 @@ type ZeroShotClass struct {
 
 	// Association to the ZeroShotHypothesisLabel.
 	ZeroShotHypothesisLabelID uint `gorm:"not null;index;index:idx_web_article_id_label_id,unique"`
+	ZeroShotHypothesisLabel   *ZeroShotHypothesisLabel
 
 	// Association to the ZeroShotHypothesisTemplate.
 	ZeroShotHypothesisTemplateID uint `gorm:"not null;index;index:idx_web_article_id_template_id_best,unique,where:best"`
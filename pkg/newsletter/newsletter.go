@@ -0,0 +1,300 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package newsletter parses email newsletter messages (RFC 5322 with MIME
+// parts), extracting their text and the links they contain.
+package newsletter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/text/encoding/htmlindex"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxPartsDepth limits the nesting of multipart bodies.
+const maxPartsDepth = 10
+
+// Message is a parsed newsletter message.
+type Message struct {
+	// MessageID is the value of the "Message-ID" header, without angle
+	// brackets. If the header is missing, it is derived from the SHA-256
+	// hash of the raw message, so that it is still stable.
+	MessageID string
+	Subject   string
+	// From is the sender email address, and FromName its display name.
+	From     string
+	FromName string
+	// Date is the value of the "Date" header, or the zero time if it is
+	// missing or invalid.
+	Date time.Time
+	// Text is the plain-text content of the message, converted from HTML
+	// when there is no plain-text part.
+	Text string
+	// Links is the list of unique HTTP(S) URLs linked by the message.
+	Links []string
+}
+
+var wordDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// Parse parses a raw message.
+func Parse(raw []byte) (*Message, error) {
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("error reading message: %w", err)
+	}
+
+	msg := &Message{
+		MessageID: strings.Trim(strings.TrimSpace(m.Header.Get("Message-Id")), "<>"),
+		Subject:   decodeHeader(m.Header.Get("Subject")),
+	}
+	if len(msg.MessageID) == 0 {
+		msg.MessageID = fmt.Sprintf("sha256:%x", sha256.Sum256(raw))
+	}
+	if from := m.Header.Get("From"); len(from) > 0 {
+		parser := &mail.AddressParser{WordDecoder: wordDecoder}
+		if addr, err := parser.Parse(from); err == nil {
+			msg.From = strings.ToLower(addr.Address)
+			msg.FromName = addr.Name
+		}
+	}
+	if date, err := m.Header.Date(); err == nil {
+		msg.Date = date.UTC()
+	}
+
+	var b body
+	err = b.read(textproto.MIMEHeader(m.Header), m.Body, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b.html) > 0 {
+		doc, err := html.Parse(strings.NewReader(b.html))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing HTML part: %w", err)
+		}
+		msg.Links = htmlLinks(doc)
+		if len(b.plain) == 0 {
+			b.plain = htmlText(doc)
+		}
+	} else {
+		msg.Links = textLinks(b.plain)
+	}
+	msg.Text = normalizeText(b.plain)
+	return msg, nil
+}
+
+// body holds the first plain-text and HTML parts of a message.
+type body struct {
+	plain string
+	html  string
+}
+
+func (b *body) read(header textproto.MIMEHeader, r io.Reader, depth int) error {
+	if depth > maxPartsDepth {
+		return fmt.Errorf("too many nested MIME parts")
+	}
+
+	if disposition, _, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil && disposition == "attachment" {
+		return nil
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(r, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("error reading MIME part: %w", err)
+			}
+			err = b.read(part.Header, part, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if (mediaType != "text/plain" || len(b.plain) > 0) && (mediaType != "text/html" || len(b.html) > 0) {
+		return nil
+	}
+
+	text, err := decodeText(header.Get("Content-Transfer-Encoding"), params["charset"], r)
+	if err != nil {
+		return err
+	}
+	if mediaType == "text/plain" {
+		b.plain = text
+	} else {
+		b.html = text
+	}
+	return nil
+}
+
+func decodeText(transferEncoding, charset string, r io.Reader) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	}
+	if len(charset) > 0 {
+		if cr, err := charsetReader(charset, r); err == nil {
+			r = cr
+		}
+	}
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("error decoding MIME part: %w", err)
+	}
+	return strings.ToValidUTF8(string(content), ""), nil
+}
+
+func charsetReader(charset string, r io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "utf-8", "us-ascii":
+		return r, nil
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %#v: %w", charset, err)
+	}
+	return enc.NewDecoder().Reader(r), nil
+}
+
+func decodeHeader(s string) string {
+	decoded, err := wordDecoder.DecodeHeader(s)
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+	return strings.TrimSpace(decoded)
+}
+
+var reBlankLines = regexp.MustCompile(`\n{3,}`)
+
+func normalizeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(reBlankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// reIgnoredLink matches the links which are usually part of the newsletter
+// boilerplate, rather than its content.
+var reIgnoredLink = regexp.MustCompile(`(?i)unsubscribe|optout|opt-out|email-preferences|manage-preferences|view-in-browser|viewinbrowser|webversion`)
+
+func htmlLinks(doc *html.Node) []string {
+	links := newLinkSet()
+	goquery.NewDocumentFromNode(doc).Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		text := strings.ToLower(a.Text())
+		if strings.Contains(text, "unsubscribe") || strings.Contains(text, "view in browser") {
+			return
+		}
+		links.add(a.AttrOr("href", ""))
+	})
+	return links.urls
+}
+
+// blockElements are the HTML elements whose content is separated from the
+// surrounding text by line breaks.
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"div": true, "dl": true, "dt": true, "dd": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "table": true,
+	"td": true, "th": true, "tr": true, "ul": true,
+}
+
+var reSpaces = regexp.MustCompile(`[ \t\r\n\f]+`)
+
+// htmlText converts an HTML document to plain text, with a blank line
+// between block elements. Scripts, styles and the document head are
+// omitted.
+func htmlText(doc *html.Node) string {
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			sb.WriteString(reSpaces.ReplaceAllString(n.Data, " "))
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "head", "script", "style", "title":
+				return
+			case "br":
+				sb.WriteString("\n")
+				return
+			}
+		}
+		block := n.Type == html.ElementNode && blockElements[n.Data]
+		if block {
+			sb.WriteString("\n\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if block {
+			sb.WriteString("\n\n")
+		}
+	}
+	walk(doc)
+	return sb.String()
+}
+
+var reTextURL = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+func textLinks(text string) []string {
+	links := newLinkSet()
+	for _, u := range reTextURL.FindAllString(text, -1) {
+		links.add(strings.TrimRight(u, ".,;:!?)]'"))
+	}
+	return links.urls
+}
+
+type linkSet struct {
+	seen map[string]struct{}
+	urls []string
+}
+
+func newLinkSet() *linkSet {
+	return &linkSet{seen: make(map[string]struct{})}
+}
+
+func (ls *linkSet) add(rawURL string) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return
+	}
+	u.Fragment = ""
+	s := u.String()
+	if _, ok := ls.seen[s]; ok || reIgnoredLink.MatchString(s) {
+		return
+	}
+	ls.seen[s] = struct{}{}
+	ls.urls = append(ls.urls, s)
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package newsletter
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+const multipartMessage = `Message-ID: <20211120.1234@mail.example.com>
+From: =?UTF-8?Q?Caff=C3=A8_News?= <News@Example.com>
+To: reader@example.org
+Subject: =?ISO-8859-1?Q?Le_caff=E8?= of the week
+Date: Sat, 20 Nov 2021 10:15:00 +0100
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: multipart/alternative; boundary="inner"
+
+--inner
+Content-Type: text/html; charset=iso-8859-1
+Content-Transfer-Encoding: quoted-printable
+
+<html><head><style>p { color: red; }</style></head><body>
+<p>Top stories of the <b>week</b>:</p>
+<p><a href=3D"https://news.example.com/a?id=3D1#top">Caff=E8 prices</a></p>
+<p><a href=3D"https://news.example.com/a?id=3D1">Again</a>
+<a href=3D"mailto:news@example.com">Write us</a>
+<a href=3D"https://example.com/unsubscribe?u=3D42">Stop</a>
+<a href=3D"https://example.com/u?id=3D42">Unsubscribe</a></p>
+</body></html>
+--inner--
+
+--outer
+Content-Type: text/plain; charset=utf-8
+Content-Disposition: attachment; filename="notes.txt"
+Content-Transfer-Encoding: base64
+
+aHR0cHM6Ly9hdHRhY2htZW50LmV4YW1wbGUuY29tLw==
+--outer--
+`
+
+func TestParse_Multipart(t *testing.T) {
+	t.Parallel()
+
+	msg, err := Parse([]byte(strings.ReplaceAll(multipartMessage, "\n", "\r\n")))
+	require.NoError(t, err)
+
+	assert.Equal(t, "20211120.1234@mail.example.com", msg.MessageID)
+	assert.Equal(t, "Le caffè of the week", msg.Subject)
+	assert.Equal(t, "news@example.com", msg.From)
+	assert.Equal(t, "Caffè News", msg.FromName)
+	assert.Equal(t, time.Date(2021, 11, 20, 9, 15, 0, 0, time.UTC), msg.Date)
+	assert.Equal(t, "Top stories of the week:\n\nCaffè prices\n\nAgain Write us Stop Unsubscribe", msg.Text)
+	assert.Equal(t, []string{"https://news.example.com/a?id=1"}, msg.Links)
+}
+
+func TestParse_PlainText(t *testing.T) {
+	t.Parallel()
+
+	raw := "From: news@example.com\r\n" +
+		"Subject: Daily digest\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"Content-Transfer-Encoding: base64\r\n" +
+		"\r\n" +
+		"UmVhZDogaHR0cHM6Ly9uZXdzLmV4YW1wbGUuY29tL2IuCkFuZCAoaHR0cHM6Ly9uZXdzLmV4YW1w\r\n" +
+		"bGUuY29tL2MpLg==\r\n"
+
+	msg, err := Parse([]byte(raw))
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(msg.MessageID, "sha256:"))
+	assert.True(t, msg.Date.IsZero())
+	assert.Equal(t, "Read: https://news.example.com/b.\nAnd (https://news.example.com/c).", msg.Text)
+	assert.Equal(t, []string{"https://news.example.com/b", "https://news.example.com/c"}, msg.Links)
+
+	again, err := Parse([]byte(raw))
+	require.NoError(t, err)
+	assert.Equal(t, msg.MessageID, again.MessageID)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := Parse([]byte("not a message"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,202 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package opml reads and writes OPML documents of feed subscriptions.
+package opml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"golang.org/x/net/html/charset"
+	"io"
+	"strings"
+	"time"
+)
+
+// Version is the OPML version of the generated documents.
+const Version = "2.0"
+
+// Document is an OPML document.
+type Document struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+// Head is the head element of an OPML document.
+type Head struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+// Body is the body element of an OPML document.
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is an outline element of an OPML document. An Outline with an
+// XMLURL is a feed subscription; otherwise, it usually acts as a folder
+// (category) for its nested Outlines.
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Language string    `xml:"language,attr,omitempty"`
+	Category string    `xml:"category,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// Feed is a feed subscription, as read from or written to an OPML Document.
+type Feed struct {
+	URL      string
+	Title    string
+	SiteURL  string
+	Language string
+	Tags     []string
+}
+
+// Parse reads an OPML document.
+func Parse(r io.Reader) (*Document, error) {
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charset.NewReaderLabel
+
+	doc := new(Document)
+	err := dec.Decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing OPML document: %w", err)
+	}
+	if len(doc.Version) == 0 {
+		return nil, fmt.Errorf("invalid OPML document: missing version")
+	}
+	return doc, nil
+}
+
+// Feeds returns the feed subscriptions from all the Outlines of the
+// document, in order. The tags of each Feed are made of the texts of the
+// enclosing folder Outlines, followed by the categories from its own
+// "category" attribute.
+func (d *Document) Feeds() []Feed {
+	feeds := make([]Feed, 0)
+	var walk func(outlines []Outline, folders []string)
+	walk = func(outlines []Outline, folders []string) {
+		for _, o := range outlines {
+			url := strings.TrimSpace(o.XMLURL)
+			if len(url) == 0 {
+				folder := o.Title
+				if len(folder) == 0 {
+					folder = o.Text
+				}
+				walk(o.Outlines, appendTag(folders, folder))
+				continue
+			}
+
+			title := strings.TrimSpace(o.Title)
+			if len(title) == 0 {
+				title = strings.TrimSpace(o.Text)
+			}
+			if title == url {
+				title = ""
+			}
+			tags := make([]string, 0, len(folders))
+			tags = append(tags, folders...)
+			tags = append(tags, parseCategory(o.Category)...)
+
+			feeds = append(feeds, Feed{
+				URL:      url,
+				Title:    title,
+				SiteURL:  strings.TrimSpace(o.HTMLURL),
+				Language: strings.TrimSpace(o.Language),
+				Tags:     tags,
+			})
+		}
+	}
+	walk(d.Body.Outlines, nil)
+	return feeds
+}
+
+// NewDocument creates a new OPML Document with an Outline for each Feed.
+// The tags of each Feed are written to the "category" attribute.
+func NewDocument(title string, feeds []Feed, dateCreated time.Time) *Document {
+	outlines := make([]Outline, len(feeds))
+	for i, f := range feeds {
+		text := f.Title
+		if len(text) == 0 {
+			text = f.URL
+		}
+		outlines[i] = Outline{
+			Text:     text,
+			Title:    f.Title,
+			Type:     "rss",
+			XMLURL:   f.URL,
+			HTMLURL:  f.SiteURL,
+			Language: f.Language,
+			Category: formatCategory(f.Tags),
+		}
+	}
+
+	return &Document{
+		Version: Version,
+		Head: Head{
+			Title:       title,
+			DateCreated: dateCreated.UTC().Format(time.RFC1123Z),
+		},
+		Body: Body{Outlines: outlines},
+	}
+}
+
+// Write writes the OPML document, with an XML declaration.
+func (d *Document) Write(w io.Writer) error {
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return fmt.Errorf("error writing OPML document: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(d)
+	if err != nil {
+		return fmt.Errorf("error writing OPML document: %w", err)
+	}
+	_, err = io.WriteString(w, "\n")
+	if err != nil {
+		return fmt.Errorf("error writing OPML document: %w", err)
+	}
+	return nil
+}
+
+// parseCategory splits the value of an OPML "category" attribute, which is
+// a comma-separated list of slash-delimited category paths, into tags.
+// Each category path becomes a tag, without the leading slash.
+func parseCategory(category string) []string {
+	var tags []string
+	for _, c := range strings.Split(category, ",") {
+		c = strings.Trim(strings.TrimSpace(c), "/")
+		if len(c) > 0 {
+			tags = append(tags, c)
+		}
+	}
+	return tags
+}
+
+// formatCategory is the reverse of parseCategory.
+func formatCategory(tags []string) string {
+	categories := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if len(tag) > 0 {
+			categories = append(categories, "/"+strings.ReplaceAll(tag, ",", " "))
+		}
+	}
+	return strings.Join(categories, ",")
+}
+
+func appendTag(tags []string, tag string) []string {
+	tag = strings.TrimSpace(tag)
+	if len(tag) == 0 {
+		return tags
+	}
+	return append(append([]string(nil), tags...), tag)
+}
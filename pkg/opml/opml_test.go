@@ -0,0 +1,129 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opml
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nested folders and categories", func(t *testing.T) {
+		t.Parallel()
+		doc, err := Parse(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>Subscriptions</title></head>
+  <body>
+    <outline text="News">
+      <outline text="World" title="World news">
+        <outline type="rss" text="Foo" xmlUrl="https://foo.example.com/rss" htmlUrl="https://foo.example.com/" category="/europe,/politics/elections"/>
+      </outline>
+      <outline type="rss" text="Bar" title="The Bar" xmlUrl=" https://bar.example.com/atom.xml " language="it"/>
+    </outline>
+    <outline type="rss" text="Baz" xmlUrl="https://baz.example.com/feed"/>
+    <outline text="Just a note"/>
+  </body>
+</opml>`))
+		require.NoError(t, err)
+		assert.Equal(t, "2.0", doc.Version)
+		assert.Equal(t, "Subscriptions", doc.Head.Title)
+
+		expected := []Feed{
+			{
+				URL:     "https://foo.example.com/rss",
+				Title:   "Foo",
+				SiteURL: "https://foo.example.com/",
+				Tags:    []string{"News", "World news", "europe", "politics/elections"},
+			},
+			{
+				URL:      "https://bar.example.com/atom.xml",
+				Title:    "The Bar",
+				Language: "it",
+				Tags:     []string{"News"},
+			},
+			{
+				URL:   "https://baz.example.com/feed",
+				Title: "Baz",
+				Tags:  []string{},
+			},
+		}
+		feeds := doc.Feeds()
+		require.Len(t, feeds, len(expected))
+		for i, f := range feeds {
+			assert.Equal(t, expected[i].URL, f.URL)
+			assert.Equal(t, expected[i].Title, f.Title)
+			assert.Equal(t, expected[i].SiteURL, f.SiteURL)
+			assert.Equal(t, expected[i].Language, f.Language)
+			assert.ElementsMatch(t, expected[i].Tags, f.Tags)
+		}
+	})
+
+	t.Run("non-UTF-8 encoding", func(t *testing.T) {
+		t.Parallel()
+		doc, err := Parse(strings.NewReader("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n" +
+			"<opml version=\"1.0\"><body><outline text=\"Caf\xe9\" xmlUrl=\"https://example.com/rss\"/></body></opml>"))
+		require.NoError(t, err)
+		feeds := doc.Feeds()
+		require.Len(t, feeds, 1)
+		assert.Equal(t, "Café", feeds[0].Title)
+	})
+
+	t.Run("invalid documents", func(t *testing.T) {
+		t.Parallel()
+		_, err := Parse(strings.NewReader(`<opml version="2.0"><body>`))
+		assert.Error(t, err)
+		_, err = Parse(strings.NewReader(`<rss version="2.0"></rss>`))
+		assert.Error(t, err)
+		_, err = Parse(strings.NewReader(`<opml><body></body></opml>`))
+		assert.Error(t, err)
+	})
+}
+
+func TestNewDocument(t *testing.T) {
+	t.Parallel()
+
+	feeds := []Feed{
+		{
+			URL:      "https://foo.example.com/rss",
+			Title:    "Foo & Co",
+			SiteURL:  "https://foo.example.com/",
+			Language: "en",
+			Tags:     []string{"news", "politics/elections"},
+		},
+		{
+			URL:  "https://bar.example.com/atom.xml",
+			Tags: []string{},
+		},
+	}
+	dateCreated := time.Date(2021, 11, 20, 10, 30, 0, 0, time.UTC)
+
+	doc := NewDocument("WhatsNew Feeds", feeds, dateCreated)
+	assert.Equal(t, Version, doc.Version)
+	assert.Equal(t, "Sat, 20 Nov 2021 10:30:00 +0000", doc.Head.DateCreated)
+	require.Len(t, doc.Body.Outlines, 2)
+	assert.Equal(t, "Foo & Co", doc.Body.Outlines[0].Text)
+	assert.Equal(t, "/news,/politics/elections", doc.Body.Outlines[0].Category)
+	assert.Equal(t, "https://bar.example.com/atom.xml", doc.Body.Outlines[1].Text)
+	assert.Equal(t, "rss", doc.Body.Outlines[1].Type)
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf))
+	assert.True(t, strings.HasPrefix(buf.String(), `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, buf.String(), `text="Foo &amp; Co"`)
+
+	parsed, err := Parse(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "WhatsNew Feeds", parsed.Head.Title)
+	assert.Equal(t, []Feed{
+		feeds[0],
+		{URL: "https://bar.example.com/atom.xml", Tags: []string{}},
+	}, parsed.Feeds())
+}
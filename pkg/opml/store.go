@@ -0,0 +1,126 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package opml
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+// An ImportedFeed is a Feed from an OPML document, associated to its
+// database record.
+type ImportedFeed struct {
+	Feed
+	// FeedID is the ID of the models.Feed with the same URL. It is zero
+	// for a Duplicate of a deleted models.Feed.
+	FeedID uint
+	// Duplicate reports whether a models.Feed with the same URL was
+	// already existing, or it appeared earlier in the same document, so
+	// that no new models.Feed was created.
+	Duplicate bool
+}
+
+// Import creates a new enabled models.Feed for each Feed whose URL is not
+// yet in the database. Duplicates are reported, and otherwise ignored.
+func Import(tx *gorm.DB, feeds []Feed) ([]ImportedFeed, error) {
+	imported := make([]ImportedFeed, len(feeds))
+	seen := make(map[string]uint, len(feeds))
+
+	for i, feed := range feeds {
+		if id, ok := seen[feed.URL]; ok {
+			imported[i] = ImportedFeed{Feed: feed, FeedID: id, Duplicate: true}
+			continue
+		}
+
+		id, created, err := importFeed(tx, feed)
+		if err != nil {
+			return nil, err
+		}
+		imported[i] = ImportedFeed{Feed: feed, FeedID: id, Duplicate: !created}
+		seen[feed.URL] = id
+	}
+	return imported, nil
+}
+
+func importFeed(tx *gorm.DB, feed Feed) (uint, bool, error) {
+	var existing models.Feed
+	res := tx.Unscoped().Select("id", "deleted_at").Limit(1).Find(&existing, "url = ?", feed.URL)
+	if res.Error != nil {
+		return 0, false, fmt.Errorf("error fetching Feed by URL %#v: %w", feed.URL, res.Error)
+	}
+	if res.RowsAffected > 0 {
+		if existing.DeletedAt.Valid {
+			return 0, false, nil
+		}
+		return existing.ID, false, nil
+	}
+
+	newFeed := &models.Feed{
+		URL:      feed.URL,
+		Enabled:  true,
+		Title:    makeNullString(feed.Title),
+		SiteURL:  makeNullString(feed.SiteURL),
+		Language: makeNullString(strings.ToLower(feed.Language)),
+	}
+	err := newFeed.SetTags(feed.Tags)
+	if err != nil {
+		return 0, false, err
+	}
+
+	res = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(newFeed)
+	if res.Error != nil {
+		return 0, false, fmt.Errorf("error creating Feed %#v: %w", feed.URL, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return 0, false, nil
+	}
+	return newFeed.ID, true, nil
+}
+
+// Export loads from the database the enabled Feeds, the disabled Feeds, or
+// both, ordered by ID.
+func Export(tx *gorm.DB, enabled, disabled bool) ([]Feed, error) {
+	var statuses []bool
+	if enabled {
+		statuses = append(statuses, true)
+	}
+	if disabled {
+		statuses = append(statuses, false)
+	}
+	if len(statuses) == 0 {
+		return []Feed{}, nil
+	}
+
+	var dbFeeds []models.Feed
+	res := tx.Where("enabled IN ?", statuses).Order("id").Find(&dbFeeds)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error fetching Feeds: %w", res.Error)
+	}
+
+	feeds := make([]Feed, len(dbFeeds))
+	for i, f := range dbFeeds {
+		tags, err := f.TagsAsStringSlice()
+		if err != nil {
+			return nil, err
+		}
+		feeds[i] = Feed{
+			URL:      f.URL,
+			Title:    f.Title.String,
+			SiteURL:  f.SiteURL.String,
+			Language: f.Language.String,
+			Tags:     tags,
+		}
+	}
+	return feeds, nil
+}
+
+func makeNullString(s string) sql.NullString {
+	s = strings.TrimSpace(s)
+	return sql.NullString{String: s, Valid: len(s) > 0}
+}
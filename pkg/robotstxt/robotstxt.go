@@ -0,0 +1,189 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package robotstxt parses robots.txt files (RFC 9309) and tells whether a
+// crawler is allowed to access a path.
+package robotstxt
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSize is the maximum number of bytes of a robots.txt file which are
+// parsed. The rest of the file is ignored.
+const MaxSize = 500 << 10
+
+// Robots holds the rules of a robots.txt file. The zero value allows
+// everything.
+type Robots struct {
+	groups []*group
+}
+
+type group struct {
+	agents     []string
+	rules      []rule
+	crawlDelay time.Duration
+}
+
+type rule struct {
+	allow   bool
+	pattern string
+	re      *regexp.Regexp
+}
+
+// Parse parses the content of a robots.txt file. Invalid lines are
+// ignored.
+func Parse(r io.Reader) (*Robots, error) {
+	robots := &Robots{}
+	var current *group
+	// lastWasAgent reports whether the previous relevant line was a
+	// user-agent, so that consecutive user-agents share the same group.
+	lastWasAgent := false
+
+	scanner := bufio.NewScanner(io.LimitReader(r, MaxSize))
+	scanner.Buffer(make([]byte, 0, 4096), MaxSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			continue
+		}
+		field := strings.ToLower(strings.TrimSpace(line[:i]))
+		value := strings.TrimSpace(line[i+1:])
+
+		switch field {
+		case "user-agent":
+			if current == nil || !lastWasAgent {
+				current = &group{}
+				robots.groups = append(robots.groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			lastWasAgent = true
+		case "allow", "disallow":
+			lastWasAgent = false
+			if current == nil || len(value) == 0 {
+				continue
+			}
+			current.rules = append(current.rules, rule{
+				allow:   field == "allow",
+				pattern: value,
+				re:      compilePattern(value),
+			})
+		case "crawl-delay":
+			lastWasAgent = false
+			if current == nil {
+				continue
+			}
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.crawlDelay = time.Duration(seconds * float64(time.Second))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return robots, nil
+}
+
+// compilePattern converts a path pattern, which can contain the special
+// characters "*" (any sequence of characters) and "$" (end of the path),
+// to a regular expression.
+func compilePattern(pattern string) *regexp.Regexp {
+	end := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if end {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// Allowed reports whether the crawler with the given user agent is allowed
+// to access the path, which should also include the query, if any (e.g.
+// "/news?id=1").
+//
+// The most specific (longest) matching rule wins, and an allow rule wins
+// over a disallow rule of the same length.
+func (r *Robots) Allowed(userAgent, path string) bool {
+	if path == "/robots.txt" {
+		return true
+	}
+	if len(path) == 0 {
+		path = "/"
+	}
+
+	allowed := true
+	matchLen := -1
+	for _, g := range r.matchingGroups(userAgent) {
+		for _, ru := range g.rules {
+			if !ru.re.MatchString(path) {
+				continue
+			}
+			if l := len(ru.pattern); l > matchLen || (l == matchLen && ru.allow) {
+				allowed = ru.allow
+				matchLen = l
+			}
+		}
+	}
+	return allowed
+}
+
+// CrawlDelay returns the crawl delay for the crawler with the given user
+// agent, or zero if it is not set.
+func (r *Robots) CrawlDelay(userAgent string) time.Duration {
+	var delay time.Duration
+	for _, g := range r.matchingGroups(userAgent) {
+		if g.crawlDelay > delay {
+			delay = g.crawlDelay
+		}
+	}
+	return delay
+}
+
+// matchingGroups returns the groups whose user-agent is the longest one
+// contained in the given user agent string (case-insensitive), or the groups
+// for any user-agent ("*") if none matches.
+func (r *Robots) matchingGroups(userAgent string) []*group {
+	ua := strings.ToLower(userAgent)
+
+	var matching, wildcard []*group
+	bestLen := 0
+	for _, g := range r.groups {
+		groupLen, groupWildcard := 0, false
+		for _, agent := range g.agents {
+			switch {
+			case agent == "*":
+				groupWildcard = true
+			case len(agent) > groupLen && strings.Contains(ua, agent):
+				groupLen = len(agent)
+			}
+		}
+		switch {
+		case groupLen > bestLen:
+			matching = []*group{g}
+			bestLen = groupLen
+		case groupLen > 0 && groupLen == bestLen:
+			matching = append(matching, g)
+		case groupLen == 0 && groupWildcard:
+			wildcard = append(wildcard, g)
+		}
+	}
+	if len(matching) > 0 {
+		return matching
+	}
+	return wildcard
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package robotstxt
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRobots = `# Example robots.txt
+User-agent: *
+Disallow: /private/
+Allow: /private/public
+Disallow: /*.pdf$
+Disallow: /search?
+Crawl-delay: 2
+
+User-agent: BadBot
+User-agent: OtherBot
+Disallow: /
+
+User-agent: WhatsNew
+Disallow: /news/drafts # work in progress
+Allow: /news/drafts/published
+Crawl-delay: 0.5
+`
+
+func TestRobots_Allowed(t *testing.T) {
+	t.Parallel()
+
+	robots, err := Parse(strings.NewReader(testRobots))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		userAgent string
+		path      string
+		want      bool
+	}{
+		{"Mozilla/5.0", "/", true},
+		{"Mozilla/5.0", "/private/data", false},
+		{"Mozilla/5.0", "/private/public/data", true},
+		{"Mozilla/5.0", "/files/report.pdf", false},
+		{"Mozilla/5.0", "/files/report.pdf?download=1", true},
+		{"Mozilla/5.0", "/search?q=news", false},
+		{"Mozilla/5.0", "/search", true},
+		{"Mozilla/5.0 (compatible; BadBot/1.0)", "/news", false},
+		{"otherbot", "/robots.txt", true},
+		{"Mozilla/5.0 (compatible; WhatsNew/1.0)", "/private/data", true},
+		{"Mozilla/5.0 (compatible; WhatsNew/1.0)", "/news/drafts/1", false},
+		{"Mozilla/5.0 (compatible; WhatsNew/1.0)", "/news/drafts/published/1", true},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, robots.Allowed(tc.userAgent, tc.path), "%s %s", tc.userAgent, tc.path)
+	}
+}
+
+func TestRobots_CrawlDelay(t *testing.T) {
+	t.Parallel()
+
+	robots, err := Parse(strings.NewReader(testRobots))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2*time.Second, robots.CrawlDelay("Mozilla/5.0"))
+	assert.Equal(t, 500*time.Millisecond, robots.CrawlDelay("WhatsNew/1.0"))
+	assert.Equal(t, time.Duration(0), robots.CrawlDelay("BadBot"))
+}
+
+func TestRobots_Empty(t *testing.T) {
+	t.Parallel()
+
+	var robots Robots
+	assert.True(t, robots.Allowed("Mozilla/5.0", "/private"))
+
+	parsed, err := Parse(strings.NewReader("User-agent: *\nDisallow:\n"))
+	require.NoError(t, err)
+	assert.True(t, parsed.Allowed("Mozilla/5.0", "/private"))
+}
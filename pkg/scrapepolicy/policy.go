@@ -0,0 +1,177 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scrapepolicy
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Policy holds the rules to follow when scraping the web pages of a domain,
+// including all its subdomains.
+type Policy struct {
+	// Domain is the normalized domain name (see NormalizeDomain). It is
+	// empty for the default policy.
+	Domain             string
+	UserAgent          string
+	Headers            map[string]string
+	Cookies            map[string]string
+	ProxyURL           string
+	MinRequestInterval time.Duration
+	// IgnoreRobotsTxt and InsecureSkipVerify, when not nil, replace the
+	// default settings. They are always set in the policies returned by
+	// Client.Policy.
+	IgnoreRobotsTxt    *bool
+	InsecureSkipVerify *bool
+}
+
+// FromConfig creates a new Policy from the configuration.
+func FromConfig(c config.DomainPolicy) Policy {
+	return Policy{
+		Domain:             NormalizeDomain(c.Domain),
+		UserAgent:          strings.TrimSpace(c.UserAgent),
+		Headers:            c.Headers,
+		Cookies:            c.Cookies,
+		ProxyURL:           strings.TrimSpace(c.ProxyURL),
+		MinRequestInterval: c.MinRequestInterval,
+		IgnoreRobotsTxt:    c.IgnoreRobotsTxt,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+}
+
+// FromModel creates a new Policy from a models.DomainPolicy.
+func FromModel(m models.DomainPolicy) (Policy, error) {
+	headers, err := m.HeadersAsMap()
+	if err != nil {
+		return Policy{}, err
+	}
+	cookies, err := m.CookiesAsMap()
+	if err != nil {
+		return Policy{}, err
+	}
+	p := Policy{
+		Domain:             NormalizeDomain(m.Domain),
+		UserAgent:          strings.TrimSpace(m.UserAgent),
+		Headers:            headers,
+		Cookies:            cookies,
+		ProxyURL:           strings.TrimSpace(m.ProxyURL),
+		MinRequestInterval: m.MinRequestInterval,
+		IgnoreRobotsTxt:    nullBoolPtr(m.IgnoreRobotsTxt),
+		InsecureSkipVerify: nullBoolPtr(m.InsecureSkipVerify),
+	}
+	return p, nil
+}
+
+// nullBoolPtr converts a sql.NullBool to a *bool, which is nil if the value
+// is NULL.
+func nullBoolPtr(b sql.NullBool) *bool {
+	if !b.Valid {
+		return nil
+	}
+	return &b.Bool
+}
+
+// isTrue reports whether b is set and true.
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+// NormalizeDomain returns the domain in lowercase, without spaces and
+// leading or trailing dots.
+func NormalizeDomain(domain string) string {
+	return strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// Validate reports whether the Policy is valid.
+func (p Policy) Validate() error {
+	if len(p.Domain) == 0 {
+		return fmt.Errorf("invalid empty domain policy domain")
+	}
+	if strings.ContainsAny(p.Domain, "/:@ ") {
+		return fmt.Errorf("invalid domain policy domain %#v: only the domain name is expected", p.Domain)
+	}
+	if p.MinRequestInterval < 0 {
+		return fmt.Errorf("invalid negative domain policy min request interval %s", p.MinRequestInterval)
+	}
+	for name := range p.Headers {
+		if !validToken(name) {
+			return fmt.Errorf("invalid domain policy header name %#v", name)
+		}
+	}
+	for name := range p.Cookies {
+		if !validToken(name) {
+			return fmt.Errorf("invalid domain policy cookie name %#v", name)
+		}
+	}
+	if _, err := parseProxyURL(p.ProxyURL); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validToken reports whether s is a valid HTTP token (RFC 7230), as
+// required for header and cookie names.
+func validToken(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, r := range s {
+		if r <= ' ' || r >= 0x7f || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseProxyURL parses the URL of a proxy, returning nil if it is empty.
+func parseProxyURL(proxyURL string) (*url.URL, error) {
+	if len(proxyURL) == 0 {
+		return nil, nil
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid domain policy proxy URL: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("invalid domain policy proxy URL scheme %#v", u.Scheme)
+	}
+	if len(u.Host) == 0 {
+		return nil, fmt.Errorf("invalid domain policy proxy URL %#v: missing host", proxyURL)
+	}
+	return u, nil
+}
+
+// matches reports whether the policy applies to the given host, that is,
+// the host is the domain itself or one of its subdomains.
+func (p Policy) matches(host string) bool {
+	return host == p.Domain || strings.HasSuffix(host, "."+p.Domain)
+}
+
+// apply sets the user agent, the headers and the cookies of the request.
+func (p Policy) apply(req *http.Request) {
+	for name, value := range p.Headers {
+		req.Header.Set(name, value)
+	}
+	if len(p.UserAgent) > 0 {
+		req.Header.Set("User-Agent", p.UserAgent)
+	}
+	names := make([]string, 0, len(p.Cookies))
+	for name := range p.Cookies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		req.AddCookie(&http.Cookie{Name: name, Value: p.Cookies[name]})
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package scrapepolicy enforces the per-domain policies for scraping web
+// pages: robots.txt rules, per-host rate limits, custom headers and
+// cookies, proxies, and TLS verification.
+package scrapepolicy
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/robotstxt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDisallowed is returned when a URL cannot be requested because of the
+// robots.txt rules.
+var ErrDisallowed = errors.New("disallowed by robots.txt")
+
+// maxRedirects is the maximum number of redirects followed by a request,
+// as for the default HTTP client.
+const maxRedirects = 10
+
+// maxCacheSize is the number of cached robots.txt files (and rate-limited
+// hosts) beyond which the expired entries are removed.
+const maxCacheSize = 10000
+
+// Client performs HTTP GET requests according to the domain policies. It
+// is safe for concurrent use: in particular, the rate limit of each host is
+// shared by all the goroutines.
+type Client struct {
+	conf    config.WebScraper
+	limiter *hostLimiter
+
+	policiesMu sync.RWMutex
+	// policies are sorted by decreasing domain length, so that the most
+	// specific one is found first.
+	policies []Policy
+
+	clientsMu sync.Mutex
+	clients   map[transportKey]*http.Client
+
+	robotsMu sync.Mutex
+	robots   map[string]robotsEntry
+}
+
+type transportKey struct {
+	proxyURL           string
+	insecureSkipVerify bool
+}
+
+// robotsTxtRequestKey is the context key marking the requests of
+// robots.txt files, whose redirects are not checked against robots.txt.
+type robotsTxtRequestKey struct{}
+
+type robotsEntry struct {
+	robots  *robotstxt.Robots
+	expires time.Time
+}
+
+// NewClient creates a new Client. The default policy is defined by the
+// WebScraper settings, and no domain policies are set.
+func NewClient(conf config.WebScraper) *Client {
+	return &Client{
+		conf:    conf,
+		limiter: newHostLimiter(),
+		clients: make(map[transportKey]*http.Client),
+		robots:  make(map[string]robotsEntry),
+	}
+}
+
+// SetPolicies replaces all the domain policies. When more policies have
+// the same domain, the last one wins.
+func (c *Client) SetPolicies(policies []Policy) {
+	byDomain := make(map[string]Policy, len(policies))
+	for _, p := range policies {
+		byDomain[p.Domain] = p
+	}
+	sorted := make([]Policy, 0, len(byDomain))
+	for _, p := range byDomain {
+		sorted = append(sorted, p)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i].Domain) != len(sorted[j].Domain) {
+			return len(sorted[i].Domain) > len(sorted[j].Domain)
+		}
+		return sorted[i].Domain < sorted[j].Domain
+	})
+
+	c.policiesMu.Lock()
+	defer c.policiesMu.Unlock()
+	c.policies = sorted
+}
+
+// Policy returns the effective policy for the given host: the most
+// specific domain policy, if any, completed with the default settings.
+func (c *Client) Policy(host string) Policy {
+	ignoreRobotsTxt := !c.conf.RespectRobotsTxt
+	insecureSkipVerify := c.conf.InsecureSkipVerify
+	p := Policy{
+		UserAgent:          c.conf.UserAgent,
+		MinRequestInterval: c.conf.MinRequestInterval,
+		IgnoreRobotsTxt:    &ignoreRobotsTxt,
+		InsecureSkipVerify: &insecureSkipVerify,
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	c.policiesMu.RLock()
+	defer c.policiesMu.RUnlock()
+	for _, dp := range c.policies {
+		if !dp.matches(host) {
+			continue
+		}
+		p.Domain = dp.Domain
+		if len(dp.UserAgent) > 0 {
+			p.UserAgent = dp.UserAgent
+		}
+		p.Headers = dp.Headers
+		p.Cookies = dp.Cookies
+		p.ProxyURL = dp.ProxyURL
+		if dp.MinRequestInterval > 0 {
+			p.MinRequestInterval = dp.MinRequestInterval
+		}
+		if dp.IgnoreRobotsTxt != nil {
+			ignoreRobotsTxt = *dp.IgnoreRobotsTxt
+		}
+		if dp.InsecureSkipVerify != nil {
+			insecureSkipVerify = *dp.InsecureSkipVerify
+		}
+		break
+	}
+	return p
+}
+
+// Get performs an HTTP GET request, according to the policy of the URL
+// host. If the URL is disallowed by the robots.txt rules, an error
+// wrapping ErrDisallowed is returned. The request waits, if needed, for
+// the minimum interval since the previous request to the same host (or
+// the robots.txt crawl delay, if longer).
+//
+// Redirects are subject to the policy of their target host as well (see
+// checkRedirect).
+func (c *Client) Get(ctx context.Context, rawURL string) (*http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %#v: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL scheme %#v", u.Scheme)
+	}
+
+	p := c.Policy(u.Hostname())
+	hc, err := c.httpClient(p)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.allow(ctx, hc, p, u)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(ctx, hc, p, rawURL)
+}
+
+// allow checks the robots.txt rules for the URL, unless the policy ignores
+// them, and then waits for the rate limit of the URL host.
+func (c *Client) allow(ctx context.Context, hc *http.Client, p Policy, u *url.URL) error {
+	interval := p.MinRequestInterval
+	if !isTrue(p.IgnoreRobotsTxt) {
+		robots, err := c.robotsTxt(ctx, hc, p, u)
+		if err != nil {
+			return err
+		}
+		if !robots.Allowed(p.UserAgent, u.RequestURI()) {
+			return fmt.Errorf("%#v: %w", u.String(), ErrDisallowed)
+		}
+		if delay := robots.CrawlDelay(p.UserAgent); delay > interval {
+			interval = delay
+		}
+	}
+	return c.limiter.wait(ctx, u.Host, interval)
+}
+
+// checkRedirect is the CheckRedirect function of the HTTP clients. A
+// redirect is subject to the policy of its target host: the user agent,
+// the headers and the cookies set by the policy of the previous request are
+// replaced by the ones of the new policy, so that they are not leaked to
+// another domain, and the target URL must be allowed by its robots.txt
+// rules and wait for the rate limit of its host. The proxy and the TLS
+// settings of the original request are kept.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	prev := c.Policy(via[len(via)-1].URL.Hostname())
+	p := c.Policy(req.URL.Hostname())
+	for name := range prev.Headers {
+		req.Header.Del(name)
+	}
+	req.Header.Del("Cookie")
+	p.apply(req)
+
+	ctx := req.Context()
+	if ctx.Value(robotsTxtRequestKey{}) != nil {
+		// Checking the robots.txt of a robots.txt redirect could loop.
+		return c.limiter.wait(ctx, req.URL.Host, p.MinRequestInterval)
+	}
+	hc, err := c.httpClient(p)
+	if err != nil {
+		return err
+	}
+	return c.allow(ctx, hc, p, req.URL)
+}
+
+func (c *Client) do(ctx context.Context, hc *http.Client, p Policy, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request for %#v: %w", rawURL, err)
+	}
+	p.apply(req)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing request for %#v: %w", rawURL, err)
+	}
+	return resp, nil
+}
+
+// httpClient returns the HTTP client for the proxy and the TLS setting of
+// the policy, creating it on first use.
+func (c *Client) httpClient(p Policy) (*http.Client, error) {
+	key := transportKey{proxyURL: p.ProxyURL, insecureSkipVerify: isTrue(p.InsecureSkipVerify)}
+
+	c.clientsMu.Lock()
+	defer c.clientsMu.Unlock()
+	if hc, ok := c.clients[key]; ok {
+		return hc, nil
+	}
+
+	proxyURL, err := parseProxyURL(p.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: key.insecureSkipVerify},
+	}
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	hc := &http.Client{
+		Timeout:       c.conf.RequestTimeout,
+		Transport:     transport,
+		CheckRedirect: c.checkRedirect,
+	}
+	c.clients[key] = hc
+	return hc, nil
+}
+
+// robotsTxt returns the robots.txt rules of the URL host, from the cache
+// if they are not expired.
+//
+// As recommended by RFC 9309, a missing robots.txt file (or any other
+// client error) allows everything, while server errors and network errors
+// are returned, so that the request can be retried later.
+func (c *Client) robotsTxt(ctx context.Context, hc *http.Client, p Policy, u *url.URL) (*robotstxt.Robots, error) {
+	key := u.Scheme + "://" + u.Host
+	now := time.Now()
+
+	c.robotsMu.Lock()
+	entry, ok := c.robots[key]
+	c.robotsMu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.robots, nil
+	}
+
+	err := c.limiter.wait(ctx, u.Host, p.MinRequestInterval)
+	if err != nil {
+		return nil, err
+	}
+	robotsCtx := context.WithValue(ctx, robotsTxtRequestKey{}, true)
+	robots, err := c.fetchRobotsTxt(robotsCtx, hc, p, key+"/robots.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	c.robotsMu.Lock()
+	defer c.robotsMu.Unlock()
+	if len(c.robots) >= maxCacheSize {
+		for k, e := range c.robots {
+			if now.After(e.expires) {
+				delete(c.robots, k)
+			}
+		}
+	}
+	c.robots[key] = robotsEntry{robots: robots, expires: now.Add(c.conf.RobotsTxtTTL)}
+	return robots, nil
+}
+
+func (c *Client) fetchRobotsTxt(ctx context.Context, hc *http.Client, p Policy, robotsURL string) (_ *robotstxt.Robots, err error) {
+	resp, err := c.do(ctx, hc, p, robotsURL)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching robots.txt: %w", err)
+	}
+	defer func() {
+		if e := resp.Body.Close(); e != nil && err == nil {
+			err = fmt.Errorf("error closing robots.txt response body: %w", e)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return nil, fmt.Errorf("request for %#v returned status code %d", robotsURL, resp.StatusCode)
+	case resp.StatusCode >= 400:
+		return &robotstxt.Robots{}, nil
+	}
+
+	robots, err := robotstxt.Parse(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading robots.txt: %w", err)
+	}
+	return robots, nil
+}
+
+// hostLimiter enforces a minimum interval between the requests to the same
+// host, by reserving the next available time slot for each request.
+type hostLimiter struct {
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func newHostLimiter() *hostLimiter {
+	return &hostLimiter{next: make(map[string]time.Time)}
+}
+
+// wait blocks until the request to the host is allowed, or the context is
+// done.
+func (l *hostLimiter) wait(ctx context.Context, host string, interval time.Duration) error {
+	if interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if len(l.next) >= maxCacheSize {
+		for h, t := range l.next {
+			if now.After(t) {
+				delete(l.next, h)
+			}
+		}
+	}
+	slot := l.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[host] = slot.Add(interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
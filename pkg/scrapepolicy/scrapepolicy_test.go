@@ -0,0 +1,273 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scrapepolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testConfig() config.WebScraper {
+	return config.WebScraper{
+		RequestTimeout:     5 * time.Second,
+		UserAgent:          "WhatsNew/1.0",
+		RespectRobotsTxt:   true,
+		RobotsTxtTTL:       time.Hour,
+		MinRequestInterval: 0,
+	}
+}
+
+func TestClient_Policy(t *testing.T) {
+	t.Parallel()
+
+	c := NewClient(testConfig())
+	c.SetPolicies([]Policy{
+		FromConfig(config.DomainPolicy{Domain: "Example.com.", UserAgent: "Agent/1", MinRequestInterval: time.Second}),
+		FromConfig(config.DomainPolicy{Domain: "news.example.com", IgnoreRobotsTxt: boolPtr(true)}),
+		FromConfig(config.DomainPolicy{Domain: "example.com", UserAgent: "Agent/2"}),
+	})
+
+	assert.Equal(t, Policy{
+		UserAgent:          "WhatsNew/1.0",
+		IgnoreRobotsTxt:    boolPtr(false),
+		InsecureSkipVerify: boolPtr(false),
+	}, c.Policy("example.org"))
+	assert.Equal(t, Policy{
+		Domain:             "example.com",
+		UserAgent:          "Agent/2",
+		IgnoreRobotsTxt:    boolPtr(false),
+		InsecureSkipVerify: boolPtr(false),
+	}, c.Policy("www.example.com"))
+	assert.Equal(t, Policy{
+		Domain:             "news.example.com",
+		UserAgent:          "WhatsNew/1.0",
+		IgnoreRobotsTxt:    boolPtr(true),
+		InsecureSkipVerify: boolPtr(false),
+	}, c.Policy("NEWS.example.com"))
+	assert.Equal(t, "", c.Policy("badexample.com").Domain)
+}
+
+func TestClient_Policy_OverridesDefaults(t *testing.T) {
+	t.Parallel()
+
+	conf := testConfig()
+	conf.RespectRobotsTxt = false
+	conf.InsecureSkipVerify = true
+	c := NewClient(conf)
+	c.SetPolicies([]Policy{
+		FromConfig(config.DomainPolicy{Domain: "strict.example.com", IgnoreRobotsTxt: boolPtr(false), InsecureSkipVerify: boolPtr(false)}),
+		FromConfig(config.DomainPolicy{Domain: "example.com"}),
+	})
+
+	// A domain policy can tighten the defaults...
+	strict := c.Policy("strict.example.com")
+	assert.Equal(t, boolPtr(false), strict.IgnoreRobotsTxt)
+	assert.Equal(t, boolPtr(false), strict.InsecureSkipVerify)
+
+	// ...while unset values inherit them.
+	inherited := c.Policy("www.example.com")
+	assert.Equal(t, boolPtr(true), inherited.IgnoreRobotsTxt)
+	assert.Equal(t, boolPtr(true), inherited.InsecureSkipVerify)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := Policy{
+		Domain:   "example.com",
+		Headers:  map[string]string{"Accept-Language": "en"},
+		Cookies:  map[string]string{"consent": "yes"},
+		ProxyURL: "socks5://127.0.0.1:1080",
+	}
+	assert.NoError(t, valid.Validate())
+
+	invalid := []Policy{
+		{},
+		{Domain: "https://example.com/"},
+		{Domain: "example.com", MinRequestInterval: -time.Second},
+		{Domain: "example.com", Headers: map[string]string{"Bad Header": "x"}},
+		{Domain: "example.com", Cookies: map[string]string{"a;b": "x"}},
+		{Domain: "example.com", ProxyURL: "ftp://proxy.example.com"},
+		{Domain: "example.com", ProxyURL: "http://"},
+	}
+	for _, p := range invalid {
+		assert.Error(t, p.Validate(), "%+v", p)
+	}
+}
+
+func TestClient_Get(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r)
+		mu.Unlock()
+		if r.URL.Path == "/robots.txt" {
+			_, _ = fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+			return
+		}
+		_, _ = fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	c := NewClient(testConfig())
+	c.SetPolicies([]Policy{{
+		Domain:  u.Hostname(),
+		Headers: map[string]string{"Accept-Language": "it"},
+		Cookies: map[string]string{"consent": "yes", "a": "1"},
+	}})
+
+	resp, err := c.Get(context.Background(), server.URL+"/news?id=1")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	_, err = c.Get(context.Background(), server.URL+"/private/1")
+	assert.True(t, errors.Is(err, ErrDisallowed))
+
+	resp, err = c.Get(context.Background(), server.URL+"/news?id=2")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, requests, 3) // the robots.txt is cached
+	assert.Equal(t, "/robots.txt", requests[0].URL.Path)
+	assert.Equal(t, "/news", requests[1].URL.Path)
+	assert.Equal(t, "WhatsNew/1.0", requests[1].Header.Get("User-Agent"))
+	assert.Equal(t, "it", requests[1].Header.Get("Accept-Language"))
+	assert.Equal(t, "a=1; consent=yes", requests[1].Header.Get("Cookie"))
+}
+
+func TestClient_Get_RobotsTxtErrors(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(robotsStatus int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/robots.txt" {
+				w.WriteHeader(robotsStatus)
+				return
+			}
+			_, _ = fmt.Fprint(w, "ok")
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	// A missing robots.txt allows everything.
+	server := newServer(http.StatusNotFound)
+	resp, err := NewClient(testConfig()).Get(context.Background(), server.URL+"/private")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	server = newServer(http.StatusServiceUnavailable)
+	_, err = NewClient(testConfig()).Get(context.Background(), server.URL+"/private")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrDisallowed))
+}
+
+func TestClient_Get_Redirects(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var targetRequests []*http.Request
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		targetRequests = append(targetRequests, r)
+		mu.Unlock()
+		if r.URL.Path == "/robots.txt" {
+			_, _ = fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+			return
+		}
+		_, _ = fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(target.Close)
+
+	targetURL, err := url.Parse(target.URL)
+	require.NoError(t, err)
+	// The target is reached by a different host name, so that another
+	// policy applies.
+	targetBase := "http://localhost:" + targetURL.Port()
+
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, targetBase+r.URL.Path, http.StatusFound)
+	}))
+	t.Cleanup(source.Close)
+
+	sourceURL, err := url.Parse(source.URL)
+	require.NoError(t, err)
+
+	c := NewClient(testConfig())
+	c.SetPolicies([]Policy{{
+		Domain:          sourceURL.Hostname(),
+		UserAgent:       "Agent/1",
+		Headers:         map[string]string{"X-Api-Key": "secret"},
+		Cookies:         map[string]string{"session": "secret"},
+		IgnoreRobotsTxt: boolPtr(true),
+	}})
+
+	resp, err := c.Get(context.Background(), source.URL+"/news")
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	_, err = c.Get(context.Background(), source.URL+"/private/1")
+	assert.True(t, errors.Is(err, ErrDisallowed))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, targetRequests, 2)
+	assert.Equal(t, "/robots.txt", targetRequests[0].URL.Path)
+	assert.Equal(t, "/news", targetRequests[1].URL.Path)
+	assert.Equal(t, "WhatsNew/1.0", targetRequests[1].Header.Get("User-Agent"))
+	assert.Empty(t, targetRequests[1].Header.Get("X-Api-Key"))
+	assert.Empty(t, targetRequests[1].Header.Get("Cookie"))
+}
+
+func TestHostLimiter(t *testing.T) {
+	t.Parallel()
+
+	l := newHostLimiter()
+	const interval = 50 * time.Millisecond
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, l.wait(context.Background(), "example.com", interval))
+		}()
+	}
+	wg.Wait()
+	assert.GreaterOrEqual(t, time.Since(start), 2*interval)
+
+	// Other hosts are not affected.
+	start = time.Now()
+	require.NoError(t, l.wait(context.Background(), "example.org", interval))
+	assert.Less(t, time.Since(start), interval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, l.wait(ctx, "example.net", time.Hour))
+	assert.Error(t, l.wait(ctx, "example.net", time.Hour))
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package scrapestats provides statistics about the scraping outcomes of
+// the WebResources.
+package scrapestats
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// UnsuccessfulStatuses are the scrape statuses of the WebResources whose
+// page could not be scraped. Blocked WebResources might still have a
+// WebArticle, created from their feed item.
+var UnsuccessfulStatuses = []models.ScrapeStatus{
+	models.SkippedScrape,
+	models.BlockedScrape,
+	models.FailedScrape,
+	models.RetryingScrape,
+}
+
+// Failure is the number of WebResources with the same domain, scrape
+// status, reason and HTTP status code.
+type Failure struct {
+	Domain        string
+	Status        models.ScrapeStatus
+	Reason        models.ScrapeReason
+	HTTPStatus    int
+	Count         int64
+	LastAttemptAt sql.NullTime
+}
+
+// Filter restricts the WebResources counted by Failures.
+type Filter struct {
+	// Status, if not empty, must be one of UnsuccessfulStatuses. By
+	// default, all of them are counted.
+	Status models.ScrapeStatus
+	// Domain, if not empty, also includes its subdomains.
+	Domain string
+	// Since, if not zero, excludes the WebResources whose last scraping
+	// attempt is older.
+	Since time.Time
+	// Limit is the maximum number of results (zero means no limit).
+	Limit int
+}
+
+// domainPattern matches the URL of a WebResource, capturing its host
+// name. The syntax is valid for both PostgreSQL and Go regular expressions.
+const domainPattern = `^[A-Za-z][A-Za-z0-9+.-]*://(?:[^/@]*@)?([^/:?#]+)`
+
+// domainExpr extracts the lowercase host name from the URL of a
+// WebResource.
+const domainExpr = `LOWER(SUBSTRING(url FROM '` + domainPattern + `'))`
+
+// Failures counts the unsuccessful scrapings, grouped by domain, status,
+// reason and HTTP status code, in decreasing order of count.
+func Failures(tx *gorm.DB, f Filter) ([]Failure, error) {
+	statuses := UnsuccessfulStatuses
+	if len(f.Status) > 0 {
+		if !isUnsuccessful(f.Status) {
+			return nil, fmt.Errorf("invalid unsuccessful scrape status %#v", f.Status)
+		}
+		statuses = []models.ScrapeStatus{f.Status}
+	}
+
+	query := tx.Model(&models.WebResource{}).
+		Select(domainExpr+" AS domain, scrape_status AS status, scrape_reason AS reason, "+
+			"scrape_http_status AS http_status, COUNT(*) AS count, "+
+			"MAX(last_scrape_attempt_at) AS last_attempt_at").
+		Where("scrape_status IN ?", statuses)
+
+	if domain := normalizeDomain(f.Domain); len(domain) > 0 {
+		query = query.Where("("+domainExpr+" = ? OR RIGHT("+domainExpr+", ?) = ?)", domainArgs(domain)...)
+	}
+	if !f.Since.IsZero() {
+		query = query.Where("last_scrape_attempt_at >= ?", f.Since)
+	}
+	if f.Limit > 0 {
+		query = query.Limit(f.Limit)
+	}
+
+	var failures []Failure
+	res := query.
+		Group("1, 2, 3, 4").
+		Order("count DESC, domain, status, reason, http_status").
+		Scan(&failures)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error counting scrape failures: %w", res.Error)
+	}
+	return failures, nil
+}
+
+// normalizeDomain returns the domain in lowercase, without spaces and
+// leading or trailing dots.
+func normalizeDomain(domain string) string {
+	return strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+}
+
+// domainArgs returns the arguments of the domain condition of Failures,
+// which matches the domain itself, or any of its subdomains.
+func domainArgs(domain string) []interface{} {
+	return []interface{}{domain, len(domain) + 1, "." + domain}
+}
+
+func isUnsuccessful(status models.ScrapeStatus) bool {
+	for _, s := range UnsuccessfulStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
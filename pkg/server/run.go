@@ -7,6 +7,7 @@ package server
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -14,62 +15,96 @@ import (
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"net"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 )
 
+const listenerRetryDelay = 5 * time.Second
+
 // Run runs the server according to its configuration.
 func (s *Server) Run(ctx context.Context) error {
 	grpcServer := grpc.NewServer()
 	whatsnew.RegisterWhatsnewServer(grpcServer, s)
 
+	go s.listenStageNotifications(ctx)
+
+	var tlsCert tls.Certificate
+	if s.conf.TLSEnabled {
+		var err error
+		tlsCert, err = tls.LoadX509KeyPair(s.conf.TLSCert, s.conf.TLSKey)
+		if err != nil {
+			return fmt.Errorf("error loading TLS certificate: %w", err)
+		}
+	}
+
+	listener, err := net.Listen("tcp", s.conf.Address)
+	if err != nil {
+		return fmt.Errorf("TCP listen error: %w", err)
+	}
+
+	// The gateway is a client of the gRPC server, since server-streaming
+	// calls (StreamWebArticles) are not supported by the in-process
+	// handlers.
+	gwConn, err := s.dialGateway(ctx, listener.Addr(), tlsCert)
+	if err != nil {
+		_ = listener.Close()
+		return err
+	}
+	defer func() {
+		if err := gwConn.Close(); err != nil {
+			s.log.Err(err).Msg("error closing gateway gRPC connection")
+		}
+	}()
+
 	gwMux := runtime.NewServeMux()
-	err := whatsnew.RegisterWhatsnewHandlerServer(ctx, gwMux, s)
+	err = whatsnew.RegisterWhatsnewHandler(ctx, gwMux, gwConn)
 	if err != nil {
+		_ = listener.Close()
 		return fmt.Errorf("failed to register service handler: %w", err)
 	}
 
 	mux := http.NewServeMux()
 	mux.Handle("/", gwMux)
 
-	listener, err := net.Listen("tcp", s.conf.Address)
-	if err != nil {
-		return fmt.Errorf("TCP listen error: %w", err)
-	}
-
+	gh := &grpcHandler{server: grpcServer}
 	handler := cors.New(s.corsOptions()).Handler(mux)
-	handler = handlerFunc(grpcServer, handler)
+	handler = handlerFunc(gh, handler)
 
 	if s.conf.TLSEnabled {
-		return s.serveTLS(ctx, listener, handler)
+		return s.serveTLS(ctx, listener, handler, tlsCert, gh)
 	}
-	return s.serveInsecure(ctx, listener, handler)
+	return s.serveInsecure(ctx, listener, handler, gh)
 }
 
-func (s *Server) serveInsecure(ctx context.Context, listener net.Listener, handler http.Handler) error {
+func (s *Server) serveInsecure(ctx context.Context, listener net.Listener, handler http.Handler, gh *grpcHandler) error {
 	h2s := &http2.Server{}
 	h1s := &http.Server{
 		Handler: h2c.NewHandler(handler, h2s),
 	}
+	// This lets the shutdown of the server reach the HTTP/2 connections too.
+	err := http2.ConfigureServer(h1s, h2s)
+	if err != nil {
+		return fmt.Errorf("error configuring HTTP/2 server: %w", err)
+	}
 
 	s.log.Info().Msgf("Serving on %s (insecure)", s.conf.Address)
 
-	go s.shutDownServerWhenContextIsDone(ctx, h1s)
-
-	err := h1s.Serve(listener)
-	if err != nil {
-		return fmt.Errorf("server error: %w", err)
-	}
-	return nil
+	return s.serve(ctx, h1s, gh, func() error {
+		return h1s.Serve(listener)
+	})
 }
 
-func (s *Server) serveTLS(ctx context.Context, listener net.Listener, handler http.Handler) error {
-	tlsCert, err := tls.LoadX509KeyPair(s.conf.TLSCert, s.conf.TLSKey)
-	if err != nil {
-		return fmt.Errorf("error loading TLS certificate: %w", err)
-	}
-
+func (s *Server) serveTLS(
+	ctx context.Context,
+	listener net.Listener,
+	handler http.Handler,
+	tlsCert tls.Certificate,
+	gh *grpcHandler,
+) error {
 	hs := &http.Server{
 		Handler: handler,
 		TLSConfig: &tls.Config{
@@ -80,34 +115,151 @@ func (s *Server) serveTLS(ctx context.Context, listener net.Listener, handler ht
 
 	s.log.Info().Msgf("Serving on %s (TLS)", s.conf.Address)
 
-	go s.shutDownServerWhenContextIsDone(ctx, hs)
+	return s.serve(ctx, hs, gh, func() error {
+		return hs.Serve(tls.NewListener(listener, hs.TLSConfig))
+	})
+}
 
-	err = hs.Serve(tls.NewListener(listener, hs.TLSConfig))
-	if err != nil {
-		return fmt.Errorf("server error: %w", err)
+// serve runs the serve function of the HTTP server until the context is
+// done. Then, it returns only once the server is shut down.
+func (s *Server) serve(ctx context.Context, hs *http.Server, gh *grpcHandler, serveFn func() error) error {
+	shutDown := make(chan struct{})
+	go func() {
+		defer close(shutDown)
+		s.shutDownServerWhenContextIsDone(ctx, hs, gh)
+	}()
+
+	err := serveFn()
+	if err == http.ErrServerClosed {
+		<-shutDown
+		return nil
 	}
-	return nil
+	return fmt.Errorf("server error: %w", err)
 }
 
-func (s *Server) shutDownServerWhenContextIsDone(ctx context.Context, hs *http.Server) {
+// shutDownServerWhenContextIsDone gracefully shuts down the HTTP server,
+// and then the gRPC server, once the context is done.
+func (s *Server) shutDownServerWhenContextIsDone(ctx context.Context, hs *http.Server, gh *grpcHandler) {
 	<-ctx.Done()
 	s.log.Info().Msg("context done, shutting down server")
 	err := hs.Shutdown(context.Background())
 	if err != nil {
 		s.log.Err(err).Msg("server shutdown error")
 	}
+	gh.gracefulStop()
 }
 
-func handlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Handler {
+// dialGateway creates the connection of the gateway to the gRPC server,
+// at the listen address. With TLS, the certificate of the server is the
+// only trusted one.
+func (s *Server) dialGateway(ctx context.Context, addr net.Addr, tlsCert tls.Certificate) (*grpc.ClientConn, error) {
+	endpoint, err := s.gatewayEndpoint(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := grpc.WithInsecure()
+	if s.conf.TLSEnabled {
+		leaf, err := x509.ParseCertificate(tlsCert.Certificate[0])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing TLS certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		pool.AddCert(leaf)
+		conf := &tls.Config{RootCAs: pool}
+		// The listen address rarely matches the names of the certificate.
+		if len(leaf.DNSNames) > 0 {
+			conf.ServerName = leaf.DNSNames[0]
+		}
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(conf))
+	}
+
+	conn, err := grpc.DialContext(ctx, endpoint, creds)
+	if err != nil {
+		return nil, fmt.Errorf("error dialing gRPC server at %s: %w", endpoint, err)
+	}
+	return conn, nil
+}
+
+// gatewayEndpoint returns the address of the gRPC server for the gateway:
+// the host of the configured address, or "localhost" if it listens on all
+// the interfaces, and the port actually listened on.
+func (s *Server) gatewayEndpoint(addr net.Addr) (string, error) {
+	host, _, err := net.SplitHostPort(s.conf.Address)
+	if err != nil {
+		return "", fmt.Errorf("invalid server address %#v: %w", s.conf.Address, err)
+	}
+	if ip := net.ParseIP(host); len(host) == 0 || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	_, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return "", fmt.Errorf("invalid listen address %#v: %w", addr.String(), err)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// listenStageNotifications runs the stage notifications listener until the
+// context is done, restarting it in case of errors.
+func (s *Server) listenStageNotifications(ctx context.Context) {
+	for {
+		err := s.listener.Listen(ctx)
+		if err != nil {
+			s.log.Err(err).Msg("stage notifications listener error")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenerRetryDelay):
+		}
+	}
+}
+
+func handlerFunc(grpcHandler http.Handler, otherHandler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if isGRPCRequest(r) {
-			grpcServer.ServeHTTP(w, r)
+			grpcHandler.ServeHTTP(w, r)
 		} else {
 			otherHandler.ServeHTTP(w, r)
 		}
 	})
 }
 
+// grpcHandler serves the gRPC requests, keeping track of the ones in
+// progress. The gRPC server cannot drain the requests it serves through
+// ServeHTTP, so it is only stopped once they are over.
+type grpcHandler struct {
+	server  *grpc.Server
+	mu      sync.Mutex
+	stopped bool
+	active  sync.WaitGroup
+}
+
+func (h *grpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	if h.stopped {
+		h.mu.Unlock()
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	h.active.Add(1)
+	h.mu.Unlock()
+
+	defer h.active.Done()
+	h.server.ServeHTTP(w, r)
+}
+
+// gracefulStop rejects new requests, waits for the ones in progress, and
+// gracefully stops the gRPC server.
+func (h *grpcHandler) gracefulStop() {
+	h.mu.Lock()
+	h.stopped = true
+	h.mu.Unlock()
+
+	h.active.Wait()
+	h.server.GracefulStop()
+}
+
 func (s *Server) corsOptions() cors.Options {
 	return cors.Options{
 		AllowedOrigins: s.conf.AllowedOrigins,
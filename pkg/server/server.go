@@ -7,6 +7,7 @@ package server
 import (
 	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
@@ -15,16 +16,22 @@ import (
 // Server implements WhatsNew HTTP and gRPC server.
 type Server struct {
 	whatsnew.UnimplementedWhatsnewServer
-	conf config.Server
-	db   *gorm.DB
-	log  zerolog.Logger
+	conf     config.Server
+	hnswConf config.HNSW
+	db       *gorm.DB
+	log      zerolog.Logger
+	// listener provides the notifications for StreamWebArticles.
+	listener *stagenotifier.Listener
 }
 
 // New creates a new Server.
-func New(conf config.Server, db *gorm.DB) *Server {
+func New(conf config.Server, hnswConf config.HNSW, db *gorm.DB) *Server {
+	logger := log.Logger.Level(zerolog.Level(conf.LogLevel))
 	return &Server{
-		conf: conf,
-		db:   db,
-		log:  log.Logger.Level(zerolog.Level(conf.LogLevel)),
+		conf:     conf,
+		hnswConf: hnswConf,
+		db:       db,
+		log:      logger,
+		listener: stagenotifier.NewListener(db, logger),
 	}
 }
@@ -0,0 +1,302 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/apisource"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strings"
+)
+
+// GetApiSources gets all APISources.
+func (s *Server) GetApiSources(
+	_ context.Context,
+	req *whatsnew.GetApiSourcesRequest,
+) (*whatsnew.GetApiSourcesResponse, error) {
+	query := s.db.Order("id")
+	if len(req.GetAfter()) > 0 {
+		query = query.Where("id > ?", req.GetAfter())
+	}
+	if req.GetFirst() > 0 {
+		query = query.Limit(int(req.GetFirst()))
+	}
+
+	var sources []models.APISource
+	ret := query.Find(&sources)
+	if ret.Error != nil {
+		return &whatsnew.GetApiSourcesResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	respSources := make([]*whatsnew.ApiSource, len(sources))
+	for i, src := range sources {
+		var err error
+		respSources[i], err = makeAPIAPISource(src)
+		if err != nil {
+			return &whatsnew.GetApiSourcesResponse{Errors: s.makeErrors(req, err)}, nil
+		}
+	}
+
+	resp := &whatsnew.GetApiSourcesResponse{
+		Data: &whatsnew.GetApiSourcesData{
+			ApiSources: respSources,
+		},
+	}
+	return resp, nil
+}
+
+// CreateApiSources creates new APISources.
+func (s *Server) CreateApiSources(
+	_ context.Context,
+	req *whatsnew.CreateApiSourcesRequest,
+) (*whatsnew.CreateApiSourcesResponse, error) {
+	reqSources := req.GetNewApiSources().GetApiSources()
+	sources := make([]models.APISource, len(reqSources))
+	for i, reqSource := range reqSources {
+		err := setAPISourceFields(&sources[i], reqSource)
+		if err != nil {
+			return &whatsnew.CreateApiSourcesResponse{Errors: s.makeErrors(req, err)}, nil
+		}
+	}
+
+	ret := s.db.Create(&sources)
+	if ret.Error != nil {
+		return &whatsnew.CreateApiSourcesResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	ids := make([]string, len(sources))
+	for i, src := range sources {
+		ids[i] = fmt.Sprintf("%d", src.ID)
+	}
+
+	resp := &whatsnew.CreateApiSourcesResponse{
+		Data: &whatsnew.CreateApiSourcesData{
+			ApiSourceIds: ids,
+		},
+	}
+	return resp, nil
+}
+
+// CreateApiSource creates a new APISource.
+func (s *Server) CreateApiSource(
+	_ context.Context,
+	req *whatsnew.CreateApiSourceRequest,
+) (*whatsnew.CreateApiSourceResponse, error) {
+	var src models.APISource
+	err := setAPISourceFields(&src, req.GetNewApiSource())
+	if err != nil {
+		return &whatsnew.CreateApiSourceResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	ret := s.db.Create(&src)
+	if ret.Error != nil {
+		return &whatsnew.CreateApiSourceResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+	resp := &whatsnew.CreateApiSourceResponse{
+		Data: &whatsnew.CreateApiSourceData{
+			ApiSourceId: fmt.Sprintf("%d", src.ID),
+		},
+	}
+	return resp, nil
+}
+
+// GetApiSource gets an APISource.
+func (s *Server) GetApiSource(
+	_ context.Context,
+	req *whatsnew.GetApiSourceRequest,
+) (*whatsnew.GetApiSourceResponse, error) {
+	var src models.APISource
+	ret := s.db.First(&src, "id = ?", req.GetId())
+	if ret.Error != nil {
+		return &whatsnew.GetApiSourceResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	apiSource, err := makeAPIAPISource(src)
+	if err != nil {
+		return &whatsnew.GetApiSourceResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+	resp := &whatsnew.GetApiSourceResponse{
+		Data: &whatsnew.GetApiSourceData{
+			ApiSource: apiSource,
+		},
+	}
+	return resp, nil
+}
+
+// UpdateApiSource updates an APISource.
+func (s *Server) UpdateApiSource(
+	ctx context.Context,
+	req *whatsnew.UpdateApiSourceRequest,
+) (*whatsnew.UpdateApiSourceResponse, error) {
+	var src models.APISource
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&src, "id = ?", req.GetId())
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		us := req.GetUpdatedApiSource()
+
+		err := setAPISourceFields(&src, us)
+		if err != nil {
+			return err
+		}
+		src.Enabled = us.GetEnabled()
+		src.FailuresCount = int(us.GetFailuresCount())
+		src.LastError = sql.NullString{
+			String: us.GetLastError(),
+			Valid:  len(us.GetLastError()) > 0,
+		}
+
+		src.LastRetrievedAt, err = nullTimeFromString(us.GetLastRetrievedAt())
+		if err != nil {
+			return err
+		}
+
+		ret = tx.Save(&src)
+		return ret.Error
+	})
+
+	if err != nil {
+		return &whatsnew.UpdateApiSourceResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	apiSource, err := makeAPIAPISource(src)
+	if err != nil {
+		return &whatsnew.UpdateApiSourceResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+	resp := &whatsnew.UpdateApiSourceResponse{
+		Data: &whatsnew.UpdateApiSourceData{
+			ApiSource: apiSource,
+		},
+	}
+	return resp, nil
+}
+
+// DeleteApiSource deletes an APISource.
+func (s *Server) DeleteApiSource(
+	ctx context.Context,
+	req *whatsnew.DeleteApiSourceRequest,
+) (*whatsnew.DeleteApiSourceResponse, error) {
+	var src models.APISource
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&src, "id = ?", req.GetId())
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		var itemsCount int64
+		ret = tx.Model(&models.APIItem{}).Where("api_source_id = ?", src.ID).Limit(1).Count(&itemsCount)
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		if itemsCount == 0 {
+			ret = tx.Unscoped().Delete(&src)
+		} else {
+			ret = tx.Delete(&src)
+		}
+		return ret.Error
+	})
+
+	if err != nil {
+		return &whatsnew.DeleteApiSourceResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.DeleteApiSourceResponse{
+		Data: &whatsnew.DeleteApiSourceData{
+			DeletedApiSourceId: fmt.Sprintf("%d", src.ID),
+		},
+	}
+	return resp, nil
+}
+
+// apiSourceFields is implemented by both whatsnew.NewApiSource and
+// whatsnew.UpdatedApiSource.
+type apiSourceFields interface {
+	GetName() string
+	GetUrl() string
+	GetMethod() string
+	GetHeaders() []*whatsnew.ApiSourceHeader
+	GetBody() string
+	GetItemsPath() string
+	GetUrlPath() string
+	GetTitlePath() string
+	GetDescriptionPath() string
+	GetPublishedAtPath() string
+	GetPublishedAtLayout() string
+	GetPagination() string
+	GetPaginationParam() string
+	GetPaginationStart() int64
+	GetNextCursorPath() string
+	GetMaxPages() int64
+	GetLanguage() string
+}
+
+// setAPISourceFields sets the user-defined fields of an APISource, and
+// validates the result. A header with an empty value keeps its current value,
+// since header values are never returned by the API.
+func setAPISourceFields(src *models.APISource, f apiSourceFields) error {
+	current, err := src.HeadersAsMap()
+	if err != nil {
+		return err
+	}
+	headers := make(map[string]string, len(f.GetHeaders()))
+	for _, h := range f.GetHeaders() {
+		if len(h.GetName()) == 0 {
+			return fmt.Errorf("invalid empty APISource header name")
+		}
+		value := h.GetValue()
+		if len(value) == 0 {
+			value = current[h.GetName()]
+		}
+		headers[h.GetName()] = value
+	}
+	err = src.SetHeaders(headers)
+	if err != nil {
+		return err
+	}
+
+	src.Name = f.GetName()
+	src.URL = f.GetUrl()
+	src.Method = strings.ToUpper(f.GetMethod())
+	if len(src.Method) == 0 {
+		src.Method = "GET"
+	}
+	src.Body = sql.NullString{
+		String: f.GetBody(),
+		Valid:  len(f.GetBody()) > 0,
+	}
+	src.ItemsPath = f.GetItemsPath()
+	src.URLPath = f.GetUrlPath()
+	src.TitlePath = f.GetTitlePath()
+	src.DescriptionPath = f.GetDescriptionPath()
+	src.PublishedAtPath = f.GetPublishedAtPath()
+	src.PublishedAtLayout = f.GetPublishedAtLayout()
+	src.Pagination = models.APISourcePagination(f.GetPagination())
+	if len(src.Pagination) == 0 {
+		src.Pagination = models.NoPagination
+	}
+	src.PaginationParam = f.GetPaginationParam()
+	src.PaginationStart = int(f.GetPaginationStart())
+	src.NextCursorPath = f.GetNextCursorPath()
+	src.MaxPages = int(f.GetMaxPages())
+	if src.MaxPages < 1 {
+		src.MaxPages = 1
+	}
+	src.Language = makeLanguage(f.GetLanguage())
+
+	if len(src.Name) == 0 {
+		return fmt.Errorf("invalid empty APISource name")
+	}
+	return apisource.Validate(src)
+}
@@ -0,0 +1,293 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/scrapepolicy"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"strconv"
+	"time"
+)
+
+// GetDomainPolicies gets all DomainPolicies.
+func (s *Server) GetDomainPolicies(
+	_ context.Context,
+	req *whatsnew.GetDomainPoliciesRequest,
+) (*whatsnew.GetDomainPoliciesResponse, error) {
+	query := s.db.Order("id")
+	if len(req.GetAfter()) > 0 {
+		query = query.Where("id > ?", req.GetAfter())
+	}
+	if req.GetFirst() > 0 {
+		query = query.Limit(int(req.GetFirst()))
+	}
+
+	var policies []models.DomainPolicy
+	ret := query.Find(&policies)
+	if ret.Error != nil {
+		return &whatsnew.GetDomainPoliciesResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	respPolicies := make([]*whatsnew.DomainPolicy, len(policies))
+	for i, p := range policies {
+		var err error
+		respPolicies[i], err = makeAPIDomainPolicy(p)
+		if err != nil {
+			return &whatsnew.GetDomainPoliciesResponse{Errors: s.makeErrors(req, err)}, nil
+		}
+	}
+
+	resp := &whatsnew.GetDomainPoliciesResponse{
+		Data: &whatsnew.GetDomainPoliciesData{
+			DomainPolicies: respPolicies,
+		},
+	}
+	return resp, nil
+}
+
+// CreateDomainPolicies creates new DomainPolicies.
+func (s *Server) CreateDomainPolicies(
+	_ context.Context,
+	req *whatsnew.CreateDomainPoliciesRequest,
+) (*whatsnew.CreateDomainPoliciesResponse, error) {
+	reqPolicies := req.GetNewDomainPolicies().GetDomainPolicies()
+	policies := make([]models.DomainPolicy, len(reqPolicies))
+	for i, reqPolicy := range reqPolicies {
+		err := setDomainPolicyFields(&policies[i], reqPolicy)
+		if err != nil {
+			return &whatsnew.CreateDomainPoliciesResponse{Errors: s.makeErrors(req, err)}, nil
+		}
+	}
+
+	ret := s.db.Create(&policies)
+	if ret.Error != nil {
+		return &whatsnew.CreateDomainPoliciesResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	ids := make([]string, len(policies))
+	for i, p := range policies {
+		ids[i] = fmt.Sprintf("%d", p.ID)
+	}
+
+	resp := &whatsnew.CreateDomainPoliciesResponse{
+		Data: &whatsnew.CreateDomainPoliciesData{
+			DomainPolicyIds: ids,
+		},
+	}
+	return resp, nil
+}
+
+// CreateDomainPolicy creates a new DomainPolicy.
+func (s *Server) CreateDomainPolicy(
+	_ context.Context,
+	req *whatsnew.CreateDomainPolicyRequest,
+) (*whatsnew.CreateDomainPolicyResponse, error) {
+	var p models.DomainPolicy
+	err := setDomainPolicyFields(&p, req.GetNewDomainPolicy())
+	if err != nil {
+		return &whatsnew.CreateDomainPolicyResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	ret := s.db.Create(&p)
+	if ret.Error != nil {
+		return &whatsnew.CreateDomainPolicyResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+	resp := &whatsnew.CreateDomainPolicyResponse{
+		Data: &whatsnew.CreateDomainPolicyData{
+			DomainPolicyId: fmt.Sprintf("%d", p.ID),
+		},
+	}
+	return resp, nil
+}
+
+// GetDomainPolicy gets a DomainPolicy.
+func (s *Server) GetDomainPolicy(
+	_ context.Context,
+	req *whatsnew.GetDomainPolicyRequest,
+) (*whatsnew.GetDomainPolicyResponse, error) {
+	var p models.DomainPolicy
+	ret := s.db.First(&p, "id = ?", req.GetId())
+	if ret.Error != nil {
+		return &whatsnew.GetDomainPolicyResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	apiPolicy, err := makeAPIDomainPolicy(p)
+	if err != nil {
+		return &whatsnew.GetDomainPolicyResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.GetDomainPolicyResponse{
+		Data: &whatsnew.GetDomainPolicyData{
+			DomainPolicy: apiPolicy,
+		},
+	}
+	return resp, nil
+}
+
+// UpdateDomainPolicy updates a DomainPolicy.
+func (s *Server) UpdateDomainPolicy(
+	ctx context.Context,
+	req *whatsnew.UpdateDomainPolicyRequest,
+) (*whatsnew.UpdateDomainPolicyResponse, error) {
+	var p models.DomainPolicy
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&p, "id = ?", req.GetId())
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		err := setDomainPolicyFields(&p, req.GetUpdatedDomainPolicy())
+		if err != nil {
+			return err
+		}
+
+		ret = tx.Save(&p)
+		return ret.Error
+	})
+
+	if err != nil {
+		return &whatsnew.UpdateDomainPolicyResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	apiPolicy, err := makeAPIDomainPolicy(p)
+	if err != nil {
+		return &whatsnew.UpdateDomainPolicyResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.UpdateDomainPolicyResponse{
+		Data: &whatsnew.UpdateDomainPolicyData{
+			DomainPolicy: apiPolicy,
+		},
+	}
+	return resp, nil
+}
+
+// DeleteDomainPolicy deletes a DomainPolicy.
+func (s *Server) DeleteDomainPolicy(
+	ctx context.Context,
+	req *whatsnew.DeleteDomainPolicyRequest,
+) (*whatsnew.DeleteDomainPolicyResponse, error) {
+	var p models.DomainPolicy
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&p, "id = ?", req.GetId())
+		if ret.Error != nil {
+			return ret.Error
+		}
+		// Policies are not referenced by other models, and the unique
+		// domain must be available again after deletion.
+		ret = tx.Unscoped().Delete(&p)
+		return ret.Error
+	})
+
+	if err != nil {
+		return &whatsnew.DeleteDomainPolicyResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.DeleteDomainPolicyResponse{
+		Data: &whatsnew.DeleteDomainPolicyData{
+			DeletedDomainPolicyId: fmt.Sprintf("%d", p.ID),
+		},
+	}
+	return resp, nil
+}
+
+// domainPolicyFields is implemented by both whatsnew.NewDomainPolicy
+// and whatsnew.UpdatedDomainPolicy.
+type domainPolicyFields interface {
+	GetDomain() string
+	GetUserAgent() string
+	GetHeaders() []*whatsnew.DomainPolicyHeader
+	GetCookies() []*whatsnew.DomainPolicyCookie
+	GetProxyUrl() string
+	GetMinRequestInterval() string
+	GetIgnoreRobotsTxt() string
+	GetInsecureSkipVerify() string
+}
+
+// setDomainPolicyFields sets the user-defined fields of a DomainPolicy, and
+// validates the result. A header or cookie with an empty value keeps its
+// current value, since header and cookie values are never returned by the API.
+func setDomainPolicyFields(p *models.DomainPolicy, f domainPolicyFields) error {
+	currentHeaders, err := p.HeadersAsMap()
+	if err != nil {
+		return err
+	}
+	headers := make(map[string]string, len(f.GetHeaders()))
+	for _, h := range f.GetHeaders() {
+		value := h.GetValue()
+		if len(value) == 0 {
+			value = currentHeaders[h.GetName()]
+		}
+		headers[h.GetName()] = value
+	}
+	err = p.SetHeaders(headers)
+	if err != nil {
+		return err
+	}
+
+	currentCookies, err := p.CookiesAsMap()
+	if err != nil {
+		return err
+	}
+	cookies := make(map[string]string, len(f.GetCookies()))
+	for _, c := range f.GetCookies() {
+		value := c.GetValue()
+		if len(value) == 0 {
+			value = currentCookies[c.GetName()]
+		}
+		cookies[c.GetName()] = value
+	}
+	err = p.SetCookies(cookies)
+	if err != nil {
+		return err
+	}
+
+	p.MinRequestInterval = 0
+	if len(f.GetMinRequestInterval()) > 0 {
+		p.MinRequestInterval, err = time.ParseDuration(f.GetMinRequestInterval())
+		if err != nil {
+			return fmt.Errorf("invalid DomainPolicy min request interval: %w", err)
+		}
+	}
+
+	p.Domain = scrapepolicy.NormalizeDomain(f.GetDomain())
+	p.UserAgent = f.GetUserAgent()
+	p.ProxyURL = f.GetProxyUrl()
+	p.IgnoreRobotsTxt, err = parseOptionalBool(f.GetIgnoreRobotsTxt())
+	if err != nil {
+		return fmt.Errorf("invalid DomainPolicy ignore robots.txt value: %w", err)
+	}
+	p.InsecureSkipVerify, err = parseOptionalBool(f.GetInsecureSkipVerify())
+	if err != nil {
+		return fmt.Errorf("invalid DomainPolicy insecure skip verify value: %w", err)
+	}
+
+	sp, err := scrapepolicy.FromModel(*p)
+	if err != nil {
+		return err
+	}
+	return sp.Validate()
+}
+
+// parseOptionalBool parses a boolean value, such as "true" or "false". An
+// empty string results in a NULL value.
+func parseOptionalBool(s string) (sql.NullBool, error) {
+	if len(s) == 0 {
+		return sql.NullBool{}, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return sql.NullBool{}, err
+	}
+	return sql.NullBool{Bool: b, Valid: true}, nil
+}
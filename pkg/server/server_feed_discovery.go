@@ -0,0 +1,54 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/feeddiscovery"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"gorm.io/gorm"
+)
+
+// DiscoverFeeds discovers the feeds of a website, optionally creating the
+// new ones.
+func (s *Server) DiscoverFeeds(
+	ctx context.Context,
+	req *whatsnew.DiscoverFeedsRequest,
+) (*whatsnew.DiscoverFeedsResponse, error) {
+	q := req.GetFeedDiscoveryQuery()
+
+	feeds, err := feeddiscovery.New(s.conf.FeedDiscovery).Discover(ctx, q.GetUrl())
+	if err != nil {
+		return &whatsnew.DiscoverFeedsResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	var stored []feeddiscovery.StoredFeed
+	if q.GetCreate() {
+		err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+			stored, err = feeddiscovery.Store(tx, feeds, q.GetTags())
+			return err
+		})
+		if err != nil {
+			return &whatsnew.DiscoverFeedsResponse{Errors: s.makeErrors(req, err)}, nil
+		}
+	} else {
+		stored = make([]feeddiscovery.StoredFeed, len(feeds))
+		for i, feed := range feeds {
+			stored[i] = feeddiscovery.StoredFeed{Feed: feed}
+		}
+	}
+
+	discoveredFeeds := make([]*whatsnew.DiscoveredFeed, len(stored))
+	for i, sf := range stored {
+		discoveredFeeds[i] = makeAPIDiscoveredFeed(sf)
+	}
+
+	resp := &whatsnew.DiscoverFeedsResponse{
+		Data: &whatsnew.DiscoverFeedsData{
+			DiscoveredFeeds: discoveredFeeds,
+		},
+	}
+	return resp, nil
+}
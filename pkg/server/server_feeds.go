@@ -12,6 +12,7 @@ import (
 	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"strings"
 )
 
 // GetFeeds gets all Feeds.
@@ -20,6 +21,9 @@ func (s *Server) GetFeeds(_ context.Context, req *whatsnew.GetFeedsRequest) (*wh
 	if len(req.GetAfter()) > 0 {
 		query = query.Where("id > ?", req.GetAfter())
 	}
+	if len(req.GetTag()) > 0 {
+		query = query.Where("? = ANY(tags)", req.GetTag())
+	}
 	if req.GetFirst() > 0 {
 		query = query.Limit(int(req.GetFirst()))
 	}
@@ -32,7 +36,11 @@ func (s *Server) GetFeeds(_ context.Context, req *whatsnew.GetFeedsRequest) (*wh
 
 	respFeeds := make([]*whatsnew.Feed, len(feeds))
 	for i, feed := range feeds {
-		respFeeds[i] = makeAPIFeed(feed)
+		var err error
+		respFeeds[i], err = makeAPIFeed(feed)
+		if err != nil {
+			return &whatsnew.GetFeedsResponse{Errors: s.makeErrors(req, err)}, nil
+		}
 	}
 
 	resp := &whatsnew.GetFeedsResponse{
@@ -52,9 +60,11 @@ func (s *Server) CreateFeeds(
 
 	feeds := make([]models.Feed, len(reqFeeds))
 	for i, reqFeed := range reqFeeds {
-		feeds[i] = models.Feed{
-			URL: reqFeed.GetUrl(),
+		feed, err := makeNewFeed(reqFeed)
+		if err != nil {
+			return &whatsnew.CreateFeedsResponse{Errors: s.makeErrors(req, err)}, nil
 		}
+		feeds[i] = feed
 	}
 
 	ret := s.db.Create(&feeds)
@@ -77,8 +87,9 @@ func (s *Server) CreateFeeds(
 
 // CreateFeed creates a new Feed.
 func (s *Server) CreateFeed(_ context.Context, req *whatsnew.CreateFeedRequest) (*whatsnew.CreateFeedResponse, error) {
-	feed := models.Feed{
-		URL: req.GetNewFeed().GetUrl(),
+	feed, err := makeNewFeed(req.GetNewFeed())
+	if err != nil {
+		return &whatsnew.CreateFeedResponse{Errors: s.makeErrors(req, err)}, nil
 	}
 	ret := s.db.Create(&feed)
 	if ret.Error != nil {
@@ -99,9 +110,13 @@ func (s *Server) GetFeed(_ context.Context, req *whatsnew.GetFeedRequest) (*what
 	if ret.Error != nil {
 		return &whatsnew.GetFeedResponse{Errors: s.makeErrors(req, ret.Error)}, nil
 	}
+	apiFeed, err := makeAPIFeed(feed)
+	if err != nil {
+		return &whatsnew.GetFeedResponse{Errors: s.makeErrors(req, err)}, nil
+	}
 	resp := &whatsnew.GetFeedResponse{
 		Data: &whatsnew.GetFeedData{
-			Feed: makeAPIFeed(feed),
+			Feed: apiFeed,
 		},
 	}
 	return resp, nil
@@ -136,6 +151,11 @@ func (s *Server) UpdateFeed(
 			return err
 		}
 
+		err = setFeedMetadata(&feed, uf.GetTags(), uf.GetLanguage(), uf.GetFetchIntervalSeconds())
+		if err != nil {
+			return err
+		}
+
 		ret = tx.Save(&feed)
 		return ret.Error
 	})
@@ -144,9 +164,13 @@ func (s *Server) UpdateFeed(
 		return &whatsnew.UpdateFeedResponse{Errors: s.makeErrors(req, err)}, nil
 	}
 
+	apiFeed, err := makeAPIFeed(feed)
+	if err != nil {
+		return &whatsnew.UpdateFeedResponse{Errors: s.makeErrors(req, err)}, nil
+	}
 	resp := &whatsnew.UpdateFeedResponse{
 		Data: &whatsnew.UpdateFeedData{
-			Feed: makeAPIFeed(feed),
+			Feed: apiFeed,
 		},
 	}
 	return resp, nil
@@ -190,3 +214,35 @@ func (s *Server) DeleteFeed(
 	}
 	return resp, nil
 }
+
+func makeNewFeed(nf *whatsnew.NewFeed) (models.Feed, error) {
+	feed := models.Feed{
+		URL: nf.GetUrl(),
+	}
+	err := setFeedMetadata(&feed, nf.GetTags(), nf.GetLanguage(), nf.GetFetchIntervalSeconds())
+	return feed, err
+}
+
+// setFeedMetadata sets the user-defined metadata of a Feed. An empty
+// language or a zero interval are stored as null.
+func setFeedMetadata(feed *models.Feed, tags []string, language string, fetchIntervalSeconds int64) error {
+	if fetchIntervalSeconds < 0 {
+		return fmt.Errorf("invalid negative fetch interval: %d", fetchIntervalSeconds)
+	}
+
+	err := feed.SetTags(tags)
+	if err != nil {
+		return err
+	}
+
+	feed.Language = makeLanguage(language)
+	feed.FetchIntervalSeconds = sql.NullInt64{Int64: fetchIntervalSeconds, Valid: fetchIntervalSeconds > 0}
+	return nil
+}
+
+// makeLanguage normalizes a language code, converting an empty value to
+// null.
+func makeLanguage(language string) sql.NullString {
+	language = strings.ToLower(strings.TrimSpace(language))
+	return sql.NullString{String: language, Valid: len(language) > 0}
+}
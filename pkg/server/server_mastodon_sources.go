@@ -0,0 +1,247 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/url"
+	"strings"
+)
+
+// GetMastodonSources gets all MastodonSources.
+func (s *Server) GetMastodonSources(
+	_ context.Context,
+	req *whatsnew.GetMastodonSourcesRequest,
+) (*whatsnew.GetMastodonSourcesResponse, error) {
+	query := s.db.Order("id")
+	if len(req.GetAfter()) > 0 {
+		query = query.Where("id > ?", req.GetAfter())
+	}
+	if req.GetFirst() > 0 {
+		query = query.Limit(int(req.GetFirst()))
+	}
+
+	var sources []models.MastodonSource
+	ret := query.Find(&sources)
+	if ret.Error != nil {
+		return &whatsnew.GetMastodonSourcesResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	respSources := make([]*whatsnew.MastodonSource, len(sources))
+	for i, src := range sources {
+		respSources[i] = makeAPIMastodonSource(src)
+	}
+
+	resp := &whatsnew.GetMastodonSourcesResponse{
+		Data: &whatsnew.GetMastodonSourcesData{
+			MastodonSources: respSources,
+		},
+	}
+	return resp, nil
+}
+
+// CreateMastodonSources creates new MastodonSources.
+func (s *Server) CreateMastodonSources(
+	_ context.Context,
+	req *whatsnew.CreateMastodonSourcesRequest,
+) (*whatsnew.CreateMastodonSourcesResponse, error) {
+	reqSources := req.GetNewMastodonSources().GetMastodonSources()
+	sources := make([]models.MastodonSource, len(reqSources))
+	for i, reqSource := range reqSources {
+		err := setMastodonSourceFields(&sources[i], reqSource)
+		if err != nil {
+			return &whatsnew.CreateMastodonSourcesResponse{Errors: s.makeErrors(req, err)}, nil
+		}
+	}
+
+	ret := s.db.Create(&sources)
+	if ret.Error != nil {
+		return &whatsnew.CreateMastodonSourcesResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	ids := make([]string, len(sources))
+	for i, src := range sources {
+		ids[i] = fmt.Sprintf("%d", src.ID)
+	}
+
+	resp := &whatsnew.CreateMastodonSourcesResponse{
+		Data: &whatsnew.CreateMastodonSourcesData{
+			MastodonSourceIds: ids,
+		},
+	}
+	return resp, nil
+}
+
+// CreateMastodonSource creates a new MastodonSource.
+func (s *Server) CreateMastodonSource(
+	_ context.Context,
+	req *whatsnew.CreateMastodonSourceRequest,
+) (*whatsnew.CreateMastodonSourceResponse, error) {
+	var src models.MastodonSource
+	err := setMastodonSourceFields(&src, req.GetNewMastodonSource())
+	if err != nil {
+		return &whatsnew.CreateMastodonSourceResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	ret := s.db.Create(&src)
+	if ret.Error != nil {
+		return &whatsnew.CreateMastodonSourceResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+	resp := &whatsnew.CreateMastodonSourceResponse{
+		Data: &whatsnew.CreateMastodonSourceData{
+			MastodonSourceId: fmt.Sprintf("%d", src.ID),
+		},
+	}
+	return resp, nil
+}
+
+// GetMastodonSource gets a MastodonSource.
+func (s *Server) GetMastodonSource(
+	_ context.Context,
+	req *whatsnew.GetMastodonSourceRequest,
+) (*whatsnew.GetMastodonSourceResponse, error) {
+	var src models.MastodonSource
+	ret := s.db.First(&src, "id = ?", req.GetId())
+	if ret.Error != nil {
+		return &whatsnew.GetMastodonSourceResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	resp := &whatsnew.GetMastodonSourceResponse{
+		Data: &whatsnew.GetMastodonSourceData{
+			MastodonSource: makeAPIMastodonSource(src),
+		},
+	}
+	return resp, nil
+}
+
+// UpdateMastodonSource updates a MastodonSource.
+func (s *Server) UpdateMastodonSource(
+	ctx context.Context,
+	req *whatsnew.UpdateMastodonSourceRequest,
+) (*whatsnew.UpdateMastodonSourceResponse, error) {
+	var src models.MastodonSource
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&src, "id = ?", req.GetId())
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		us := req.GetUpdatedMastodonSource()
+		err := setMastodonSourceFields(&src, us)
+		if err != nil {
+			return err
+		}
+		src.Enabled = us.GetEnabled()
+		src.FailuresCount = int(us.GetFailuresCount())
+		src.LastError = sql.NullString{
+			String: us.GetLastError(),
+			Valid:  len(us.GetLastError()) > 0,
+		}
+
+		src.LastRetrievedAt, err = nullTimeFromString(us.GetLastRetrievedAt())
+		if err != nil {
+			return err
+		}
+
+		ret = tx.Save(&src)
+		return ret.Error
+	})
+
+	if err != nil {
+		return &whatsnew.UpdateMastodonSourceResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.UpdateMastodonSourceResponse{
+		Data: &whatsnew.UpdateMastodonSourceData{
+			MastodonSource: makeAPIMastodonSource(src),
+		},
+	}
+	return resp, nil
+}
+
+// DeleteMastodonSource deletes a MastodonSource.
+func (s *Server) DeleteMastodonSource(
+	ctx context.Context,
+	req *whatsnew.DeleteMastodonSourceRequest,
+) (*whatsnew.DeleteMastodonSourceResponse, error) {
+	var src models.MastodonSource
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&src, "id = ?", req.GetId())
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		var postsCount int64
+		ret = tx.Model(&models.MastodonPost{}).Where("mastodon_source_id = ?", src.ID).Limit(1).Count(&postsCount)
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		if postsCount == 0 {
+			ret = tx.Unscoped().Delete(&src)
+		} else {
+			ret = tx.Delete(&src)
+		}
+		return ret.Error
+	})
+
+	if err != nil {
+		return &whatsnew.DeleteMastodonSourceResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.DeleteMastodonSourceResponse{
+		Data: &whatsnew.DeleteMastodonSourceData{
+			DeletedMastodonSourceId: fmt.Sprintf("%d", src.ID),
+		},
+	}
+	return resp, nil
+}
+
+// mastodonSourceFields is implemented by both whatsnew.NewMastodonSource
+// and whatsnew.UpdatedMastodonSource.
+type mastodonSourceFields interface {
+	GetInstanceUrl() string
+	GetType() string
+	GetText() string
+}
+
+// setMastodonSourceFields sets the user-defined fields of a MastodonSource,
+// and validates the result. The instance URL loses any trailing slash, and
+// the text any leading "@" or "#", so that the same source is always stored
+// the same way.
+func setMastodonSourceFields(src *models.MastodonSource, f mastodonSourceFields) error {
+	src.InstanceURL = strings.TrimSuffix(strings.TrimSpace(f.GetInstanceUrl()), "/")
+	src.Type = models.MastodonSourceType(f.GetType())
+
+	src.Text = strings.TrimSpace(f.GetText())
+	switch src.Type {
+	case models.AccountMastodonSource:
+		src.Text = strings.TrimPrefix(src.Text, "@")
+	case models.HashtagMastodonSource:
+		src.Text = strings.TrimPrefix(src.Text, "#")
+	default:
+		return fmt.Errorf("invalid MastodonSource type %#v", src.Type)
+	}
+	if len(src.Text) == 0 {
+		return fmt.Errorf("invalid empty MastodonSource text")
+	}
+
+	u, err := url.Parse(src.InstanceURL)
+	if err != nil {
+		return fmt.Errorf("invalid MastodonSource instance URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("invalid MastodonSource instance URL %#v", src.InstanceURL)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"regexp"
+	"strings"
+)
+
+// GetNewsletterSources gets all NewsletterSources.
+func (s *Server) GetNewsletterSources(
+	_ context.Context,
+	req *whatsnew.GetNewsletterSourcesRequest,
+) (*whatsnew.GetNewsletterSourcesResponse, error) {
+	query := s.db.Order("id")
+	if len(req.GetAfter()) > 0 {
+		query = query.Where("id > ?", req.GetAfter())
+	}
+	if req.GetFirst() > 0 {
+		query = query.Limit(int(req.GetFirst()))
+	}
+
+	var sources []models.NewsletterSource
+	ret := query.Find(&sources)
+	if ret.Error != nil {
+		return &whatsnew.GetNewsletterSourcesResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	respSources := make([]*whatsnew.NewsletterSource, len(sources))
+	for i, src := range sources {
+		respSources[i] = makeAPINewsletterSource(src)
+	}
+
+	resp := &whatsnew.GetNewsletterSourcesResponse{
+		Data: &whatsnew.GetNewsletterSourcesData{
+			NewsletterSources: respSources,
+		},
+	}
+	return resp, nil
+}
+
+// CreateNewsletterSources creates new NewsletterSources.
+func (s *Server) CreateNewsletterSources(
+	_ context.Context,
+	req *whatsnew.CreateNewsletterSourcesRequest,
+) (*whatsnew.CreateNewsletterSourcesResponse, error) {
+	reqSources := req.GetNewNewsletterSources().GetNewsletterSources()
+	sources := make([]models.NewsletterSource, len(reqSources))
+	for i, reqSource := range reqSources {
+		err := setNewsletterSourceFields(&sources[i], reqSource)
+		if err != nil {
+			return &whatsnew.CreateNewsletterSourcesResponse{Errors: s.makeErrors(req, err)}, nil
+		}
+	}
+
+	ret := s.db.Create(&sources)
+	if ret.Error != nil {
+		return &whatsnew.CreateNewsletterSourcesResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	ids := make([]string, len(sources))
+	for i, src := range sources {
+		ids[i] = fmt.Sprintf("%d", src.ID)
+	}
+
+	resp := &whatsnew.CreateNewsletterSourcesResponse{
+		Data: &whatsnew.CreateNewsletterSourcesData{
+			NewsletterSourceIds: ids,
+		},
+	}
+	return resp, nil
+}
+
+// CreateNewsletterSource creates a new NewsletterSource.
+func (s *Server) CreateNewsletterSource(
+	_ context.Context,
+	req *whatsnew.CreateNewsletterSourceRequest,
+) (*whatsnew.CreateNewsletterSourceResponse, error) {
+	var src models.NewsletterSource
+	err := setNewsletterSourceFields(&src, req.GetNewNewsletterSource())
+	if err != nil {
+		return &whatsnew.CreateNewsletterSourceResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	ret := s.db.Create(&src)
+	if ret.Error != nil {
+		return &whatsnew.CreateNewsletterSourceResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+	resp := &whatsnew.CreateNewsletterSourceResponse{
+		Data: &whatsnew.CreateNewsletterSourceData{
+			NewsletterSourceId: fmt.Sprintf("%d", src.ID),
+		},
+	}
+	return resp, nil
+}
+
+// GetNewsletterSource gets a NewsletterSource.
+func (s *Server) GetNewsletterSource(
+	_ context.Context,
+	req *whatsnew.GetNewsletterSourceRequest,
+) (*whatsnew.GetNewsletterSourceResponse, error) {
+	var src models.NewsletterSource
+	ret := s.db.First(&src, "id = ?", req.GetId())
+	if ret.Error != nil {
+		return &whatsnew.GetNewsletterSourceResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	resp := &whatsnew.GetNewsletterSourceResponse{
+		Data: &whatsnew.GetNewsletterSourceData{
+			NewsletterSource: makeAPINewsletterSource(src),
+		},
+	}
+	return resp, nil
+}
+
+// UpdateNewsletterSource updates a NewsletterSource.
+func (s *Server) UpdateNewsletterSource(
+	ctx context.Context,
+	req *whatsnew.UpdateNewsletterSourceRequest,
+) (*whatsnew.UpdateNewsletterSourceResponse, error) {
+	var src models.NewsletterSource
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&src, "id = ?", req.GetId())
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		us := req.GetUpdatedNewsletterSource()
+		prevHost, prevUsername, prevFolder := src.Host, src.Username, src.Folder
+
+		err := setNewsletterSourceFields(&src, us)
+		if err != nil {
+			return err
+		}
+		// The UIDs of the messages read so far are only meaningful for the
+		// same mailbox folder.
+		if src.Host != prevHost || src.Username != prevUsername || src.Folder != prevFolder {
+			src.UIDValidity = 0
+			src.LastUID = 0
+		}
+		src.Enabled = us.GetEnabled()
+		src.FailuresCount = int(us.GetFailuresCount())
+		src.LastError = sql.NullString{
+			String: us.GetLastError(),
+			Valid:  len(us.GetLastError()) > 0,
+		}
+
+		src.LastRetrievedAt, err = nullTimeFromString(us.GetLastRetrievedAt())
+		if err != nil {
+			return err
+		}
+
+		ret = tx.Save(&src)
+		return ret.Error
+	})
+
+	if err != nil {
+		return &whatsnew.UpdateNewsletterSourceResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.UpdateNewsletterSourceResponse{
+		Data: &whatsnew.UpdateNewsletterSourceData{
+			NewsletterSource: makeAPINewsletterSource(src),
+		},
+	}
+	return resp, nil
+}
+
+// DeleteNewsletterSource deletes a NewsletterSource.
+func (s *Server) DeleteNewsletterSource(
+	ctx context.Context,
+	req *whatsnew.DeleteNewsletterSourceRequest,
+) (*whatsnew.DeleteNewsletterSourceResponse, error) {
+	var src models.NewsletterSource
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&src, "id = ?", req.GetId())
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		var messagesCount int64
+		ret = tx.Model(&models.NewsletterMessage{}).Where("newsletter_source_id = ?", src.ID).Limit(1).Count(&messagesCount)
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		if messagesCount == 0 {
+			ret = tx.Unscoped().Delete(&src)
+		} else {
+			ret = tx.Delete(&src)
+		}
+		return ret.Error
+	})
+
+	if err != nil {
+		return &whatsnew.DeleteNewsletterSourceResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.DeleteNewsletterSourceResponse{
+		Data: &whatsnew.DeleteNewsletterSourceData{
+			DeletedNewsletterSourceId: fmt.Sprintf("%d", src.ID),
+		},
+	}
+	return resp, nil
+}
+
+// newsletterSourceFields is implemented by both whatsnew.NewNewsletterSource
+// and whatsnew.UpdatedNewsletterSource.
+type newsletterSourceFields interface {
+	GetName() string
+	GetHost() string
+	GetPort() int64
+	GetDisableTls() bool
+	GetUsername() string
+	GetPassword() string
+	GetFolder() string
+	GetSenderFilter() string
+	GetMode() string
+	GetLinkPattern() string
+	GetLanguage() string
+}
+
+// setNewsletterSourceFields sets the user-defined fields of a
+// NewsletterSource, and validates the result. An empty password leaves the
+// current one unchanged, since passwords are never returned by the API.
+func setNewsletterSourceFields(src *models.NewsletterSource, f newsletterSourceFields) error {
+	src.Name = f.GetName()
+	src.Host = strings.TrimSpace(f.GetHost())
+	src.Port = int(f.GetPort())
+	if src.Port == 0 {
+		src.Port = 993
+	}
+	src.DisableTLS = f.GetDisableTls()
+	src.Username = f.GetUsername()
+	if len(f.GetPassword()) > 0 {
+		src.Password = f.GetPassword()
+	}
+	src.Folder = f.GetFolder()
+	if len(src.Folder) == 0 {
+		src.Folder = "INBOX"
+	}
+	src.SenderFilter = f.GetSenderFilter()
+	src.Mode = models.NewsletterSourceMode(f.GetMode())
+	if len(src.Mode) == 0 {
+		src.Mode = models.ArticleNewsletterMode
+	}
+	src.LinkPattern = f.GetLinkPattern()
+	src.Language = makeLanguage(f.GetLanguage())
+
+	switch {
+	case len(src.Name) == 0:
+		return fmt.Errorf("invalid empty NewsletterSource name")
+	case len(src.Host) == 0:
+		return fmt.Errorf("invalid empty NewsletterSource host")
+	case src.Port < 1 || src.Port > 65535:
+		return fmt.Errorf("invalid NewsletterSource port %d", src.Port)
+	case len(src.Username) == 0:
+		return fmt.Errorf("invalid empty NewsletterSource username")
+	case len(src.Password) == 0:
+		return fmt.Errorf("invalid empty NewsletterSource password")
+	case src.Mode != models.ArticleNewsletterMode && src.Mode != models.LinksNewsletterMode:
+		return fmt.Errorf("invalid NewsletterSource mode %#v", src.Mode)
+	}
+	if _, err := regexp.Compile(src.LinkPattern); err != nil {
+		return fmt.Errorf("invalid NewsletterSource link pattern: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/opml"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// ExportOPML exports the Feeds as an OPML document.
+func (s *Server) ExportOPML(
+	ctx context.Context,
+	req *whatsnew.ExportOPMLRequest,
+) (*whatsnew.ExportOPMLResponse, error) {
+	enabled, disabled := req.GetEnabled(), req.GetDisabled()
+	if !enabled && !disabled {
+		enabled, disabled = true, true
+	}
+
+	feeds, err := opml.Export(s.db.WithContext(ctx), enabled, disabled)
+	if err != nil {
+		return &whatsnew.ExportOPMLResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	var buf bytes.Buffer
+	err = opml.NewDocument("WhatsNew Feeds", feeds, time.Now()).Write(&buf)
+	if err != nil {
+		return &whatsnew.ExportOPMLResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.ExportOPMLResponse{
+		Data: &whatsnew.ExportOPMLData{
+			Opml: buf.String(),
+		},
+	}
+	return resp, nil
+}
+
+// ImportOPML imports the Feeds from an OPML document, creating the missing
+// ones.
+func (s *Server) ImportOPML(
+	ctx context.Context,
+	req *whatsnew.ImportOPMLRequest,
+) (*whatsnew.ImportOPMLResponse, error) {
+	doc, err := opml.Parse(strings.NewReader(req.GetOpmldocument().GetOpml()))
+	if err != nil {
+		return &whatsnew.ImportOPMLResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	var imported []opml.ImportedFeed
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		imported, err = opml.Import(tx, doc.Feeds())
+		return err
+	})
+	if err != nil {
+		return &whatsnew.ImportOPMLResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	var createdCount, duplicatesCount int64
+	importedFeeds := make([]*whatsnew.ImportedFeed, len(imported))
+	for i, f := range imported {
+		if f.Duplicate {
+			duplicatesCount++
+		} else {
+			createdCount++
+		}
+		importedFeeds[i] = makeAPIImportedFeed(f)
+	}
+
+	resp := &whatsnew.ImportOPMLResponse{
+		Data: &whatsnew.ImportOPMLData{
+			CreatedCount:    createdCount,
+			DuplicatesCount: duplicatesCount,
+			ImportedFeeds:   importedFeeds,
+		},
+	}
+	return resp, nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/scrapestats"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"time"
+)
+
+// GetScrapeFailures gets the number of unsuccessful scrapings, grouped by
+// domain, status, reason and HTTP status code.
+func (s *Server) GetScrapeFailures(
+	ctx context.Context,
+	req *whatsnew.GetScrapeFailuresRequest,
+) (*whatsnew.GetScrapeFailuresResponse, error) {
+	filter := scrapestats.Filter{
+		Status: models.ScrapeStatus(req.GetStatus()),
+		Domain: req.GetDomain(),
+		Limit:  int(req.GetFirst()),
+	}
+	if len(req.GetSince()) > 0 {
+		since, err := time.Parse(time.RFC3339, req.GetSince())
+		if err != nil {
+			err = fmt.Errorf("error parsing RFC3339 datetime %#v: %w", req.GetSince(), err)
+			return &whatsnew.GetScrapeFailuresResponse{Errors: s.makeErrors(req, err)}, nil
+		}
+		filter.Since = since
+	}
+
+	failures, err := scrapestats.Failures(s.db.WithContext(ctx), filter)
+	if err != nil {
+		return &whatsnew.GetScrapeFailuresResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	respFailures := make([]*whatsnew.ScrapeFailure, len(failures))
+	for i, f := range failures {
+		respFailures[i] = makeAPIScrapeFailure(f)
+	}
+
+	resp := &whatsnew.GetScrapeFailuresResponse{
+		Data: &whatsnew.GetScrapeFailuresData{
+			ScrapeFailures: respFailures,
+		},
+	}
+	return resp, nil
+}
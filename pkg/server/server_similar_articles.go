@@ -0,0 +1,173 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	hnswpb "github.com/SpecializedGeneralist/hnsw-grpc-server/pkg/grpcapi"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/bertencoder"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/grpcconn"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/hnswclient"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"math"
+	"strings"
+	"time"
+)
+
+// defaultSimilarArticlesTimeframe is the default time range of a similarity
+// search, when its lower bound is not specified.
+const defaultSimilarArticlesTimeframe = 7 * 24 * time.Hour
+
+// SearchSimilarArticles searches the WebArticles which are semantically
+// similar to a given text or to an existing WebArticle.
+func (s *Server) SearchSimilarArticles(
+	ctx context.Context,
+	req *whatsnew.SearchSimilarArticlesRequest,
+) (*whatsnew.SearchSimilarArticlesResponse, error) {
+	similarArticles, err := s.searchSimilarArticles(ctx, req.GetSimilarArticlesQuery())
+	if err != nil {
+		return &whatsnew.SearchSimilarArticlesResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.SearchSimilarArticlesResponse{
+		Data: &whatsnew.SearchSimilarArticlesData{
+			SimilarArticles: similarArticles,
+		},
+	}
+	return resp, nil
+}
+
+func (s *Server) searchSimilarArticles(
+	ctx context.Context,
+	q *whatsnew.SimilarArticlesQuery,
+) ([]*whatsnew.SimilarArticle, error) {
+	searchParams, err := makeSimilarArticlesSearchParams(q)
+	if err != nil {
+		return nil, err
+	}
+
+	var excludedID uint
+	switch {
+	case len(q.GetWebArticleId()) > 0:
+		var vec models.Vector
+		ret := s.db.WithContext(ctx).First(&vec, "web_article_id = ?", q.GetWebArticleId())
+		if ret.Error != nil {
+			return nil, fmt.Errorf("error fetching the Vector of WebArticle %s: %w", q.GetWebArticleId(), ret.Error)
+		}
+		searchParams.Vector, err = vec.DataAsFloat32Slice()
+		if err != nil {
+			return nil, err
+		}
+		excludedID = vec.WebArticleID
+	case len(strings.TrimSpace(q.GetText())) > 0:
+		encoder := bertencoder.New(s.conf.SpagoBERTServer, s.log)
+		searchParams.Vector, err = encoder.Encode(ctx, strings.TrimSpace(q.GetText()))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("either text or web_article_id must be given")
+	}
+
+	hits, err := s.searchKNN(ctx, searchParams)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		if hit.ID == excludedID {
+			continue
+		}
+		ids = append(ids, hit.ID)
+		if q.GetLimit() > 0 && len(ids) == int(q.GetLimit()) {
+			break
+		}
+	}
+	if len(ids) == 0 {
+		return []*whatsnew.SimilarArticle{}, nil
+	}
+
+	var webArticles []models.WebArticle
+	ret := preloadWebArticleAssociations(s.db.WithContext(ctx)).Find(&webArticles, ids)
+	if ret.Error != nil {
+		return nil, ret.Error
+	}
+	urls, err := s.findWebArticlesURLs(ctx, webArticles)
+	if err != nil {
+		return nil, err
+	}
+
+	webArticlesByID := make(map[uint]models.WebArticle, len(webArticles))
+	for _, wa := range webArticles {
+		webArticlesByID[wa.ID] = wa
+	}
+
+	// The hits are already sorted by distance.
+	result := make([]*whatsnew.SimilarArticle, 0, len(ids))
+	for _, hit := range hits {
+		wa, ok := webArticlesByID[hit.ID]
+		if !ok {
+			continue // excluded, beyond limit, or no longer existing
+		}
+		result = append(result, &whatsnew.SimilarArticle{
+			Distance:   hit.Distance,
+			WebArticle: makeAPIWebArticle(wa, urls[wa.WebResourceID]),
+		})
+	}
+	return result, nil
+}
+
+func (s *Server) searchKNN(ctx context.Context, params hnswclient.SearchParams) (hnswclient.Hits, error) {
+	hnswConn, err := grpcconn.Dial(ctx, s.hnswConf.Server)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := hnswConn.Close(); err != nil {
+			s.log.Err(err).Msg("error closing HNSW connection")
+		}
+	}()
+	hnswClient := hnswclient.New(hnswpb.NewServerClient(hnswConn), s.hnswConf.Index)
+
+	return hnswClient.SearchKNN(ctx, params)
+}
+
+func makeSimilarArticlesSearchParams(q *whatsnew.SimilarArticlesQuery) (hnswclient.SearchParams, error) {
+	to := time.Now().UTC()
+	if len(q.GetTo()) > 0 {
+		t, err := time.Parse(time.RFC3339, q.GetTo())
+		if err != nil {
+			return hnswclient.SearchParams{}, fmt.Errorf("error parsing RFC3339 datetime %#v: %w", q.GetTo(), err)
+		}
+		to = t.UTC()
+	}
+
+	from := to.Add(-defaultSimilarArticlesTimeframe)
+	if len(q.GetFrom()) > 0 {
+		t, err := time.Parse(time.RFC3339, q.GetFrom())
+		if err != nil {
+			return hnswclient.SearchParams{}, fmt.Errorf("error parsing RFC3339 datetime %#v: %w", q.GetFrom(), err)
+		}
+		from = t.UTC()
+	}
+
+	if from.After(to) {
+		return hnswclient.SearchParams{}, fmt.Errorf("invalid time range: from %s is after to %s", from, to)
+	}
+
+	threshold := q.GetDistanceThreshold()
+	if threshold <= 0 {
+		threshold = math.MaxFloat32
+	}
+
+	return hnswclient.SearchParams{
+		From:              from,
+		To:                to,
+		DistanceThreshold: threshold,
+	}, nil
+}
@@ -0,0 +1,198 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetSitemaps gets all Sitemaps.
+func (s *Server) GetSitemaps(_ context.Context, req *whatsnew.GetSitemapsRequest) (*whatsnew.GetSitemapsResponse, error) {
+	query := s.db.Order("id")
+	if len(req.GetAfter()) > 0 {
+		query = query.Where("id > ?", req.GetAfter())
+	}
+	if req.GetFirst() > 0 {
+		query = query.Limit(int(req.GetFirst()))
+	}
+
+	var sitemaps []models.Sitemap
+	ret := query.Find(&sitemaps)
+	if ret.Error != nil {
+		return &whatsnew.GetSitemapsResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	respSitemaps := make([]*whatsnew.Sitemap, len(sitemaps))
+	for i, sm := range sitemaps {
+		respSitemaps[i] = makeAPISitemap(sm)
+	}
+
+	resp := &whatsnew.GetSitemapsResponse{
+		Data: &whatsnew.GetSitemapsData{
+			Sitemaps: respSitemaps,
+		},
+	}
+	return resp, nil
+}
+
+// CreateSitemaps creates new Sitemaps.
+func (s *Server) CreateSitemaps(
+	_ context.Context,
+	req *whatsnew.CreateSitemapsRequest,
+) (*whatsnew.CreateSitemapsResponse, error) {
+	reqSitemaps := req.GetNewSitemaps().GetSitemaps()
+	sitemaps := make([]models.Sitemap, len(reqSitemaps))
+	for i, reqSitemap := range reqSitemaps {
+		sitemaps[i] = makeNewSitemap(reqSitemap)
+	}
+
+	ret := s.db.Create(&sitemaps)
+	if ret.Error != nil {
+		return &whatsnew.CreateSitemapsResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	ids := make([]string, len(sitemaps))
+	for i, sm := range sitemaps {
+		ids[i] = fmt.Sprintf("%d", sm.ID)
+	}
+
+	resp := &whatsnew.CreateSitemapsResponse{
+		Data: &whatsnew.CreateSitemapsData{
+			SitemapIds: ids,
+		},
+	}
+	return resp, nil
+}
+
+// CreateSitemap creates a new Sitemap.
+func (s *Server) CreateSitemap(
+	_ context.Context,
+	req *whatsnew.CreateSitemapRequest,
+) (*whatsnew.CreateSitemapResponse, error) {
+	sm := makeNewSitemap(req.GetNewSitemap())
+	ret := s.db.Create(&sm)
+	if ret.Error != nil {
+		return &whatsnew.CreateSitemapResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+	resp := &whatsnew.CreateSitemapResponse{
+		Data: &whatsnew.CreateSitemapData{
+			SitemapId: fmt.Sprintf("%d", sm.ID),
+		},
+	}
+	return resp, nil
+}
+
+// GetSitemap gets a Sitemap.
+func (s *Server) GetSitemap(_ context.Context, req *whatsnew.GetSitemapRequest) (*whatsnew.GetSitemapResponse, error) {
+	var sm models.Sitemap
+	ret := s.db.First(&sm, "id = ?", req.GetId())
+	if ret.Error != nil {
+		return &whatsnew.GetSitemapResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+	resp := &whatsnew.GetSitemapResponse{
+		Data: &whatsnew.GetSitemapData{
+			Sitemap: makeAPISitemap(sm),
+		},
+	}
+	return resp, nil
+}
+
+// UpdateSitemap updates a Sitemap.
+func (s *Server) UpdateSitemap(
+	ctx context.Context,
+	req *whatsnew.UpdateSitemapRequest,
+) (*whatsnew.UpdateSitemapResponse, error) {
+	var sm models.Sitemap
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sm, "id = ?", req.GetId())
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		us := req.GetUpdatedSitemap()
+
+		sm.URL = us.GetUrl()
+		sm.Enabled = us.GetEnabled()
+		sm.FailuresCount = int(us.GetFailuresCount())
+		sm.LastError = sql.NullString{
+			String: us.GetLastError(),
+			Valid:  len(us.GetLastError()) > 0,
+		}
+		sm.Language = makeLanguage(us.GetLanguage())
+
+		var err error
+		sm.LastRetrievedAt, err = nullTimeFromString(us.GetLastRetrievedAt())
+		if err != nil {
+			return err
+		}
+
+		ret = tx.Save(&sm)
+		return ret.Error
+	})
+
+	if err != nil {
+		return &whatsnew.UpdateSitemapResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.UpdateSitemapResponse{
+		Data: &whatsnew.UpdateSitemapData{
+			Sitemap: makeAPISitemap(sm),
+		},
+	}
+	return resp, nil
+}
+
+// DeleteSitemap deletes a Sitemap.
+func (s *Server) DeleteSitemap(
+	ctx context.Context,
+	req *whatsnew.DeleteSitemapRequest,
+) (*whatsnew.DeleteSitemapResponse, error) {
+	var sm models.Sitemap
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sm, "id = ?", req.GetId())
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		var itemsCount int64
+		ret = tx.Model(&models.SitemapItem{}).Where("sitemap_id = ?", sm.ID).Limit(1).Count(&itemsCount)
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		if itemsCount == 0 {
+			ret = tx.Unscoped().Delete(&sm)
+		} else {
+			ret = tx.Delete(&sm)
+		}
+		return ret.Error
+	})
+
+	if err != nil {
+		return &whatsnew.DeleteSitemapResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.DeleteSitemapResponse{
+		Data: &whatsnew.DeleteSitemapData{
+			DeletedSitemapId: fmt.Sprintf("%d", sm.ID),
+		},
+	}
+	return resp, nil
+}
+
+func makeNewSitemap(ns *whatsnew.NewSitemap) models.Sitemap {
+	return models.Sitemap{
+		URL:      ns.GetUrl(),
+		Language: makeLanguage(ns.GetLanguage()),
+	}
+}
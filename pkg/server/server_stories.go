@@ -0,0 +1,209 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"time"
+)
+
+// defaultTrendingStoriesTimeframe is the default time window for the
+// trending Stories, when its lower bound is not specified.
+const defaultTrendingStoriesTimeframe = 24 * time.Hour
+
+// trendingStoryRow is the result of the trending Stories query.
+type trendingStoryRow struct {
+	StoryID            uint
+	WindowMembersCount int64
+}
+
+// GetTrendingStories gets the Stories with the largest number of member
+// WebArticles published within a time window.
+func (s *Server) GetTrendingStories(
+	ctx context.Context,
+	req *whatsnew.GetTrendingStoriesRequest,
+) (*whatsnew.GetTrendingStoriesResponse, error) {
+	trendingStories, err := s.getTrendingStories(ctx, req)
+	if err != nil {
+		return &whatsnew.GetTrendingStoriesResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.GetTrendingStoriesResponse{
+		Data: &whatsnew.GetTrendingStoriesData{
+			TrendingStories: trendingStories,
+		},
+	}
+	return resp, nil
+}
+
+func (s *Server) getTrendingStories(
+	ctx context.Context,
+	req *whatsnew.GetTrendingStoriesRequest,
+) ([]*whatsnew.TrendingStory, error) {
+	from, to, err := parseTrendingStoriesTimeWindow(req.GetFrom(), req.GetTo())
+	if err != nil {
+		return nil, err
+	}
+
+	query := s.db.WithContext(ctx).
+		Model(&models.Story{}).
+		Select("stories.id AS story_id, COUNT(web_articles.id) AS window_members_count").
+		Joins("JOIN similarity_infos ON similarity_infos.story_id = stories.id").
+		Joins("JOIN web_articles ON web_articles.id = similarity_infos.web_article_id").
+		Where("web_articles.publish_date >= ? AND web_articles.publish_date < ?", from, to).
+		Group("stories.id").
+		Order("window_members_count DESC, stories.id")
+	if req.GetMinMembers() > 0 {
+		query = query.Having("COUNT(web_articles.id) >= ?", req.GetMinMembers())
+	}
+	if req.GetFirst() > 0 {
+		query = query.Limit(int(req.GetFirst()))
+	}
+
+	var rows []trendingStoryRow
+	ret := query.Scan(&rows)
+	if ret.Error != nil {
+		return nil, fmt.Errorf("error fetching trending Stories: %w", ret.Error)
+	}
+	if len(rows) == 0 {
+		return []*whatsnew.TrendingStory{}, nil
+	}
+
+	ids := make([]uint, len(rows))
+	for i, row := range rows {
+		ids[i] = row.StoryID
+	}
+	stories, err := s.findStoriesWithRepresentatives(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*whatsnew.TrendingStory, 0, len(rows))
+	for _, row := range rows {
+		story, ok := stories[row.StoryID]
+		if !ok {
+			continue // deleted in the meantime
+		}
+		result = append(result, &whatsnew.TrendingStory{
+			WindowMembersCount: row.WindowMembersCount,
+			Story:              story,
+		})
+	}
+	return result, nil
+}
+
+// GetStory gets a Story, including all its member WebArticles.
+func (s *Server) GetStory(
+	ctx context.Context,
+	req *whatsnew.GetStoryRequest,
+) (*whatsnew.GetStoryResponse, error) {
+	var story models.Story
+	ret := s.db.WithContext(ctx).First(&story, "id = ?", req.GetId())
+	if ret.Error != nil {
+		return &whatsnew.GetStoryResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	var webArticles []models.WebArticle
+	ret = preloadWebArticleAssociations(s.db.WithContext(ctx)).
+		Where("id IN (?)", s.db.Model(&models.SimilarityInfo{}).Select("web_article_id").Where("story_id = ?", story.ID)).
+		Order("publish_date, id").
+		Find(&webArticles)
+	if ret.Error != nil {
+		return &whatsnew.GetStoryResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	urls, err := s.findWebArticlesURLs(ctx, webArticles)
+	if err != nil {
+		return &whatsnew.GetStoryResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	var representative *whatsnew.WebArticle
+	respWebArticles := make([]*whatsnew.WebArticle, len(webArticles))
+	for i, wa := range webArticles {
+		respWebArticles[i] = makeAPIWebArticle(wa, urls[wa.WebResourceID])
+		if wa.ID == story.RepresentativeID {
+			representative = respWebArticles[i]
+		}
+	}
+
+	respStory, err := makeAPIStory(story, representative)
+	if err != nil {
+		return &whatsnew.GetStoryResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.GetStoryResponse{
+		Data: &whatsnew.GetStoryData{
+			Story:       respStory,
+			WebArticles: respWebArticles,
+		},
+	}
+	return resp, nil
+}
+
+// findStoriesWithRepresentatives returns the API representation of the
+// Stories with the given IDs, including their representative WebArticles,
+// mapped by Story ID.
+func (s *Server) findStoriesWithRepresentatives(ctx context.Context, ids []uint) (map[uint]*whatsnew.Story, error) {
+	var stories []models.Story
+	ret := s.db.WithContext(ctx).Find(&stories, ids)
+	if ret.Error != nil {
+		return nil, fmt.Errorf("error fetching Stories: %w", ret.Error)
+	}
+
+	representativeIDs := make([]uint, len(stories))
+	for i, st := range stories {
+		representativeIDs[i] = st.RepresentativeID
+	}
+
+	var webArticles []models.WebArticle
+	ret = preloadWebArticleAssociations(s.db.WithContext(ctx)).Find(&webArticles, representativeIDs)
+	if ret.Error != nil {
+		return nil, fmt.Errorf("error fetching WebArticles: %w", ret.Error)
+	}
+	urls, err := s.findWebArticlesURLs(ctx, webArticles)
+	if err != nil {
+		return nil, err
+	}
+
+	webArticlesByID := make(map[uint]*whatsnew.WebArticle, len(webArticles))
+	for _, wa := range webArticles {
+		webArticlesByID[wa.ID] = makeAPIWebArticle(wa, urls[wa.WebResourceID])
+	}
+
+	result := make(map[uint]*whatsnew.Story, len(stories))
+	for _, st := range stories {
+		result[st.ID], err = makeAPIStory(st, webArticlesByID[st.RepresentativeID])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func parseTrendingStoriesTimeWindow(rawFrom, rawTo string) (from, to time.Time, err error) {
+	to = time.Now().UTC()
+	if len(rawTo) > 0 {
+		to, err = time.Parse(time.RFC3339, rawTo)
+		if err != nil {
+			return from, to, fmt.Errorf("error parsing RFC3339 datetime %#v: %w", rawTo, err)
+		}
+	}
+
+	from = to.Add(-defaultTrendingStoriesTimeframe)
+	if len(rawFrom) > 0 {
+		from, err = time.Parse(time.RFC3339, rawFrom)
+		if err != nil {
+			return from, to, fmt.Errorf("error parsing RFC3339 datetime %#v: %w", rawFrom, err)
+		}
+	}
+
+	if from.After(to) {
+		return from, to, fmt.Errorf("invalid time range: from %s is after to %s", from, to)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,343 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
+	"gorm.io/gorm"
+	"time"
+)
+
+// streamReplayBatchSize is the maximum number of WebArticles fetched at once
+// when a streaming subscription is resumed.
+const streamReplayBatchSize = 100
+
+// webArticlesFilter contains the optional criteria for selecting
+// WebArticles.
+type webArticlesFilter struct {
+	PublishDateFrom string
+	PublishDateTo   string
+	Language        string
+	Country         string
+	ZeroShotLabel   string
+	TextClass       string
+	Duplicate       string
+}
+
+// GetWebArticles gets WebArticles, optionally filtered.
+func (s *Server) GetWebArticles(
+	ctx context.Context,
+	req *whatsnew.GetWebArticlesRequest,
+) (*whatsnew.GetWebArticlesResponse, error) {
+	query := s.db.WithContext(ctx).Order("id")
+	if len(req.GetAfter()) > 0 {
+		query = query.Where("id > ?", req.GetAfter())
+	}
+	if req.GetFirst() > 0 {
+		query = query.Limit(int(req.GetFirst()))
+	}
+
+	query, err := applyWebArticlesFilter(query, webArticlesFilter{
+		PublishDateFrom: req.GetPublishDateFrom(),
+		PublishDateTo:   req.GetPublishDateTo(),
+		Language:        req.GetLanguage(),
+		Country:         req.GetCountry(),
+		ZeroShotLabel:   req.GetZeroShotLabel(),
+		TextClass:       req.GetTextClass(),
+		Duplicate:       req.GetDuplicate(),
+	})
+	if err != nil {
+		return &whatsnew.GetWebArticlesResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	var webArticles []models.WebArticle
+	ret := preloadWebArticleAssociations(query).Find(&webArticles)
+	if ret.Error != nil {
+		return &whatsnew.GetWebArticlesResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	urls, err := s.findWebArticlesURLs(ctx, webArticles)
+	if err != nil {
+		return &whatsnew.GetWebArticlesResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	respWebArticles := make([]*whatsnew.WebArticle, len(webArticles))
+	for i, wa := range webArticles {
+		respWebArticles[i] = makeAPIWebArticle(wa, urls[wa.WebResourceID])
+	}
+
+	resp := &whatsnew.GetWebArticlesResponse{
+		Data: &whatsnew.GetWebArticlesData{
+			WebArticles: respWebArticles,
+		},
+	}
+	return resp, nil
+}
+
+// GetWebArticle gets a WebArticle.
+func (s *Server) GetWebArticle(
+	ctx context.Context,
+	req *whatsnew.GetWebArticleRequest,
+) (*whatsnew.GetWebArticleResponse, error) {
+	var wa models.WebArticle
+	ret := preloadWebArticleAssociations(s.db.WithContext(ctx)).First(&wa, "id = ?", req.GetId())
+	if ret.Error != nil {
+		return &whatsnew.GetWebArticleResponse{Errors: s.makeErrors(req, ret.Error)}, nil
+	}
+
+	urls, err := s.findWebArticlesURLs(ctx, []models.WebArticle{wa})
+	if err != nil {
+		return &whatsnew.GetWebArticleResponse{Errors: s.makeErrors(req, err)}, nil
+	}
+
+	resp := &whatsnew.GetWebArticleResponse{
+		Data: &whatsnew.GetWebArticleData{
+			WebArticle: makeAPIWebArticle(wa, urls[wa.WebResourceID]),
+		},
+	}
+	return resp, nil
+}
+
+// StreamWebArticles streams the WebArticles processed by a pipeline stage.
+func (s *Server) StreamWebArticles(
+	req *whatsnew.StreamWebArticlesRequest,
+	stream whatsnew.Whatsnew_StreamWebArticlesServer,
+) error {
+	err := s.streamWebArticles(req, stream)
+	if err != nil {
+		return stream.Send(&whatsnew.StreamWebArticlesResponse{Errors: s.makeErrors(req, err)})
+	}
+	return nil
+}
+
+func (s *Server) streamWebArticles(
+	req *whatsnew.StreamWebArticlesRequest,
+	stream whatsnew.Whatsnew_StreamWebArticlesServer,
+) error {
+	ctx := stream.Context()
+
+	stage := req.GetStage()
+	if !stagenotifier.IsValidStage(stage) {
+		return fmt.Errorf("invalid stage %#v", stage)
+	}
+
+	filter := webArticlesFilter{
+		PublishDateFrom: req.GetPublishDateFrom(),
+		PublishDateTo:   req.GetPublishDateTo(),
+		Language:        req.GetLanguage(),
+		Country:         req.GetCountry(),
+		ZeroShotLabel:   req.GetZeroShotLabel(),
+		TextClass:       req.GetTextClass(),
+		Duplicate:       req.GetDuplicate(),
+	}
+	if _, err := applyWebArticlesFilter(s.db, filter); err != nil {
+		return err
+	}
+
+	// Subscribing before replaying the existing WebArticles ensures that no
+	// notification is lost in between. As a consequence, a WebArticle might
+	// be sent twice.
+	sub := s.listener.Subscribe()
+	defer sub.Close()
+
+	if len(req.GetAfter()) > 0 {
+		err := s.replayWebArticles(ctx, stream, stage, req.GetAfter(), filter)
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n, ok := <-sub.C:
+			if !ok {
+				return stagenotifier.ErrSubscriptionClosed
+			}
+			if n.Stage != stage {
+				continue
+			}
+			err := s.sendStreamedWebArticle(ctx, stream, n.WebArticleID, filter)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// replayWebArticles sends all the WebArticles with ID greater than "after",
+// which already passed the given stage and satisfy the filter.
+func (s *Server) replayWebArticles(
+	ctx context.Context,
+	stream whatsnew.Whatsnew_StreamWebArticlesServer,
+	stage string,
+	after string,
+	filter webArticlesFilter,
+) error {
+	for {
+		query := s.db.WithContext(ctx).Order("id").Where("id > ?", after).Limit(streamReplayBatchSize).
+			Where(
+				"EXISTS (SELECT 1 FROM completed_stages WHERE completed_stages.web_article_id = web_articles.id AND completed_stages.stage = ?)",
+				stage,
+			)
+		query, err := applyWebArticlesFilter(query, filter)
+		if err != nil {
+			return err
+		}
+
+		var webArticles []models.WebArticle
+		ret := preloadWebArticleAssociations(query).Find(&webArticles)
+		if ret.Error != nil {
+			return ret.Error
+		}
+
+		err = s.sendWebArticles(ctx, stream, webArticles)
+		if err != nil {
+			return err
+		}
+
+		if len(webArticles) < streamReplayBatchSize {
+			return nil
+		}
+		after = fmt.Sprintf("%d", webArticles[len(webArticles)-1].ID)
+	}
+}
+
+// sendStreamedWebArticle sends the WebArticle with the given ID, only if it
+// satisfies the filter.
+func (s *Server) sendStreamedWebArticle(
+	ctx context.Context,
+	stream whatsnew.Whatsnew_StreamWebArticlesServer,
+	id uint,
+	filter webArticlesFilter,
+) error {
+	query, err := applyWebArticlesFilter(s.db.WithContext(ctx).Where("web_articles.id = ?", id), filter)
+	if err != nil {
+		return err
+	}
+
+	var webArticles []models.WebArticle
+	ret := preloadWebArticleAssociations(query).Limit(1).Find(&webArticles)
+	if ret.Error != nil {
+		return ret.Error
+	}
+	return s.sendWebArticles(ctx, stream, webArticles)
+}
+
+func (s *Server) sendWebArticles(
+	ctx context.Context,
+	stream whatsnew.Whatsnew_StreamWebArticlesServer,
+	webArticles []models.WebArticle,
+) error {
+	urls, err := s.findWebArticlesURLs(ctx, webArticles)
+	if err != nil {
+		return err
+	}
+
+	for _, wa := range webArticles {
+		err = stream.Send(&whatsnew.StreamWebArticlesResponse{
+			Data: &whatsnew.StreamWebArticlesData{
+				WebArticle: makeAPIWebArticle(wa, urls[wa.WebResourceID]),
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyWebArticlesFilter(query *gorm.DB, f webArticlesFilter) (*gorm.DB, error) {
+	if len(f.PublishDateFrom) > 0 {
+		t, err := time.Parse(time.RFC3339, f.PublishDateFrom)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing RFC3339 datetime %#v: %w", f.PublishDateFrom, err)
+		}
+		query = query.Where("web_articles.publish_date >= ?", t.UTC())
+	}
+	if len(f.PublishDateTo) > 0 {
+		t, err := time.Parse(time.RFC3339, f.PublishDateTo)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing RFC3339 datetime %#v: %w", f.PublishDateTo, err)
+		}
+		query = query.Where("web_articles.publish_date < ?", t.UTC())
+	}
+	if len(f.Language) > 0 {
+		query = query.Where("web_articles.language = ?", f.Language)
+	}
+	if len(f.Country) > 0 {
+		query = query.Where("web_articles.country_code = ?", f.Country)
+	}
+	if len(f.ZeroShotLabel) > 0 {
+		query = query.Where(
+			`EXISTS (SELECT 1 FROM zero_shot_classes
+				JOIN zero_shot_hypothesis_labels ON zero_shot_hypothesis_labels.id = zero_shot_classes.zero_shot_hypothesis_label_id
+				WHERE zero_shot_classes.web_article_id = web_articles.id
+				AND zero_shot_classes.best AND zero_shot_hypothesis_labels.text = ?)`,
+			f.ZeroShotLabel,
+		)
+	}
+	if len(f.TextClass) > 0 {
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM text_classes WHERE text_classes.web_article_id = web_articles.id AND text_classes.label = ?)",
+			f.TextClass,
+		)
+	}
+	switch f.Duplicate {
+	case "":
+	case "true":
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM similarity_infos WHERE similarity_infos.web_article_id = web_articles.id AND similarity_infos.parent_id IS NOT NULL)",
+		)
+	case "false":
+		query = query.Where(
+			"EXISTS (SELECT 1 FROM similarity_infos WHERE similarity_infos.web_article_id = web_articles.id AND similarity_infos.parent_id IS NULL)",
+		)
+	default:
+		return nil, fmt.Errorf("invalid duplicate filter value %#v: expected \"true\", \"false\" or empty", f.Duplicate)
+	}
+	return query, nil
+}
+
+func preloadWebArticleAssociations(query *gorm.DB) *gorm.DB {
+	unscoped := func(db *gorm.DB) *gorm.DB { return db.Unscoped() }
+	return query.
+		Preload("ZeroShotClasses", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("ZeroShotClasses.ZeroShotHypothesisLabel", unscoped).
+		Preload("TextClasses", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("ExtractedInfos", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("ExtractedInfos.InfoExtractionRule", unscoped).
+		Preload("SimilarityInfo")
+}
+
+// findWebArticlesURLs returns the URLs of the WebResources related to the
+// given WebArticles, mapped by WebResource ID.
+func (s *Server) findWebArticlesURLs(ctx context.Context, webArticles []models.WebArticle) (map[uint]string, error) {
+	urls := make(map[uint]string, len(webArticles))
+	if len(webArticles) == 0 {
+		return urls, nil
+	}
+
+	ids := make([]uint, len(webArticles))
+	for i, wa := range webArticles {
+		ids[i] = wa.WebResourceID
+	}
+
+	var webResources []models.WebResource
+	ret := s.db.WithContext(ctx).Select("id", "url").Find(&webResources, ids)
+	if ret.Error != nil {
+		return nil, fmt.Errorf("error fetching WebResources: %w", ret.Error)
+	}
+
+	for _, wr := range webResources {
+		urls[wr.ID] = wr.URL
+	}
+	return urls, nil
+}
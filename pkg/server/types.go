@@ -7,21 +7,230 @@ package server
 import (
 	"database/sql"
 	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/feeddiscovery"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/opml"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/scrapestats"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew"
+	"sort"
+	"strconv"
 	"time"
 )
 
-func makeAPIFeed(feed models.Feed) *whatsnew.Feed {
+func makeAPIFeed(feed models.Feed) (*whatsnew.Feed, error) {
+	tags, err := feed.TagsAsStringSlice()
+	if err != nil {
+		return nil, err
+	}
 	return &whatsnew.Feed{
-		Id:              fmt.Sprintf("%d", feed.ID),
-		CreatedAt:       feed.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:       feed.UpdatedAt.Format(time.RFC3339),
-		Url:             feed.URL,
-		Enabled:         feed.Enabled,
-		LastRetrievedAt: nullTimeToString(feed.LastRetrievedAt),
-		FailuresCount:   int64(feed.FailuresCount),
-		LastError:       feed.LastError.String,
+		Id:                   fmt.Sprintf("%d", feed.ID),
+		CreatedAt:            feed.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:            feed.UpdatedAt.Format(time.RFC3339),
+		Url:                  feed.URL,
+		Enabled:              feed.Enabled,
+		LastRetrievedAt:      nullTimeToString(feed.LastRetrievedAt),
+		FailuresCount:        int64(feed.FailuresCount),
+		LastError:            feed.LastError.String,
+		Title:                feed.Title.String,
+		SiteUrl:              feed.SiteURL.String,
+		Tags:                 tags,
+		Language:             feed.Language.String,
+		FetchIntervalSeconds: feed.FetchIntervalSeconds.Int64,
+		LastScheduledAt:      nullTimeToString(feed.LastScheduledAt),
+	}, nil
+}
+
+func makeAPIDiscoveredFeed(sf feeddiscovery.StoredFeed) *whatsnew.DiscoveredFeed {
+	df := &whatsnew.DiscoveredFeed{
+		Url:      sf.URL,
+		Title:    sf.Title,
+		FeedType: sf.Type,
+		Created:  sf.Created,
+	}
+	if sf.FeedID != 0 {
+		df.FeedId = fmt.Sprintf("%d", sf.FeedID)
+	}
+	return df
+}
+
+func makeAPIImportedFeed(f opml.ImportedFeed) *whatsnew.ImportedFeed {
+	imf := &whatsnew.ImportedFeed{
+		Url:       f.URL,
+		Title:     f.Title,
+		Tags:      f.Tags,
+		Duplicate: f.Duplicate,
+	}
+	if f.FeedID != 0 {
+		imf.FeedId = fmt.Sprintf("%d", f.FeedID)
+	}
+	return imf
+}
+
+func makeAPISitemap(sm models.Sitemap) *whatsnew.Sitemap {
+	return &whatsnew.Sitemap{
+		Id:              fmt.Sprintf("%d", sm.ID),
+		CreatedAt:       sm.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       sm.UpdatedAt.Format(time.RFC3339),
+		Url:             sm.URL,
+		Enabled:         sm.Enabled,
+		LastRetrievedAt: nullTimeToString(sm.LastRetrievedAt),
+		FailuresCount:   int64(sm.FailuresCount),
+		LastError:       sm.LastError.String,
+		Language:        sm.Language.String,
+	}
+}
+
+func makeAPIAPISource(src models.APISource) (*whatsnew.ApiSource, error) {
+	headers, err := src.HeadersAsMap()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	// Header values often carry credentials, so only their names are returned.
+	apiHeaders := make([]*whatsnew.ApiSourceHeader, len(names))
+	for i, name := range names {
+		apiHeaders[i] = &whatsnew.ApiSourceHeader{Name: name}
+	}
+
+	return &whatsnew.ApiSource{
+		Id:                fmt.Sprintf("%d", src.ID),
+		CreatedAt:         src.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         src.UpdatedAt.Format(time.RFC3339),
+		Name:              src.Name,
+		Url:               src.URL,
+		Method:            src.Method,
+		Headers:           apiHeaders,
+		Body:              src.Body.String,
+		ItemsPath:         src.ItemsPath,
+		UrlPath:           src.URLPath,
+		TitlePath:         src.TitlePath,
+		DescriptionPath:   src.DescriptionPath,
+		PublishedAtPath:   src.PublishedAtPath,
+		PublishedAtLayout: src.PublishedAtLayout,
+		Pagination:        string(src.Pagination),
+		PaginationParam:   src.PaginationParam,
+		PaginationStart:   int64(src.PaginationStart),
+		NextCursorPath:    src.NextCursorPath,
+		MaxPages:          int64(src.MaxPages),
+		Language:          src.Language.String,
+		Enabled:           src.Enabled,
+		LastRetrievedAt:   nullTimeToString(src.LastRetrievedAt),
+		FailuresCount:     int64(src.FailuresCount),
+		LastError:         src.LastError.String,
+	}, nil
+}
+
+func makeAPINewsletterSource(src models.NewsletterSource) *whatsnew.NewsletterSource {
+	return &whatsnew.NewsletterSource{
+		Id:              fmt.Sprintf("%d", src.ID),
+		CreatedAt:       src.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       src.UpdatedAt.Format(time.RFC3339),
+		Name:            src.Name,
+		Host:            src.Host,
+		Port:            int64(src.Port),
+		DisableTls:      src.DisableTLS,
+		Username:        src.Username,
+		Folder:          src.Folder,
+		SenderFilter:    src.SenderFilter,
+		Mode:            string(src.Mode),
+		LinkPattern:     src.LinkPattern,
+		Language:        src.Language.String,
+		UidValidity:     src.UIDValidity,
+		LastUid:         src.LastUID,
+		Enabled:         src.Enabled,
+		LastRetrievedAt: nullTimeToString(src.LastRetrievedAt),
+		FailuresCount:   int64(src.FailuresCount),
+		LastError:       src.LastError.String,
+	}
+}
+
+func makeAPIMastodonSource(src models.MastodonSource) *whatsnew.MastodonSource {
+	return &whatsnew.MastodonSource{
+		Id:              fmt.Sprintf("%d", src.ID),
+		CreatedAt:       src.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       src.UpdatedAt.Format(time.RFC3339),
+		InstanceUrl:     src.InstanceURL,
+		Type:            string(src.Type),
+		Text:            src.Text,
+		Enabled:         src.Enabled,
+		LastRetrievedAt: nullTimeToString(src.LastRetrievedAt),
+		FailuresCount:   int64(src.FailuresCount),
+		LastError:       src.LastError.String,
+	}
+}
+
+func makeAPIDomainPolicy(p models.DomainPolicy) (*whatsnew.DomainPolicy, error) {
+	headers, err := p.HeadersAsMap()
+	if err != nil {
+		return nil, err
+	}
+	headerNames := make([]string, 0, len(headers))
+	for name := range headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	// Header and cookie values often carry credentials or session tokens, so
+	// only their names are returned.
+	apiHeaders := make([]*whatsnew.DomainPolicyHeader, len(headerNames))
+	for i, name := range headerNames {
+		apiHeaders[i] = &whatsnew.DomainPolicyHeader{Name: name}
+	}
+
+	cookies, err := p.CookiesAsMap()
+	if err != nil {
+		return nil, err
+	}
+	cookieNames := make([]string, 0, len(cookies))
+	for name := range cookies {
+		cookieNames = append(cookieNames, name)
+	}
+	sort.Strings(cookieNames)
+	apiCookies := make([]*whatsnew.DomainPolicyCookie, len(cookieNames))
+	for i, name := range cookieNames {
+		apiCookies[i] = &whatsnew.DomainPolicyCookie{Name: name}
+	}
+
+	minRequestInterval := ""
+	if p.MinRequestInterval > 0 {
+		minRequestInterval = p.MinRequestInterval.String()
+	}
+
+	return &whatsnew.DomainPolicy{
+		Id:                 fmt.Sprintf("%d", p.ID),
+		CreatedAt:          p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:          p.UpdatedAt.Format(time.RFC3339),
+		Domain:             p.Domain,
+		UserAgent:          p.UserAgent,
+		Headers:            apiHeaders,
+		Cookies:            apiCookies,
+		ProxyUrl:           p.ProxyURL,
+		MinRequestInterval: minRequestInterval,
+		IgnoreRobotsTxt:    formatOptionalBool(p.IgnoreRobotsTxt),
+		InsecureSkipVerify: formatOptionalBool(p.InsecureSkipVerify),
+	}, nil
+}
+
+// formatOptionalBool formats a boolean value as "true" or "false", or as an
+// empty string if it is NULL.
+func formatOptionalBool(b sql.NullBool) string {
+	if !b.Valid {
+		return ""
+	}
+	return strconv.FormatBool(b.Bool)
+}
+
+func makeAPIScrapeFailure(f scrapestats.Failure) *whatsnew.ScrapeFailure {
+	return &whatsnew.ScrapeFailure{
+		Domain:        f.Domain,
+		Status:        string(f.Status),
+		Reason:        string(f.Reason),
+		HttpStatus:    int64(f.HTTPStatus),
+		Count:         f.Count,
+		LastAttemptAt: nullTimeToString(f.LastAttemptAt),
 	}
 }
 
@@ -94,6 +303,104 @@ func makeAPIInfoExtractionRule(rule models.InfoExtractionRule) *whatsnew.InfoExt
 	}
 }
 
+func makeAPIWebArticle(wa models.WebArticle, url string) *whatsnew.WebArticle {
+	return &whatsnew.WebArticle{
+		Id:                  fmt.Sprintf("%d", wa.ID),
+		CreatedAt:           wa.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           wa.UpdatedAt.Format(time.RFC3339),
+		Url:                 url,
+		Title:               wa.Title,
+		TopImage:            wa.TopImage.String,
+		ScrapedPublishDate:  nullTimeToString(wa.ScrapedPublishDate),
+		Language:            wa.Language,
+		PublishDate:         wa.PublishDate.Format(time.RFC3339),
+		TranslatedTitle:     wa.TranslatedTitle.String,
+		TranslationLanguage: wa.TranslationLanguage.String,
+		CountryCode:         wa.CountryCode.String,
+		ZeroShotClasses:     makeAPIZeroShotClasses(wa.ZeroShotClasses),
+		TextClasses:         makeAPITextClasses(wa.TextClasses),
+		ExtractedInfos:      makeAPIExtractedInfos(wa.ExtractedInfos),
+		SimilarityInfo:      makeAPISimilarityInfo(wa.SimilarityInfo),
+	}
+}
+
+func makeAPIZeroShotClasses(classes []models.ZeroShotClass) []*whatsnew.ZeroShotClass {
+	out := make([]*whatsnew.ZeroShotClass, len(classes))
+	for i, c := range classes {
+		out[i] = &whatsnew.ZeroShotClass{
+			TemplateId: fmt.Sprintf("%d", c.ZeroShotHypothesisTemplateID),
+			LabelId:    fmt.Sprintf("%d", c.ZeroShotHypothesisLabelID),
+			Best:       c.Best,
+			Confidence: c.Confidence,
+		}
+		if c.ZeroShotHypothesisLabel != nil {
+			out[i].Label = c.ZeroShotHypothesisLabel.Text
+		}
+	}
+	return out
+}
+
+func makeAPITextClasses(classes []models.TextClass) []*whatsnew.TextClass {
+	out := make([]*whatsnew.TextClass, len(classes))
+	for i, c := range classes {
+		out[i] = &whatsnew.TextClass{
+			Type:       c.Type,
+			Label:      c.Label,
+			Confidence: c.Confidence,
+		}
+	}
+	return out
+}
+
+func makeAPIExtractedInfos(infos []models.ExtractedInfo) []*whatsnew.ExtractedInfo {
+	out := make([]*whatsnew.ExtractedInfo, len(infos))
+	for i, info := range infos {
+		out[i] = &whatsnew.ExtractedInfo{
+			InfoExtractionRuleId: fmt.Sprintf("%d", info.InfoExtractionRuleID),
+			Text:                 info.Text,
+			Confidence:           info.Confidence,
+		}
+		if info.InfoExtractionRule != nil {
+			out[i].Label = info.InfoExtractionRule.Label
+		}
+	}
+	return out
+}
+
+func makeAPISimilarityInfo(si *models.SimilarityInfo) *whatsnew.SimilarityInfo {
+	if si == nil {
+		return nil
+	}
+	out := &whatsnew.SimilarityInfo{}
+	if si.ParentID != nil {
+		out.ParentId = fmt.Sprintf("%d", *si.ParentID)
+	}
+	if si.Distance != nil {
+		out.Distance = *si.Distance
+	}
+	if si.StoryID != nil {
+		out.StoryId = fmt.Sprintf("%d", *si.StoryID)
+	}
+	return out
+}
+
+func makeAPIStory(st models.Story, representative *whatsnew.WebArticle) (*whatsnew.Story, error) {
+	countryCodes, err := st.CountryCodesAsStringSlice()
+	if err != nil {
+		return nil, err
+	}
+	return &whatsnew.Story{
+		Id:             fmt.Sprintf("%d", st.ID),
+		CreatedAt:      st.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      st.UpdatedAt.Format(time.RFC3339),
+		Representative: representative,
+		FirstSeenAt:    st.FirstSeenAt.Format(time.RFC3339),
+		LastSeenAt:     st.LastSeenAt.Format(time.RFC3339),
+		MembersCount:   int64(st.MembersCount),
+		CountryCodes:   countryCodes,
+	}, nil
+}
+
 func nullTimeToString(t sql.NullTime) string {
 	if !t.Valid {
 		return ""
@@ -15,6 +15,12 @@ sed \
   -i "2i option go_package = \"github.com/SpecializedGeneralist/whatsnew/pkg/server/whatsnew\";" \
   whatsnew.proto
 
+# OpenAPI cannot describe streaming; make StreamWebArticles a
+# server-streaming RPC.
+sed \
+  -i "s/returns ( StreamWebArticlesResponse )/returns ( stream StreamWebArticlesResponse )/" \
+  whatsnew.proto
+
 # Generate whatsnew.pb.go
 protoc \
   --go_out=. \
@@ -36,6 +36,37 @@ type WhatsnewClient interface {
 	GetQueryTwitterSource(ctx context.Context, in *GetQueryTwitterSourceRequest, opts ...grpc.CallOption) (*GetQueryTwitterSourceResponse, error)
 	UpdateQueryTwitterSource(ctx context.Context, in *UpdateQueryTwitterSourceRequest, opts ...grpc.CallOption) (*UpdateQueryTwitterSourceResponse, error)
 	DeleteQueryTwitterSource(ctx context.Context, in *DeleteQueryTwitterSourceRequest, opts ...grpc.CallOption) (*DeleteQueryTwitterSourceResponse, error)
+	GetSitemaps(ctx context.Context, in *GetSitemapsRequest, opts ...grpc.CallOption) (*GetSitemapsResponse, error)
+	CreateSitemaps(ctx context.Context, in *CreateSitemapsRequest, opts ...grpc.CallOption) (*CreateSitemapsResponse, error)
+	CreateSitemap(ctx context.Context, in *CreateSitemapRequest, opts ...grpc.CallOption) (*CreateSitemapResponse, error)
+	GetSitemap(ctx context.Context, in *GetSitemapRequest, opts ...grpc.CallOption) (*GetSitemapResponse, error)
+	UpdateSitemap(ctx context.Context, in *UpdateSitemapRequest, opts ...grpc.CallOption) (*UpdateSitemapResponse, error)
+	DeleteSitemap(ctx context.Context, in *DeleteSitemapRequest, opts ...grpc.CallOption) (*DeleteSitemapResponse, error)
+	GetApiSources(ctx context.Context, in *GetApiSourcesRequest, opts ...grpc.CallOption) (*GetApiSourcesResponse, error)
+	CreateApiSources(ctx context.Context, in *CreateApiSourcesRequest, opts ...grpc.CallOption) (*CreateApiSourcesResponse, error)
+	CreateApiSource(ctx context.Context, in *CreateApiSourceRequest, opts ...grpc.CallOption) (*CreateApiSourceResponse, error)
+	GetApiSource(ctx context.Context, in *GetApiSourceRequest, opts ...grpc.CallOption) (*GetApiSourceResponse, error)
+	UpdateApiSource(ctx context.Context, in *UpdateApiSourceRequest, opts ...grpc.CallOption) (*UpdateApiSourceResponse, error)
+	DeleteApiSource(ctx context.Context, in *DeleteApiSourceRequest, opts ...grpc.CallOption) (*DeleteApiSourceResponse, error)
+	GetNewsletterSources(ctx context.Context, in *GetNewsletterSourcesRequest, opts ...grpc.CallOption) (*GetNewsletterSourcesResponse, error)
+	CreateNewsletterSources(ctx context.Context, in *CreateNewsletterSourcesRequest, opts ...grpc.CallOption) (*CreateNewsletterSourcesResponse, error)
+	CreateNewsletterSource(ctx context.Context, in *CreateNewsletterSourceRequest, opts ...grpc.CallOption) (*CreateNewsletterSourceResponse, error)
+	GetNewsletterSource(ctx context.Context, in *GetNewsletterSourceRequest, opts ...grpc.CallOption) (*GetNewsletterSourceResponse, error)
+	UpdateNewsletterSource(ctx context.Context, in *UpdateNewsletterSourceRequest, opts ...grpc.CallOption) (*UpdateNewsletterSourceResponse, error)
+	DeleteNewsletterSource(ctx context.Context, in *DeleteNewsletterSourceRequest, opts ...grpc.CallOption) (*DeleteNewsletterSourceResponse, error)
+	GetMastodonSources(ctx context.Context, in *GetMastodonSourcesRequest, opts ...grpc.CallOption) (*GetMastodonSourcesResponse, error)
+	CreateMastodonSources(ctx context.Context, in *CreateMastodonSourcesRequest, opts ...grpc.CallOption) (*CreateMastodonSourcesResponse, error)
+	CreateMastodonSource(ctx context.Context, in *CreateMastodonSourceRequest, opts ...grpc.CallOption) (*CreateMastodonSourceResponse, error)
+	GetMastodonSource(ctx context.Context, in *GetMastodonSourceRequest, opts ...grpc.CallOption) (*GetMastodonSourceResponse, error)
+	UpdateMastodonSource(ctx context.Context, in *UpdateMastodonSourceRequest, opts ...grpc.CallOption) (*UpdateMastodonSourceResponse, error)
+	DeleteMastodonSource(ctx context.Context, in *DeleteMastodonSourceRequest, opts ...grpc.CallOption) (*DeleteMastodonSourceResponse, error)
+	GetDomainPolicies(ctx context.Context, in *GetDomainPoliciesRequest, opts ...grpc.CallOption) (*GetDomainPoliciesResponse, error)
+	CreateDomainPolicies(ctx context.Context, in *CreateDomainPoliciesRequest, opts ...grpc.CallOption) (*CreateDomainPoliciesResponse, error)
+	CreateDomainPolicy(ctx context.Context, in *CreateDomainPolicyRequest, opts ...grpc.CallOption) (*CreateDomainPolicyResponse, error)
+	GetDomainPolicy(ctx context.Context, in *GetDomainPolicyRequest, opts ...grpc.CallOption) (*GetDomainPolicyResponse, error)
+	UpdateDomainPolicy(ctx context.Context, in *UpdateDomainPolicyRequest, opts ...grpc.CallOption) (*UpdateDomainPolicyResponse, error)
+	DeleteDomainPolicy(ctx context.Context, in *DeleteDomainPolicyRequest, opts ...grpc.CallOption) (*DeleteDomainPolicyResponse, error)
+	GetScrapeFailures(ctx context.Context, in *GetScrapeFailuresRequest, opts ...grpc.CallOption) (*GetScrapeFailuresResponse, error)
 	GetZeroShotHypothesisTemplates(ctx context.Context, in *GetZeroShotHypothesisTemplatesRequest, opts ...grpc.CallOption) (*GetZeroShotHypothesisTemplatesResponse, error)
 	CreateZeroShotHypothesisTemplates(ctx context.Context, in *CreateZeroShotHypothesisTemplatesRequest, opts ...grpc.CallOption) (*CreateZeroShotHypothesisTemplatesResponse, error)
 	CreateZeroShotHypothesisTemplate(ctx context.Context, in *CreateZeroShotHypothesisTemplateRequest, opts ...grpc.CallOption) (*CreateZeroShotHypothesisTemplateResponse, error)
@@ -53,6 +84,15 @@ type WhatsnewClient interface {
 	GetInfoExtractionRule(ctx context.Context, in *GetInfoExtractionRuleRequest, opts ...grpc.CallOption) (*GetInfoExtractionRuleResponse, error)
 	UpdateInfoExtractionRule(ctx context.Context, in *UpdateInfoExtractionRuleRequest, opts ...grpc.CallOption) (*UpdateInfoExtractionRuleResponse, error)
 	DeleteInfoExtractionRule(ctx context.Context, in *DeleteInfoExtractionRuleRequest, opts ...grpc.CallOption) (*DeleteInfoExtractionRuleResponse, error)
+	GetWebArticles(ctx context.Context, in *GetWebArticlesRequest, opts ...grpc.CallOption) (*GetWebArticlesResponse, error)
+	StreamWebArticles(ctx context.Context, in *StreamWebArticlesRequest, opts ...grpc.CallOption) (Whatsnew_StreamWebArticlesClient, error)
+	SearchSimilarArticles(ctx context.Context, in *SearchSimilarArticlesRequest, opts ...grpc.CallOption) (*SearchSimilarArticlesResponse, error)
+	GetWebArticle(ctx context.Context, in *GetWebArticleRequest, opts ...grpc.CallOption) (*GetWebArticleResponse, error)
+	GetTrendingStories(ctx context.Context, in *GetTrendingStoriesRequest, opts ...grpc.CallOption) (*GetTrendingStoriesResponse, error)
+	GetStory(ctx context.Context, in *GetStoryRequest, opts ...grpc.CallOption) (*GetStoryResponse, error)
+	DiscoverFeeds(ctx context.Context, in *DiscoverFeedsRequest, opts ...grpc.CallOption) (*DiscoverFeedsResponse, error)
+	ExportOPML(ctx context.Context, in *ExportOPMLRequest, opts ...grpc.CallOption) (*ExportOPMLResponse, error)
+	ImportOPML(ctx context.Context, in *ImportOPMLRequest, opts ...grpc.CallOption) (*ImportOPMLResponse, error)
 }
 
 type whatsnewClient struct {
@@ -225,6 +265,285 @@ func (c *whatsnewClient) DeleteQueryTwitterSource(ctx context.Context, in *Delet
 	return out, nil
 }
 
+func (c *whatsnewClient) GetSitemaps(ctx context.Context, in *GetSitemapsRequest, opts ...grpc.CallOption) (*GetSitemapsResponse, error) {
+	out := new(GetSitemapsResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetSitemaps", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) CreateSitemaps(ctx context.Context, in *CreateSitemapsRequest, opts ...grpc.CallOption) (*CreateSitemapsResponse, error) {
+	out := new(CreateSitemapsResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/CreateSitemaps", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) CreateSitemap(ctx context.Context, in *CreateSitemapRequest, opts ...grpc.CallOption) (*CreateSitemapResponse, error) {
+	out := new(CreateSitemapResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/CreateSitemap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetSitemap(ctx context.Context, in *GetSitemapRequest, opts ...grpc.CallOption) (*GetSitemapResponse, error) {
+	out := new(GetSitemapResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetSitemap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) UpdateSitemap(ctx context.Context, in *UpdateSitemapRequest, opts ...grpc.CallOption) (*UpdateSitemapResponse, error) {
+	out := new(UpdateSitemapResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/UpdateSitemap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) DeleteSitemap(ctx context.Context, in *DeleteSitemapRequest, opts ...grpc.CallOption) (*DeleteSitemapResponse, error) {
+	out := new(DeleteSitemapResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/DeleteSitemap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetApiSources(ctx context.Context, in *GetApiSourcesRequest, opts ...grpc.CallOption) (*GetApiSourcesResponse, error) {
+	out := new(GetApiSourcesResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetApiSources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) CreateApiSources(ctx context.Context, in *CreateApiSourcesRequest, opts ...grpc.CallOption) (*CreateApiSourcesResponse, error) {
+	out := new(CreateApiSourcesResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/CreateApiSources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) CreateApiSource(ctx context.Context, in *CreateApiSourceRequest, opts ...grpc.CallOption) (*CreateApiSourceResponse, error) {
+	out := new(CreateApiSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/CreateApiSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetApiSource(ctx context.Context, in *GetApiSourceRequest, opts ...grpc.CallOption) (*GetApiSourceResponse, error) {
+	out := new(GetApiSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetApiSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) UpdateApiSource(ctx context.Context, in *UpdateApiSourceRequest, opts ...grpc.CallOption) (*UpdateApiSourceResponse, error) {
+	out := new(UpdateApiSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/UpdateApiSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) DeleteApiSource(ctx context.Context, in *DeleteApiSourceRequest, opts ...grpc.CallOption) (*DeleteApiSourceResponse, error) {
+	out := new(DeleteApiSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/DeleteApiSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetNewsletterSources(ctx context.Context, in *GetNewsletterSourcesRequest, opts ...grpc.CallOption) (*GetNewsletterSourcesResponse, error) {
+	out := new(GetNewsletterSourcesResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetNewsletterSources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) CreateNewsletterSources(ctx context.Context, in *CreateNewsletterSourcesRequest, opts ...grpc.CallOption) (*CreateNewsletterSourcesResponse, error) {
+	out := new(CreateNewsletterSourcesResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/CreateNewsletterSources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) CreateNewsletterSource(ctx context.Context, in *CreateNewsletterSourceRequest, opts ...grpc.CallOption) (*CreateNewsletterSourceResponse, error) {
+	out := new(CreateNewsletterSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/CreateNewsletterSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetNewsletterSource(ctx context.Context, in *GetNewsletterSourceRequest, opts ...grpc.CallOption) (*GetNewsletterSourceResponse, error) {
+	out := new(GetNewsletterSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetNewsletterSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) UpdateNewsletterSource(ctx context.Context, in *UpdateNewsletterSourceRequest, opts ...grpc.CallOption) (*UpdateNewsletterSourceResponse, error) {
+	out := new(UpdateNewsletterSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/UpdateNewsletterSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) DeleteNewsletterSource(ctx context.Context, in *DeleteNewsletterSourceRequest, opts ...grpc.CallOption) (*DeleteNewsletterSourceResponse, error) {
+	out := new(DeleteNewsletterSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/DeleteNewsletterSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetMastodonSources(ctx context.Context, in *GetMastodonSourcesRequest, opts ...grpc.CallOption) (*GetMastodonSourcesResponse, error) {
+	out := new(GetMastodonSourcesResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetMastodonSources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) CreateMastodonSources(ctx context.Context, in *CreateMastodonSourcesRequest, opts ...grpc.CallOption) (*CreateMastodonSourcesResponse, error) {
+	out := new(CreateMastodonSourcesResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/CreateMastodonSources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) CreateMastodonSource(ctx context.Context, in *CreateMastodonSourceRequest, opts ...grpc.CallOption) (*CreateMastodonSourceResponse, error) {
+	out := new(CreateMastodonSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/CreateMastodonSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetMastodonSource(ctx context.Context, in *GetMastodonSourceRequest, opts ...grpc.CallOption) (*GetMastodonSourceResponse, error) {
+	out := new(GetMastodonSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetMastodonSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) UpdateMastodonSource(ctx context.Context, in *UpdateMastodonSourceRequest, opts ...grpc.CallOption) (*UpdateMastodonSourceResponse, error) {
+	out := new(UpdateMastodonSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/UpdateMastodonSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) DeleteMastodonSource(ctx context.Context, in *DeleteMastodonSourceRequest, opts ...grpc.CallOption) (*DeleteMastodonSourceResponse, error) {
+	out := new(DeleteMastodonSourceResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/DeleteMastodonSource", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetDomainPolicies(ctx context.Context, in *GetDomainPoliciesRequest, opts ...grpc.CallOption) (*GetDomainPoliciesResponse, error) {
+	out := new(GetDomainPoliciesResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetDomainPolicies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) CreateDomainPolicies(ctx context.Context, in *CreateDomainPoliciesRequest, opts ...grpc.CallOption) (*CreateDomainPoliciesResponse, error) {
+	out := new(CreateDomainPoliciesResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/CreateDomainPolicies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) CreateDomainPolicy(ctx context.Context, in *CreateDomainPolicyRequest, opts ...grpc.CallOption) (*CreateDomainPolicyResponse, error) {
+	out := new(CreateDomainPolicyResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/CreateDomainPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetDomainPolicy(ctx context.Context, in *GetDomainPolicyRequest, opts ...grpc.CallOption) (*GetDomainPolicyResponse, error) {
+	out := new(GetDomainPolicyResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetDomainPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) UpdateDomainPolicy(ctx context.Context, in *UpdateDomainPolicyRequest, opts ...grpc.CallOption) (*UpdateDomainPolicyResponse, error) {
+	out := new(UpdateDomainPolicyResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/UpdateDomainPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) DeleteDomainPolicy(ctx context.Context, in *DeleteDomainPolicyRequest, opts ...grpc.CallOption) (*DeleteDomainPolicyResponse, error) {
+	out := new(DeleteDomainPolicyResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/DeleteDomainPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetScrapeFailures(ctx context.Context, in *GetScrapeFailuresRequest, opts ...grpc.CallOption) (*GetScrapeFailuresResponse, error) {
+	out := new(GetScrapeFailuresResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetScrapeFailures", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *whatsnewClient) GetZeroShotHypothesisTemplates(ctx context.Context, in *GetZeroShotHypothesisTemplatesRequest, opts ...grpc.CallOption) (*GetZeroShotHypothesisTemplatesResponse, error) {
 	out := new(GetZeroShotHypothesisTemplatesResponse)
 	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetZeroShotHypothesisTemplates", in, out, opts...)
@@ -378,6 +697,110 @@ func (c *whatsnewClient) DeleteInfoExtractionRule(ctx context.Context, in *Delet
 	return out, nil
 }
 
+func (c *whatsnewClient) GetWebArticles(ctx context.Context, in *GetWebArticlesRequest, opts ...grpc.CallOption) (*GetWebArticlesResponse, error) {
+	out := new(GetWebArticlesResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetWebArticles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) StreamWebArticles(ctx context.Context, in *StreamWebArticlesRequest, opts ...grpc.CallOption) (Whatsnew_StreamWebArticlesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Whatsnew_ServiceDesc.Streams[0], "/whatsnew.Whatsnew/StreamWebArticles", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &whatsnewStreamWebArticlesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Whatsnew_StreamWebArticlesClient interface {
+	Recv() (*StreamWebArticlesResponse, error)
+	grpc.ClientStream
+}
+
+type whatsnewStreamWebArticlesClient struct {
+	grpc.ClientStream
+}
+
+func (x *whatsnewStreamWebArticlesClient) Recv() (*StreamWebArticlesResponse, error) {
+	m := new(StreamWebArticlesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *whatsnewClient) SearchSimilarArticles(ctx context.Context, in *SearchSimilarArticlesRequest, opts ...grpc.CallOption) (*SearchSimilarArticlesResponse, error) {
+	out := new(SearchSimilarArticlesResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/SearchSimilarArticles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetWebArticle(ctx context.Context, in *GetWebArticleRequest, opts ...grpc.CallOption) (*GetWebArticleResponse, error) {
+	out := new(GetWebArticleResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetWebArticle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetTrendingStories(ctx context.Context, in *GetTrendingStoriesRequest, opts ...grpc.CallOption) (*GetTrendingStoriesResponse, error) {
+	out := new(GetTrendingStoriesResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetTrendingStories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) GetStory(ctx context.Context, in *GetStoryRequest, opts ...grpc.CallOption) (*GetStoryResponse, error) {
+	out := new(GetStoryResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/GetStory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) DiscoverFeeds(ctx context.Context, in *DiscoverFeedsRequest, opts ...grpc.CallOption) (*DiscoverFeedsResponse, error) {
+	out := new(DiscoverFeedsResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/DiscoverFeeds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) ExportOPML(ctx context.Context, in *ExportOPMLRequest, opts ...grpc.CallOption) (*ExportOPMLResponse, error) {
+	out := new(ExportOPMLResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/ExportOPML", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *whatsnewClient) ImportOPML(ctx context.Context, in *ImportOPMLRequest, opts ...grpc.CallOption) (*ImportOPMLResponse, error) {
+	out := new(ImportOPMLResponse)
+	err := c.cc.Invoke(ctx, "/whatsnew.Whatsnew/ImportOPML", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WhatsnewServer is the server API for Whatsnew service.
 // All implementations must embed UnimplementedWhatsnewServer
 // for forward compatibility
@@ -400,6 +823,37 @@ type WhatsnewServer interface {
 	GetQueryTwitterSource(context.Context, *GetQueryTwitterSourceRequest) (*GetQueryTwitterSourceResponse, error)
 	UpdateQueryTwitterSource(context.Context, *UpdateQueryTwitterSourceRequest) (*UpdateQueryTwitterSourceResponse, error)
 	DeleteQueryTwitterSource(context.Context, *DeleteQueryTwitterSourceRequest) (*DeleteQueryTwitterSourceResponse, error)
+	GetSitemaps(context.Context, *GetSitemapsRequest) (*GetSitemapsResponse, error)
+	CreateSitemaps(context.Context, *CreateSitemapsRequest) (*CreateSitemapsResponse, error)
+	CreateSitemap(context.Context, *CreateSitemapRequest) (*CreateSitemapResponse, error)
+	GetSitemap(context.Context, *GetSitemapRequest) (*GetSitemapResponse, error)
+	UpdateSitemap(context.Context, *UpdateSitemapRequest) (*UpdateSitemapResponse, error)
+	DeleteSitemap(context.Context, *DeleteSitemapRequest) (*DeleteSitemapResponse, error)
+	GetApiSources(context.Context, *GetApiSourcesRequest) (*GetApiSourcesResponse, error)
+	CreateApiSources(context.Context, *CreateApiSourcesRequest) (*CreateApiSourcesResponse, error)
+	CreateApiSource(context.Context, *CreateApiSourceRequest) (*CreateApiSourceResponse, error)
+	GetApiSource(context.Context, *GetApiSourceRequest) (*GetApiSourceResponse, error)
+	UpdateApiSource(context.Context, *UpdateApiSourceRequest) (*UpdateApiSourceResponse, error)
+	DeleteApiSource(context.Context, *DeleteApiSourceRequest) (*DeleteApiSourceResponse, error)
+	GetNewsletterSources(context.Context, *GetNewsletterSourcesRequest) (*GetNewsletterSourcesResponse, error)
+	CreateNewsletterSources(context.Context, *CreateNewsletterSourcesRequest) (*CreateNewsletterSourcesResponse, error)
+	CreateNewsletterSource(context.Context, *CreateNewsletterSourceRequest) (*CreateNewsletterSourceResponse, error)
+	GetNewsletterSource(context.Context, *GetNewsletterSourceRequest) (*GetNewsletterSourceResponse, error)
+	UpdateNewsletterSource(context.Context, *UpdateNewsletterSourceRequest) (*UpdateNewsletterSourceResponse, error)
+	DeleteNewsletterSource(context.Context, *DeleteNewsletterSourceRequest) (*DeleteNewsletterSourceResponse, error)
+	GetMastodonSources(context.Context, *GetMastodonSourcesRequest) (*GetMastodonSourcesResponse, error)
+	CreateMastodonSources(context.Context, *CreateMastodonSourcesRequest) (*CreateMastodonSourcesResponse, error)
+	CreateMastodonSource(context.Context, *CreateMastodonSourceRequest) (*CreateMastodonSourceResponse, error)
+	GetMastodonSource(context.Context, *GetMastodonSourceRequest) (*GetMastodonSourceResponse, error)
+	UpdateMastodonSource(context.Context, *UpdateMastodonSourceRequest) (*UpdateMastodonSourceResponse, error)
+	DeleteMastodonSource(context.Context, *DeleteMastodonSourceRequest) (*DeleteMastodonSourceResponse, error)
+	GetDomainPolicies(context.Context, *GetDomainPoliciesRequest) (*GetDomainPoliciesResponse, error)
+	CreateDomainPolicies(context.Context, *CreateDomainPoliciesRequest) (*CreateDomainPoliciesResponse, error)
+	CreateDomainPolicy(context.Context, *CreateDomainPolicyRequest) (*CreateDomainPolicyResponse, error)
+	GetDomainPolicy(context.Context, *GetDomainPolicyRequest) (*GetDomainPolicyResponse, error)
+	UpdateDomainPolicy(context.Context, *UpdateDomainPolicyRequest) (*UpdateDomainPolicyResponse, error)
+	DeleteDomainPolicy(context.Context, *DeleteDomainPolicyRequest) (*DeleteDomainPolicyResponse, error)
+	GetScrapeFailures(context.Context, *GetScrapeFailuresRequest) (*GetScrapeFailuresResponse, error)
 	GetZeroShotHypothesisTemplates(context.Context, *GetZeroShotHypothesisTemplatesRequest) (*GetZeroShotHypothesisTemplatesResponse, error)
 	CreateZeroShotHypothesisTemplates(context.Context, *CreateZeroShotHypothesisTemplatesRequest) (*CreateZeroShotHypothesisTemplatesResponse, error)
 	CreateZeroShotHypothesisTemplate(context.Context, *CreateZeroShotHypothesisTemplateRequest) (*CreateZeroShotHypothesisTemplateResponse, error)
@@ -417,6 +871,15 @@ type WhatsnewServer interface {
 	GetInfoExtractionRule(context.Context, *GetInfoExtractionRuleRequest) (*GetInfoExtractionRuleResponse, error)
 	UpdateInfoExtractionRule(context.Context, *UpdateInfoExtractionRuleRequest) (*UpdateInfoExtractionRuleResponse, error)
 	DeleteInfoExtractionRule(context.Context, *DeleteInfoExtractionRuleRequest) (*DeleteInfoExtractionRuleResponse, error)
+	GetWebArticles(context.Context, *GetWebArticlesRequest) (*GetWebArticlesResponse, error)
+	StreamWebArticles(*StreamWebArticlesRequest, Whatsnew_StreamWebArticlesServer) error
+	SearchSimilarArticles(context.Context, *SearchSimilarArticlesRequest) (*SearchSimilarArticlesResponse, error)
+	GetWebArticle(context.Context, *GetWebArticleRequest) (*GetWebArticleResponse, error)
+	GetTrendingStories(context.Context, *GetTrendingStoriesRequest) (*GetTrendingStoriesResponse, error)
+	GetStory(context.Context, *GetStoryRequest) (*GetStoryResponse, error)
+	DiscoverFeeds(context.Context, *DiscoverFeedsRequest) (*DiscoverFeedsResponse, error)
+	ExportOPML(context.Context, *ExportOPMLRequest) (*ExportOPMLResponse, error)
+	ImportOPML(context.Context, *ImportOPMLRequest) (*ImportOPMLResponse, error)
 	mustEmbedUnimplementedWhatsnewServer()
 }
 
@@ -478,13 +941,106 @@ func (UnimplementedWhatsnewServer) UpdateQueryTwitterSource(context.Context, *Up
 func (UnimplementedWhatsnewServer) DeleteQueryTwitterSource(context.Context, *DeleteQueryTwitterSourceRequest) (*DeleteQueryTwitterSourceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteQueryTwitterSource not implemented")
 }
-func (UnimplementedWhatsnewServer) GetZeroShotHypothesisTemplates(context.Context, *GetZeroShotHypothesisTemplatesRequest) (*GetZeroShotHypothesisTemplatesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetZeroShotHypothesisTemplates not implemented")
+func (UnimplementedWhatsnewServer) GetSitemaps(context.Context, *GetSitemapsRequest) (*GetSitemapsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSitemaps not implemented")
 }
-func (UnimplementedWhatsnewServer) CreateZeroShotHypothesisTemplates(context.Context, *CreateZeroShotHypothesisTemplatesRequest) (*CreateZeroShotHypothesisTemplatesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateZeroShotHypothesisTemplates not implemented")
+func (UnimplementedWhatsnewServer) CreateSitemaps(context.Context, *CreateSitemapsRequest) (*CreateSitemapsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSitemaps not implemented")
 }
-func (UnimplementedWhatsnewServer) CreateZeroShotHypothesisTemplate(context.Context, *CreateZeroShotHypothesisTemplateRequest) (*CreateZeroShotHypothesisTemplateResponse, error) {
+func (UnimplementedWhatsnewServer) CreateSitemap(context.Context, *CreateSitemapRequest) (*CreateSitemapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSitemap not implemented")
+}
+func (UnimplementedWhatsnewServer) GetSitemap(context.Context, *GetSitemapRequest) (*GetSitemapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSitemap not implemented")
+}
+func (UnimplementedWhatsnewServer) UpdateSitemap(context.Context, *UpdateSitemapRequest) (*UpdateSitemapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSitemap not implemented")
+}
+func (UnimplementedWhatsnewServer) DeleteSitemap(context.Context, *DeleteSitemapRequest) (*DeleteSitemapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSitemap not implemented")
+}
+func (UnimplementedWhatsnewServer) GetApiSources(context.Context, *GetApiSourcesRequest) (*GetApiSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetApiSources not implemented")
+}
+func (UnimplementedWhatsnewServer) CreateApiSources(context.Context, *CreateApiSourcesRequest) (*CreateApiSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApiSources not implemented")
+}
+func (UnimplementedWhatsnewServer) CreateApiSource(context.Context, *CreateApiSourceRequest) (*CreateApiSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateApiSource not implemented")
+}
+func (UnimplementedWhatsnewServer) GetApiSource(context.Context, *GetApiSourceRequest) (*GetApiSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetApiSource not implemented")
+}
+func (UnimplementedWhatsnewServer) UpdateApiSource(context.Context, *UpdateApiSourceRequest) (*UpdateApiSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateApiSource not implemented")
+}
+func (UnimplementedWhatsnewServer) DeleteApiSource(context.Context, *DeleteApiSourceRequest) (*DeleteApiSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteApiSource not implemented")
+}
+func (UnimplementedWhatsnewServer) GetNewsletterSources(context.Context, *GetNewsletterSourcesRequest) (*GetNewsletterSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNewsletterSources not implemented")
+}
+func (UnimplementedWhatsnewServer) CreateNewsletterSources(context.Context, *CreateNewsletterSourcesRequest) (*CreateNewsletterSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNewsletterSources not implemented")
+}
+func (UnimplementedWhatsnewServer) CreateNewsletterSource(context.Context, *CreateNewsletterSourceRequest) (*CreateNewsletterSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNewsletterSource not implemented")
+}
+func (UnimplementedWhatsnewServer) GetNewsletterSource(context.Context, *GetNewsletterSourceRequest) (*GetNewsletterSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNewsletterSource not implemented")
+}
+func (UnimplementedWhatsnewServer) UpdateNewsletterSource(context.Context, *UpdateNewsletterSourceRequest) (*UpdateNewsletterSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNewsletterSource not implemented")
+}
+func (UnimplementedWhatsnewServer) DeleteNewsletterSource(context.Context, *DeleteNewsletterSourceRequest) (*DeleteNewsletterSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteNewsletterSource not implemented")
+}
+func (UnimplementedWhatsnewServer) GetMastodonSources(context.Context, *GetMastodonSourcesRequest) (*GetMastodonSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMastodonSources not implemented")
+}
+func (UnimplementedWhatsnewServer) CreateMastodonSources(context.Context, *CreateMastodonSourcesRequest) (*CreateMastodonSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMastodonSources not implemented")
+}
+func (UnimplementedWhatsnewServer) CreateMastodonSource(context.Context, *CreateMastodonSourceRequest) (*CreateMastodonSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMastodonSource not implemented")
+}
+func (UnimplementedWhatsnewServer) GetMastodonSource(context.Context, *GetMastodonSourceRequest) (*GetMastodonSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMastodonSource not implemented")
+}
+func (UnimplementedWhatsnewServer) UpdateMastodonSource(context.Context, *UpdateMastodonSourceRequest) (*UpdateMastodonSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMastodonSource not implemented")
+}
+func (UnimplementedWhatsnewServer) DeleteMastodonSource(context.Context, *DeleteMastodonSourceRequest) (*DeleteMastodonSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMastodonSource not implemented")
+}
+func (UnimplementedWhatsnewServer) GetDomainPolicies(context.Context, *GetDomainPoliciesRequest) (*GetDomainPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDomainPolicies not implemented")
+}
+func (UnimplementedWhatsnewServer) CreateDomainPolicies(context.Context, *CreateDomainPoliciesRequest) (*CreateDomainPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDomainPolicies not implemented")
+}
+func (UnimplementedWhatsnewServer) CreateDomainPolicy(context.Context, *CreateDomainPolicyRequest) (*CreateDomainPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDomainPolicy not implemented")
+}
+func (UnimplementedWhatsnewServer) GetDomainPolicy(context.Context, *GetDomainPolicyRequest) (*GetDomainPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDomainPolicy not implemented")
+}
+func (UnimplementedWhatsnewServer) UpdateDomainPolicy(context.Context, *UpdateDomainPolicyRequest) (*UpdateDomainPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDomainPolicy not implemented")
+}
+func (UnimplementedWhatsnewServer) DeleteDomainPolicy(context.Context, *DeleteDomainPolicyRequest) (*DeleteDomainPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDomainPolicy not implemented")
+}
+func (UnimplementedWhatsnewServer) GetScrapeFailures(context.Context, *GetScrapeFailuresRequest) (*GetScrapeFailuresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScrapeFailures not implemented")
+}
+func (UnimplementedWhatsnewServer) GetZeroShotHypothesisTemplates(context.Context, *GetZeroShotHypothesisTemplatesRequest) (*GetZeroShotHypothesisTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetZeroShotHypothesisTemplates not implemented")
+}
+func (UnimplementedWhatsnewServer) CreateZeroShotHypothesisTemplates(context.Context, *CreateZeroShotHypothesisTemplatesRequest) (*CreateZeroShotHypothesisTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateZeroShotHypothesisTemplates not implemented")
+}
+func (UnimplementedWhatsnewServer) CreateZeroShotHypothesisTemplate(context.Context, *CreateZeroShotHypothesisTemplateRequest) (*CreateZeroShotHypothesisTemplateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateZeroShotHypothesisTemplate not implemented")
 }
 func (UnimplementedWhatsnewServer) GetZeroShotHypothesisTemplate(context.Context, *GetZeroShotHypothesisTemplateRequest) (*GetZeroShotHypothesisTemplateResponse, error) {
@@ -529,6 +1085,33 @@ func (UnimplementedWhatsnewServer) UpdateInfoExtractionRule(context.Context, *Up
 func (UnimplementedWhatsnewServer) DeleteInfoExtractionRule(context.Context, *DeleteInfoExtractionRuleRequest) (*DeleteInfoExtractionRuleResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteInfoExtractionRule not implemented")
 }
+func (UnimplementedWhatsnewServer) GetWebArticles(context.Context, *GetWebArticlesRequest) (*GetWebArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebArticles not implemented")
+}
+func (UnimplementedWhatsnewServer) StreamWebArticles(*StreamWebArticlesRequest, Whatsnew_StreamWebArticlesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamWebArticles not implemented")
+}
+func (UnimplementedWhatsnewServer) SearchSimilarArticles(context.Context, *SearchSimilarArticlesRequest) (*SearchSimilarArticlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchSimilarArticles not implemented")
+}
+func (UnimplementedWhatsnewServer) GetWebArticle(context.Context, *GetWebArticleRequest) (*GetWebArticleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebArticle not implemented")
+}
+func (UnimplementedWhatsnewServer) GetTrendingStories(context.Context, *GetTrendingStoriesRequest) (*GetTrendingStoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrendingStories not implemented")
+}
+func (UnimplementedWhatsnewServer) GetStory(context.Context, *GetStoryRequest) (*GetStoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStory not implemented")
+}
+func (UnimplementedWhatsnewServer) DiscoverFeeds(context.Context, *DiscoverFeedsRequest) (*DiscoverFeedsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscoverFeeds not implemented")
+}
+func (UnimplementedWhatsnewServer) ExportOPML(context.Context, *ExportOPMLRequest) (*ExportOPMLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportOPML not implemented")
+}
+func (UnimplementedWhatsnewServer) ImportOPML(context.Context, *ImportOPMLRequest) (*ImportOPMLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportOPML not implemented")
+}
 func (UnimplementedWhatsnewServer) mustEmbedUnimplementedWhatsnewServer() {}
 
 // UnsafeWhatsnewServer may be embedded to opt out of forward compatibility for this service.
@@ -642,226 +1225,784 @@ func _Whatsnew_DeleteFeed_Handler(srv interface{}, ctx context.Context, dec func
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/DeleteFeed",
+		FullMethod: "/whatsnew.Whatsnew/DeleteFeed",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).DeleteFeed(ctx, req.(*DeleteFeedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetUserTwitterSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserTwitterSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetUserTwitterSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetUserTwitterSources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetUserTwitterSources(ctx, req.(*GetUserTwitterSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_CreateUserTwitterSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserTwitterSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).CreateUserTwitterSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/CreateUserTwitterSources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).CreateUserTwitterSources(ctx, req.(*CreateUserTwitterSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_CreateUserTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserTwitterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).CreateUserTwitterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/CreateUserTwitterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).CreateUserTwitterSource(ctx, req.(*CreateUserTwitterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetUserTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserTwitterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetUserTwitterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetUserTwitterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetUserTwitterSource(ctx, req.(*GetUserTwitterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_UpdateUserTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserTwitterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).UpdateUserTwitterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/UpdateUserTwitterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).UpdateUserTwitterSource(ctx, req.(*UpdateUserTwitterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_DeleteUserTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserTwitterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).DeleteUserTwitterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/DeleteUserTwitterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).DeleteUserTwitterSource(ctx, req.(*DeleteUserTwitterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetQueryTwitterSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQueryTwitterSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetQueryTwitterSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetQueryTwitterSources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetQueryTwitterSources(ctx, req.(*GetQueryTwitterSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_CreateQueryTwitterSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateQueryTwitterSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).CreateQueryTwitterSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/CreateQueryTwitterSources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).CreateQueryTwitterSources(ctx, req.(*CreateQueryTwitterSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_CreateQueryTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateQueryTwitterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).CreateQueryTwitterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/CreateQueryTwitterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).CreateQueryTwitterSource(ctx, req.(*CreateQueryTwitterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetQueryTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQueryTwitterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetQueryTwitterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetQueryTwitterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetQueryTwitterSource(ctx, req.(*GetQueryTwitterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_UpdateQueryTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateQueryTwitterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).UpdateQueryTwitterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/UpdateQueryTwitterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).UpdateQueryTwitterSource(ctx, req.(*UpdateQueryTwitterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_DeleteQueryTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteQueryTwitterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).DeleteQueryTwitterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/DeleteQueryTwitterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).DeleteQueryTwitterSource(ctx, req.(*DeleteQueryTwitterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetSitemaps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSitemapsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetSitemaps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetSitemaps",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetSitemaps(ctx, req.(*GetSitemapsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_CreateSitemaps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSitemapsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).CreateSitemaps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/CreateSitemaps",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).CreateSitemaps(ctx, req.(*CreateSitemapsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_CreateSitemap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSitemapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).CreateSitemap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/CreateSitemap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).CreateSitemap(ctx, req.(*CreateSitemapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetSitemap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSitemapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetSitemap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetSitemap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetSitemap(ctx, req.(*GetSitemapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_UpdateSitemap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSitemapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).UpdateSitemap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/UpdateSitemap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).UpdateSitemap(ctx, req.(*UpdateSitemapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_DeleteSitemap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSitemapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).DeleteSitemap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/DeleteSitemap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).DeleteSitemap(ctx, req.(*DeleteSitemapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetApiSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetApiSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetApiSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetApiSources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetApiSources(ctx, req.(*GetApiSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_CreateApiSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateApiSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).CreateApiSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/CreateApiSources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).CreateApiSources(ctx, req.(*CreateApiSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_CreateApiSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateApiSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).CreateApiSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/CreateApiSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).CreateApiSource(ctx, req.(*CreateApiSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetApiSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetApiSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetApiSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetApiSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetApiSource(ctx, req.(*GetApiSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_UpdateApiSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateApiSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).UpdateApiSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/UpdateApiSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).UpdateApiSource(ctx, req.(*UpdateApiSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_DeleteApiSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteApiSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).DeleteApiSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/DeleteApiSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).DeleteApiSource(ctx, req.(*DeleteApiSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetNewsletterSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNewsletterSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetNewsletterSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetNewsletterSources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetNewsletterSources(ctx, req.(*GetNewsletterSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_CreateNewsletterSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNewsletterSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).CreateNewsletterSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/CreateNewsletterSources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).CreateNewsletterSources(ctx, req.(*CreateNewsletterSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_CreateNewsletterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNewsletterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).CreateNewsletterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/CreateNewsletterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).CreateNewsletterSource(ctx, req.(*CreateNewsletterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetNewsletterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNewsletterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetNewsletterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetNewsletterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetNewsletterSource(ctx, req.(*GetNewsletterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_UpdateNewsletterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNewsletterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).UpdateNewsletterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/UpdateNewsletterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).UpdateNewsletterSource(ctx, req.(*UpdateNewsletterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_DeleteNewsletterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNewsletterSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).DeleteNewsletterSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/DeleteNewsletterSource",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).DeleteNewsletterSource(ctx, req.(*DeleteNewsletterSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetMastodonSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMastodonSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetMastodonSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetMastodonSources",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).DeleteFeed(ctx, req.(*DeleteFeedRequest))
+		return srv.(WhatsnewServer).GetMastodonSources(ctx, req.(*GetMastodonSourcesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_GetUserTwitterSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetUserTwitterSourcesRequest)
+func _Whatsnew_CreateMastodonSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMastodonSourcesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).GetUserTwitterSources(ctx, in)
+		return srv.(WhatsnewServer).CreateMastodonSources(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/GetUserTwitterSources",
+		FullMethod: "/whatsnew.Whatsnew/CreateMastodonSources",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).GetUserTwitterSources(ctx, req.(*GetUserTwitterSourcesRequest))
+		return srv.(WhatsnewServer).CreateMastodonSources(ctx, req.(*CreateMastodonSourcesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_CreateUserTwitterSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateUserTwitterSourcesRequest)
+func _Whatsnew_CreateMastodonSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMastodonSourceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).CreateUserTwitterSources(ctx, in)
+		return srv.(WhatsnewServer).CreateMastodonSource(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/CreateUserTwitterSources",
+		FullMethod: "/whatsnew.Whatsnew/CreateMastodonSource",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).CreateUserTwitterSources(ctx, req.(*CreateUserTwitterSourcesRequest))
+		return srv.(WhatsnewServer).CreateMastodonSource(ctx, req.(*CreateMastodonSourceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_CreateUserTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateUserTwitterSourceRequest)
+func _Whatsnew_GetMastodonSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMastodonSourceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).CreateUserTwitterSource(ctx, in)
+		return srv.(WhatsnewServer).GetMastodonSource(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/CreateUserTwitterSource",
+		FullMethod: "/whatsnew.Whatsnew/GetMastodonSource",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).CreateUserTwitterSource(ctx, req.(*CreateUserTwitterSourceRequest))
+		return srv.(WhatsnewServer).GetMastodonSource(ctx, req.(*GetMastodonSourceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_GetUserTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetUserTwitterSourceRequest)
+func _Whatsnew_UpdateMastodonSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMastodonSourceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).GetUserTwitterSource(ctx, in)
+		return srv.(WhatsnewServer).UpdateMastodonSource(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/GetUserTwitterSource",
+		FullMethod: "/whatsnew.Whatsnew/UpdateMastodonSource",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).GetUserTwitterSource(ctx, req.(*GetUserTwitterSourceRequest))
+		return srv.(WhatsnewServer).UpdateMastodonSource(ctx, req.(*UpdateMastodonSourceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_UpdateUserTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateUserTwitterSourceRequest)
+func _Whatsnew_DeleteMastodonSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMastodonSourceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).UpdateUserTwitterSource(ctx, in)
+		return srv.(WhatsnewServer).DeleteMastodonSource(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/UpdateUserTwitterSource",
+		FullMethod: "/whatsnew.Whatsnew/DeleteMastodonSource",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).UpdateUserTwitterSource(ctx, req.(*UpdateUserTwitterSourceRequest))
+		return srv.(WhatsnewServer).DeleteMastodonSource(ctx, req.(*DeleteMastodonSourceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_DeleteUserTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteUserTwitterSourceRequest)
+func _Whatsnew_GetDomainPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDomainPoliciesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).DeleteUserTwitterSource(ctx, in)
+		return srv.(WhatsnewServer).GetDomainPolicies(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/DeleteUserTwitterSource",
+		FullMethod: "/whatsnew.Whatsnew/GetDomainPolicies",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).DeleteUserTwitterSource(ctx, req.(*DeleteUserTwitterSourceRequest))
+		return srv.(WhatsnewServer).GetDomainPolicies(ctx, req.(*GetDomainPoliciesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_GetQueryTwitterSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetQueryTwitterSourcesRequest)
+func _Whatsnew_CreateDomainPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDomainPoliciesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).GetQueryTwitterSources(ctx, in)
+		return srv.(WhatsnewServer).CreateDomainPolicies(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/GetQueryTwitterSources",
+		FullMethod: "/whatsnew.Whatsnew/CreateDomainPolicies",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).GetQueryTwitterSources(ctx, req.(*GetQueryTwitterSourcesRequest))
+		return srv.(WhatsnewServer).CreateDomainPolicies(ctx, req.(*CreateDomainPoliciesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_CreateQueryTwitterSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateQueryTwitterSourcesRequest)
+func _Whatsnew_CreateDomainPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDomainPolicyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).CreateQueryTwitterSources(ctx, in)
+		return srv.(WhatsnewServer).CreateDomainPolicy(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/CreateQueryTwitterSources",
+		FullMethod: "/whatsnew.Whatsnew/CreateDomainPolicy",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).CreateQueryTwitterSources(ctx, req.(*CreateQueryTwitterSourcesRequest))
+		return srv.(WhatsnewServer).CreateDomainPolicy(ctx, req.(*CreateDomainPolicyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_CreateQueryTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateQueryTwitterSourceRequest)
+func _Whatsnew_GetDomainPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDomainPolicyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).CreateQueryTwitterSource(ctx, in)
+		return srv.(WhatsnewServer).GetDomainPolicy(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/CreateQueryTwitterSource",
+		FullMethod: "/whatsnew.Whatsnew/GetDomainPolicy",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).CreateQueryTwitterSource(ctx, req.(*CreateQueryTwitterSourceRequest))
+		return srv.(WhatsnewServer).GetDomainPolicy(ctx, req.(*GetDomainPolicyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_GetQueryTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetQueryTwitterSourceRequest)
+func _Whatsnew_UpdateDomainPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDomainPolicyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).GetQueryTwitterSource(ctx, in)
+		return srv.(WhatsnewServer).UpdateDomainPolicy(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/GetQueryTwitterSource",
+		FullMethod: "/whatsnew.Whatsnew/UpdateDomainPolicy",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).GetQueryTwitterSource(ctx, req.(*GetQueryTwitterSourceRequest))
+		return srv.(WhatsnewServer).UpdateDomainPolicy(ctx, req.(*UpdateDomainPolicyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_UpdateQueryTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateQueryTwitterSourceRequest)
+func _Whatsnew_DeleteDomainPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDomainPolicyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).UpdateQueryTwitterSource(ctx, in)
+		return srv.(WhatsnewServer).DeleteDomainPolicy(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/UpdateQueryTwitterSource",
+		FullMethod: "/whatsnew.Whatsnew/DeleteDomainPolicy",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).UpdateQueryTwitterSource(ctx, req.(*UpdateQueryTwitterSourceRequest))
+		return srv.(WhatsnewServer).DeleteDomainPolicy(ctx, req.(*DeleteDomainPolicyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Whatsnew_DeleteQueryTwitterSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteQueryTwitterSourceRequest)
+func _Whatsnew_GetScrapeFailures_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetScrapeFailuresRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WhatsnewServer).DeleteQueryTwitterSource(ctx, in)
+		return srv.(WhatsnewServer).GetScrapeFailures(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/whatsnew.Whatsnew/DeleteQueryTwitterSource",
+		FullMethod: "/whatsnew.Whatsnew/GetScrapeFailures",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WhatsnewServer).DeleteQueryTwitterSource(ctx, req.(*DeleteQueryTwitterSourceRequest))
+		return srv.(WhatsnewServer).GetScrapeFailures(ctx, req.(*GetScrapeFailuresRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -1172,6 +2313,171 @@ func _Whatsnew_DeleteInfoExtractionRule_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Whatsnew_GetWebArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetWebArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetWebArticles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetWebArticles(ctx, req.(*GetWebArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_StreamWebArticles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamWebArticlesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WhatsnewServer).StreamWebArticles(m, &whatsnewStreamWebArticlesServer{stream})
+}
+
+type Whatsnew_StreamWebArticlesServer interface {
+	Send(*StreamWebArticlesResponse) error
+	grpc.ServerStream
+}
+
+type whatsnewStreamWebArticlesServer struct {
+	grpc.ServerStream
+}
+
+func (x *whatsnewStreamWebArticlesServer) Send(m *StreamWebArticlesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Whatsnew_SearchSimilarArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchSimilarArticlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).SearchSimilarArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/SearchSimilarArticles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).SearchSimilarArticles(ctx, req.(*SearchSimilarArticlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetWebArticle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebArticleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetWebArticle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetWebArticle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetWebArticle(ctx, req.(*GetWebArticleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetTrendingStories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTrendingStoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetTrendingStories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetTrendingStories",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetTrendingStories(ctx, req.(*GetTrendingStoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_GetStory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).GetStory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/GetStory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).GetStory(ctx, req.(*GetStoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_DiscoverFeeds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverFeedsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).DiscoverFeeds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/DiscoverFeeds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).DiscoverFeeds(ctx, req.(*DiscoverFeedsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_ExportOPML_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportOPMLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).ExportOPML(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/ExportOPML",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).ExportOPML(ctx, req.(*ExportOPMLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Whatsnew_ImportOPML_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportOPMLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WhatsnewServer).ImportOPML(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/whatsnew.Whatsnew/ImportOPML",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WhatsnewServer).ImportOPML(ctx, req.(*ImportOPMLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Whatsnew_ServiceDesc is the grpc.ServiceDesc for Whatsnew service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1251,6 +2557,130 @@ var Whatsnew_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteQueryTwitterSource",
 			Handler:    _Whatsnew_DeleteQueryTwitterSource_Handler,
 		},
+		{
+			MethodName: "GetSitemaps",
+			Handler:    _Whatsnew_GetSitemaps_Handler,
+		},
+		{
+			MethodName: "CreateSitemaps",
+			Handler:    _Whatsnew_CreateSitemaps_Handler,
+		},
+		{
+			MethodName: "CreateSitemap",
+			Handler:    _Whatsnew_CreateSitemap_Handler,
+		},
+		{
+			MethodName: "GetSitemap",
+			Handler:    _Whatsnew_GetSitemap_Handler,
+		},
+		{
+			MethodName: "UpdateSitemap",
+			Handler:    _Whatsnew_UpdateSitemap_Handler,
+		},
+		{
+			MethodName: "DeleteSitemap",
+			Handler:    _Whatsnew_DeleteSitemap_Handler,
+		},
+		{
+			MethodName: "GetApiSources",
+			Handler:    _Whatsnew_GetApiSources_Handler,
+		},
+		{
+			MethodName: "CreateApiSources",
+			Handler:    _Whatsnew_CreateApiSources_Handler,
+		},
+		{
+			MethodName: "CreateApiSource",
+			Handler:    _Whatsnew_CreateApiSource_Handler,
+		},
+		{
+			MethodName: "GetApiSource",
+			Handler:    _Whatsnew_GetApiSource_Handler,
+		},
+		{
+			MethodName: "UpdateApiSource",
+			Handler:    _Whatsnew_UpdateApiSource_Handler,
+		},
+		{
+			MethodName: "DeleteApiSource",
+			Handler:    _Whatsnew_DeleteApiSource_Handler,
+		},
+		{
+			MethodName: "GetNewsletterSources",
+			Handler:    _Whatsnew_GetNewsletterSources_Handler,
+		},
+		{
+			MethodName: "CreateNewsletterSources",
+			Handler:    _Whatsnew_CreateNewsletterSources_Handler,
+		},
+		{
+			MethodName: "CreateNewsletterSource",
+			Handler:    _Whatsnew_CreateNewsletterSource_Handler,
+		},
+		{
+			MethodName: "GetNewsletterSource",
+			Handler:    _Whatsnew_GetNewsletterSource_Handler,
+		},
+		{
+			MethodName: "UpdateNewsletterSource",
+			Handler:    _Whatsnew_UpdateNewsletterSource_Handler,
+		},
+		{
+			MethodName: "DeleteNewsletterSource",
+			Handler:    _Whatsnew_DeleteNewsletterSource_Handler,
+		},
+		{
+			MethodName: "GetMastodonSources",
+			Handler:    _Whatsnew_GetMastodonSources_Handler,
+		},
+		{
+			MethodName: "CreateMastodonSources",
+			Handler:    _Whatsnew_CreateMastodonSources_Handler,
+		},
+		{
+			MethodName: "CreateMastodonSource",
+			Handler:    _Whatsnew_CreateMastodonSource_Handler,
+		},
+		{
+			MethodName: "GetMastodonSource",
+			Handler:    _Whatsnew_GetMastodonSource_Handler,
+		},
+		{
+			MethodName: "UpdateMastodonSource",
+			Handler:    _Whatsnew_UpdateMastodonSource_Handler,
+		},
+		{
+			MethodName: "DeleteMastodonSource",
+			Handler:    _Whatsnew_DeleteMastodonSource_Handler,
+		},
+		{
+			MethodName: "GetDomainPolicies",
+			Handler:    _Whatsnew_GetDomainPolicies_Handler,
+		},
+		{
+			MethodName: "CreateDomainPolicies",
+			Handler:    _Whatsnew_CreateDomainPolicies_Handler,
+		},
+		{
+			MethodName: "CreateDomainPolicy",
+			Handler:    _Whatsnew_CreateDomainPolicy_Handler,
+		},
+		{
+			MethodName: "GetDomainPolicy",
+			Handler:    _Whatsnew_GetDomainPolicy_Handler,
+		},
+		{
+			MethodName: "UpdateDomainPolicy",
+			Handler:    _Whatsnew_UpdateDomainPolicy_Handler,
+		},
+		{
+			MethodName: "DeleteDomainPolicy",
+			Handler:    _Whatsnew_DeleteDomainPolicy_Handler,
+		},
+		{
+			MethodName: "GetScrapeFailures",
+			Handler:    _Whatsnew_GetScrapeFailures_Handler,
+		},
 		{
 			MethodName: "GetZeroShotHypothesisTemplates",
 			Handler:    _Whatsnew_GetZeroShotHypothesisTemplates_Handler,
@@ -1319,7 +2749,45 @@ var Whatsnew_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteInfoExtractionRule",
 			Handler:    _Whatsnew_DeleteInfoExtractionRule_Handler,
 		},
+		{
+			MethodName: "GetWebArticles",
+			Handler:    _Whatsnew_GetWebArticles_Handler,
+		},
+		{
+			MethodName: "SearchSimilarArticles",
+			Handler:    _Whatsnew_SearchSimilarArticles_Handler,
+		},
+		{
+			MethodName: "GetWebArticle",
+			Handler:    _Whatsnew_GetWebArticle_Handler,
+		},
+		{
+			MethodName: "GetTrendingStories",
+			Handler:    _Whatsnew_GetTrendingStories_Handler,
+		},
+		{
+			MethodName: "GetStory",
+			Handler:    _Whatsnew_GetStory_Handler,
+		},
+		{
+			MethodName: "DiscoverFeeds",
+			Handler:    _Whatsnew_DiscoverFeeds_Handler,
+		},
+		{
+			MethodName: "ExportOPML",
+			Handler:    _Whatsnew_ExportOPML_Handler,
+		},
+		{
+			MethodName: "ImportOPML",
+			Handler:    _Whatsnew_ImportOPML_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamWebArticles",
+			Handler:       _Whatsnew_StreamWebArticles_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "whatsnew.proto",
 }
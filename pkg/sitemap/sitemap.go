@@ -0,0 +1,221 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sitemap parses XML sitemaps, sitemap indexes and Google News
+// sitemaps.
+package sitemap
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"golang.org/x/net/html/charset"
+	"io"
+	"strings"
+	"time"
+)
+
+// Sitemap is a parsed sitemap document. It is either a sitemap index,
+// listing other sitemaps, or a plain sitemap (URL set), listing pages.
+type Sitemap struct {
+	// Sitemaps is the list of sitemaps from a sitemap index.
+	Sitemaps []Entry
+	// URLs is the list of pages from a URL set.
+	URLs []URL
+}
+
+// IsIndex reports whether the Sitemap is a sitemap index.
+func (s *Sitemap) IsIndex() bool {
+	return s.Sitemaps != nil
+}
+
+// Entry is a sitemap listed in a sitemap index.
+type Entry struct {
+	Loc     string
+	LastMod *time.Time
+}
+
+// URL is a page listed in a sitemap.
+type URL struct {
+	Loc     string
+	LastMod *time.Time
+	// News is only present for Google News sitemaps entries.
+	News *News
+}
+
+// News provides the information of a Google News sitemap entry.
+type News struct {
+	PublicationName     string
+	PublicationLanguage string
+	PublicationDate     *time.Time
+	Title               string
+	Keywords            []string
+}
+
+// PublishedAt returns the publication date of the news, if available, or
+// the last modification time otherwise. It is nil if neither is known.
+func (u URL) PublishedAt() *time.Time {
+	if u.News != nil && u.News.PublicationDate != nil {
+		return u.News.PublicationDate
+	}
+	return u.LastMod
+}
+
+type xmlURLSet struct {
+	URLs []xmlURL `xml:"url"`
+}
+
+type xmlURL struct {
+	Loc     string   `xml:"loc"`
+	LastMod string   `xml:"lastmod"`
+	News    *xmlNews `xml:"http://www.google.com/schemas/sitemap-news/0.9 news"`
+}
+
+type xmlNews struct {
+	Publication struct {
+		Name     string `xml:"name"`
+		Language string `xml:"language"`
+	} `xml:"publication"`
+	PublicationDate string `xml:"publication_date"`
+	Title           string `xml:"title"`
+	Keywords        string `xml:"keywords"`
+}
+
+type xmlSitemapIndex struct {
+	Sitemaps []xmlEntry `xml:"sitemap"`
+}
+
+type xmlEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// Parse reads a sitemap or a sitemap index, optionally gzip-compressed.
+//
+// Invalid dates are ignored, as well as entries without a location.
+func Parse(r io.Reader) (*Sitemap, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing sitemap: %w", err)
+		}
+		defer func() { _ = gz.Close() }()
+		r = gz
+	} else {
+		r = br
+	}
+
+	dec := xml.NewDecoder(r)
+	dec.CharsetReader = charset.NewReaderLabel
+
+	root, err := findRoot(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	switch root.Name.Local {
+	case "urlset":
+		var us xmlURLSet
+		if err = dec.DecodeElement(&us, &root); err != nil {
+			return nil, fmt.Errorf("error parsing sitemap: %w", err)
+		}
+		return convertURLSet(us), nil
+	case "sitemapindex":
+		var si xmlSitemapIndex
+		if err = dec.DecodeElement(&si, &root); err != nil {
+			return nil, fmt.Errorf("error parsing sitemap index: %w", err)
+		}
+		return convertSitemapIndex(si), nil
+	default:
+		return nil, fmt.Errorf("invalid sitemap: unexpected root element %#v", root.Name.Local)
+	}
+}
+
+func findRoot(dec *xml.Decoder) (xml.StartElement, error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return xml.StartElement{}, fmt.Errorf("error parsing sitemap: %w", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			return se, nil
+		}
+	}
+}
+
+func convertURLSet(us xmlURLSet) *Sitemap {
+	urls := make([]URL, 0, len(us.URLs))
+	for _, u := range us.URLs {
+		loc := strings.TrimSpace(u.Loc)
+		if len(loc) == 0 {
+			continue
+		}
+		url := URL{
+			Loc:     loc,
+			LastMod: parseTime(u.LastMod),
+		}
+		if n := u.News; n != nil {
+			url.News = &News{
+				PublicationName:     strings.TrimSpace(n.Publication.Name),
+				PublicationLanguage: strings.TrimSpace(n.Publication.Language),
+				PublicationDate:     parseTime(n.PublicationDate),
+				Title:               strings.TrimSpace(n.Title),
+				Keywords:            splitKeywords(n.Keywords),
+			}
+		}
+		urls = append(urls, url)
+	}
+	return &Sitemap{URLs: urls}
+}
+
+func convertSitemapIndex(si xmlSitemapIndex) *Sitemap {
+	entries := make([]Entry, 0, len(si.Sitemaps))
+	for _, e := range si.Sitemaps {
+		loc := strings.TrimSpace(e.Loc)
+		if len(loc) == 0 {
+			continue
+		}
+		entries = append(entries, Entry{
+			Loc:     loc,
+			LastMod: parseTime(e.LastMod),
+		})
+	}
+	return &Sitemap{Sitemaps: entries}
+}
+
+// timeLayouts are the W3C Datetime formats allowed in sitemaps.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+func parseTime(s string) *time.Time {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+	return nil
+}
+
+func splitKeywords(s string) []string {
+	var keywords []string
+	for _, k := range strings.Split(s, ",") {
+		if k = strings.TrimSpace(k); len(k) > 0 {
+			keywords = append(keywords, k)
+		}
+	}
+	return keywords
+}
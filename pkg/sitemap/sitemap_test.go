@@ -0,0 +1,114 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("news sitemap", func(t *testing.T) {
+		t.Parallel()
+		s, err := Parse(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+        xmlns:news="http://www.google.com/schemas/sitemap-news/0.9">
+  <url>
+    <loc>https://www.example.org/business/article55.html</loc>
+    <lastmod>2021-11-20</lastmod>
+    <news:news>
+      <news:publication>
+        <news:name>The Example Times</news:name>
+        <news:language>en</news:language>
+      </news:publication>
+      <news:publication_date>2021-11-21T10:30:00+01:00</news:publication_date>
+      <news:title>Companies A, B in Merger Talks</news:title>
+      <news:keywords>business, merger, acquisition</news:keywords>
+    </news:news>
+  </url>
+  <url>
+    <loc> https://www.example.org/about.html </loc>
+    <lastmod>not a date</lastmod>
+  </url>
+  <url>
+    <loc></loc>
+  </url>
+</urlset>`))
+		require.NoError(t, err)
+		assert.False(t, s.IsIndex())
+		require.Len(t, s.URLs, 2)
+
+		u := s.URLs[0]
+		assert.Equal(t, "https://www.example.org/business/article55.html", u.Loc)
+		require.NotNil(t, u.LastMod)
+		assert.Equal(t, time.Date(2021, 11, 20, 0, 0, 0, 0, time.UTC), *u.LastMod)
+		require.NotNil(t, u.News)
+		assert.Equal(t, "The Example Times", u.News.PublicationName)
+		assert.Equal(t, "en", u.News.PublicationLanguage)
+		assert.Equal(t, "Companies A, B in Merger Talks", u.News.Title)
+		assert.Equal(t, []string{"business", "merger", "acquisition"}, u.News.Keywords)
+		require.NotNil(t, u.PublishedAt())
+		assert.Equal(t, time.Date(2021, 11, 21, 9, 30, 0, 0, time.UTC), *u.PublishedAt())
+
+		u = s.URLs[1]
+		assert.Equal(t, "https://www.example.org/about.html", u.Loc)
+		assert.Nil(t, u.LastMod)
+		assert.Nil(t, u.News)
+		assert.Nil(t, u.PublishedAt())
+	})
+
+	t.Run("sitemap index", func(t *testing.T) {
+		t.Parallel()
+		s, err := Parse(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap>
+    <loc>https://www.example.com/sitemap1.xml.gz</loc>
+    <lastmod>2021-10-01T18:23:17+00:00</lastmod>
+  </sitemap>
+  <sitemap>
+    <loc>https://www.example.com/sitemap2.xml.gz</loc>
+  </sitemap>
+</sitemapindex>`))
+		require.NoError(t, err)
+		assert.True(t, s.IsIndex())
+		assert.Empty(t, s.URLs)
+
+		lastMod := time.Date(2021, 10, 1, 18, 23, 17, 0, time.UTC)
+		assert.Equal(t, []Entry{
+			{Loc: "https://www.example.com/sitemap1.xml.gz", LastMod: &lastMod},
+			{Loc: "https://www.example.com/sitemap2.xml.gz"},
+		}, s.Sitemaps)
+	})
+
+	t.Run("gzip-compressed sitemap", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(`<urlset><url><loc>https://example.com/a</loc></url></urlset>`))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+
+		s, err := Parse(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, []URL{{Loc: "https://example.com/a"}}, s.URLs)
+	})
+
+	t.Run("invalid documents", func(t *testing.T) {
+		t.Parallel()
+		_, err := Parse(strings.NewReader(`<rss version="2.0"></rss>`))
+		assert.Error(t, err)
+		_, err = Parse(strings.NewReader(`<urlset><url>`))
+		assert.Error(t, err)
+		_, err = Parse(strings.NewReader(``))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,168 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stagenotifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4/stdlib"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"sync"
+)
+
+// subscriptionBufferSize is the capacity of each Subscription channel.
+const subscriptionBufferSize = 1000
+
+// ErrSubscriptionClosed is the error reported by a Subscription which was
+// closed by the Listener, either because its consumer was too slow, or
+// because the Listener stopped listening.
+var ErrSubscriptionClosed = errors.New("subscription closed")
+
+// A Listener receives the notifications from the database and dispatches
+// them to all current subscribers.
+type Listener struct {
+	db            *gorm.DB
+	log           zerolog.Logger
+	mu            sync.Mutex
+	subscriptions map[*Subscription]struct{}
+}
+
+// A Subscription receives the notifications dispatched by a Listener.
+type Subscription struct {
+	// C is the channel of incoming notifications. It is closed when
+	// the subscription ends.
+	C        <-chan Notification
+	c        chan Notification
+	listener *Listener
+	once     sync.Once
+}
+
+// NewListener creates a new Listener.
+func NewListener(db *gorm.DB, log zerolog.Logger) *Listener {
+	return &Listener{
+		db:            db,
+		log:           log,
+		subscriptions: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe creates a new Subscription. The caller must always call
+// Subscription.Close when the subscription is no longer needed.
+func (l *Listener) Subscribe() *Subscription {
+	c := make(chan Notification, subscriptionBufferSize)
+	sub := &Subscription{
+		C:        c,
+		c:        c,
+		listener: l,
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.subscriptions[sub] = struct{}{}
+	return sub
+}
+
+// Close ends the subscription. It is safe to call it more than once.
+func (sub *Subscription) Close() {
+	l := sub.listener
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removeSubscription(sub)
+}
+
+// removeSubscription must be called while holding the lock.
+func (l *Listener) removeSubscription(sub *Subscription) {
+	delete(l.subscriptions, sub)
+	sub.once.Do(func() { close(sub.c) })
+}
+
+// Listen listens for notifications on a dedicated database connection,
+// until the context is done or an error occurs. When it returns, all
+// current subscriptions are closed.
+func (l *Listener) Listen(ctx context.Context) error {
+	defer l.closeAllSubscriptions()
+
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return fmt.Errorf("error getting sql.DB from gorm.DB: %w", err)
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting a database connection: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			l.log.Err(err).Msg("error closing database connection")
+		}
+	}()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("unexpected database driver connection type %T", driverConn)
+		}
+		pgxConn := stdlibConn.Conn()
+
+		_, err := pgxConn.Exec(ctx, fmt.Sprintf("LISTEN %s", Channel))
+		if err != nil {
+			return fmt.Errorf("error listening on channel %#v: %w", Channel, err)
+		}
+		defer func() {
+			// The connection goes back to the pool (unless it was closed
+			// upon context cancellation), so we stop listening.
+			if pgxConn.IsClosed() {
+				return
+			}
+			if _, err := pgxConn.Exec(context.Background(), "UNLISTEN *"); err != nil {
+				l.log.Err(err).Msg("error unlistening notifications")
+			}
+		}()
+
+		for {
+			pn, err := pgxConn.WaitForNotification(ctx)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("error waiting for notification: %w", err)
+			}
+
+			var n Notification
+			err = json.Unmarshal([]byte(pn.Payload), &n)
+			if err != nil {
+				l.log.Err(err).Str("payload", pn.Payload).Msg("error decoding notification")
+				continue
+			}
+			l.dispatch(n)
+		}
+	})
+}
+
+func (l *Listener) dispatch(n Notification) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for sub := range l.subscriptions {
+		select {
+		case sub.c <- n:
+		default:
+			l.log.Warn().Msg("subscription buffer is full: closing subscription")
+			l.removeSubscription(sub)
+		}
+	}
+}
+
+func (l *Listener) closeAllSubscriptions() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for sub := range l.subscriptions {
+		l.removeSubscription(sub)
+	}
+}
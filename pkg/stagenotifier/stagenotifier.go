@@ -0,0 +1,100 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package stagenotifier allows pipeline stages to announce that a WebArticle
+// was processed, and other processes to be notified about it.
+//
+// Notifications are delivered with PostgreSQL LISTEN/NOTIFY. Since they are
+// sent from within the same transaction which persists the results of a
+// stage, a notification is only delivered once the transaction is
+// successfully committed.
+package stagenotifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Channel is the name of the PostgreSQL notification channel.
+const Channel = "whatsnew_web_article_processed"
+
+// Names of the pipeline stages which send notifications.
+const (
+	WebScraper           = "web_scraper"
+	TwitterScraper       = "twitter_scraper"
+	MastodonScraper      = "mastodon_scraper"
+	NewsletterFetcher    = "newsletter_fetcher"
+	Translator           = "translator"
+	ZeroShotClassifier   = "zero_shot_classifier"
+	TextClassifier       = "text_classifier"
+	GeoParser            = "geo_parser"
+	Vectorizer           = "vectorizer"
+	DuplicateDetector    = "duplicate_detector"
+	InformationExtractor = "information_extractor"
+)
+
+// Stages is the list of all known stages.
+var Stages = []string{
+	WebScraper,
+	TwitterScraper,
+	MastodonScraper,
+	NewsletterFetcher,
+	Translator,
+	ZeroShotClassifier,
+	TextClassifier,
+	GeoParser,
+	Vectorizer,
+	DuplicateDetector,
+	InformationExtractor,
+}
+
+// IsValidStage reports whether the given name is one of the known Stages.
+func IsValidStage(stage string) bool {
+	for _, s := range Stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is the payload of a single notification.
+type Notification struct {
+	Stage        string `json:"stage"`
+	WebArticleID uint   `json:"web_article_id"`
+}
+
+// Notify records that the WebArticle was processed by the given stage,
+// creating a models.CompletedStage (if it does not already exist), and
+// sends a notification on Channel.
+//
+// It should be invoked from the same transaction which persists the
+// results of the stage.
+func Notify(tx *gorm.DB, stage string, webArticleID uint) error {
+	cs := &models.CompletedStage{
+		WebArticleID: webArticleID,
+		Stage:        stage,
+	}
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(cs)
+	if res.Error != nil {
+		return fmt.Errorf("error creating CompletedStage: %w", res.Error)
+	}
+
+	payload, err := json.Marshal(Notification{
+		Stage:        stage,
+		WebArticleID: webArticleID,
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling notification payload: %w", err)
+	}
+
+	res = tx.Exec("SELECT pg_notify(?, ?)", Channel, string(payload))
+	if res.Error != nil {
+		return fmt.Errorf("error sending notification: %w", res.Error)
+	}
+	return nil
+}
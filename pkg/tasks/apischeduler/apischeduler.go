@@ -0,0 +1,133 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package apischeduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	faktory "github.com/contribsys/faktory/client"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"time"
+)
+
+// APIScheduler implements the mechanism for periodically fetching all
+// enabled APISources and scheduling a set of jobs for each APISource.
+type APIScheduler struct {
+	conf config.APIScheduler
+	db   *gorm.DB
+	fk   *faktory.Client
+	log  zerolog.Logger
+}
+
+// New creates a new APIScheduler.
+func New(conf config.APIScheduler, db *gorm.DB, fk *faktory.Client) *APIScheduler {
+	return &APIScheduler{
+		conf: conf,
+		db:   db,
+		fk:   fk,
+		log:  log.Logger.Level(zerolog.Level(conf.LogLevel)),
+	}
+}
+
+const batchSize = 100
+
+var errStop = errors.New("stop")
+
+// Run starts the API sources scheduling process.
+//
+// This function should ideally run forever, unless an error is encountered
+// or the context is done.
+func (as *APIScheduler) Run(ctx context.Context) (err error) {
+	as.log.Info().Msg("API sources scheduling starts")
+
+Loop:
+	for {
+		err = as.findAndScheduleSources(ctx)
+		if err != nil {
+			break
+		}
+
+		as.log.Info().Msgf("waiting %s", as.conf.TimeInterval)
+		select {
+		case <-time.After(as.conf.TimeInterval):
+		case <-ctx.Done():
+			as.log.Warn().Msg("context done")
+			break Loop
+		}
+	}
+
+	if err != nil && err != errStop {
+		as.log.Err(err).Msg("API sources scheduling ends with error")
+		return err
+	}
+
+	as.log.Info().Msg("API sources scheduling ends")
+	return nil
+}
+
+func (as *APIScheduler) findAndScheduleSources(ctx context.Context) error {
+	as.log.Info().Msg("scheduling all API sources")
+
+	query := as.db.WithContext(ctx).
+		Where("enabled = true").
+		Order("last_retrieved_at NULLS FIRST, id")
+
+	var sources []*models.APISource
+	res := query.FindInBatches(&sources, batchSize, func(_ *gorm.DB, batch int) error {
+		as.log.Debug().Msgf("batch %d", batch)
+		return as.processBatch(ctx, sources)
+	})
+	return res.Error
+}
+
+func (as *APIScheduler) processBatch(ctx context.Context, sources []*models.APISource) error {
+	for _, source := range sources {
+		if ctxIsDone(ctx) {
+			as.log.Warn().Msg("context done")
+			return errStop
+		}
+		err := as.scheduleSourceJobs(source)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (as *APIScheduler) scheduleSourceJobs(source *models.APISource) error {
+	// The Context is ignored on purpose here, so that it is more likely that
+	// the full set of jobs is scheduled for each API source, even if the
+	// context is canceled in the meanwhile.
+
+	for _, fj := range as.conf.Jobs {
+		job := faktory.NewJob(fj.JobType, source.ID)
+		job.Queue = fj.Queue
+		job.ReserveFor = fj.ReserveFor
+		job.Retry = new(int)
+		*job.Retry = fj.Retry
+
+		as.log.Trace().Interface("job", job).Msg("push job")
+
+		err := as.fk.Push(job)
+		if err != nil {
+			return fmt.Errorf("error pushing Job %+v for APISource %d: %w", fj, source.ID, err)
+		}
+	}
+	return nil
+}
+
+func ctxIsDone(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
@@ -6,6 +6,7 @@ package feedscheduler
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
@@ -14,6 +15,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -38,6 +40,10 @@ func New(conf config.FeedScheduler, db *gorm.DB, fk *faktory.Client) *FeedSchedu
 
 const batchSize = 100
 
+// minWaitTime is the minimum time the scheduler waits between two
+// consecutive scheduling runs.
+const minWaitTime = time.Second
+
 var errStop = errors.New("stop")
 
 // Run starts the feed scheduling process.
@@ -54,9 +60,15 @@ Loop:
 			break
 		}
 
-		fs.log.Info().Msgf("waiting %s", fs.conf.TimeInterval)
+		var waitTime time.Duration
+		waitTime, err = fs.timeUntilNextFeed(ctx)
+		if err != nil {
+			break
+		}
+
+		fs.log.Info().Msgf("waiting %s", waitTime)
 		select {
-		case <-time.After(fs.conf.TimeInterval):
+		case <-time.After(waitTime):
 		case <-ctx.Done():
 			fs.log.Warn().Msg("context done")
 			break Loop
@@ -73,10 +85,11 @@ Loop:
 }
 
 func (fs *FeedScheduler) findAndScheduleFeeds(ctx context.Context) error {
-	fs.log.Info().Msg("scheduling all feeds")
+	fs.log.Info().Msg("scheduling due feeds")
 
 	query := fs.db.WithContext(ctx).
 		Where("enabled = true").
+		Where("last_scheduled_at IS NULL OR ? <= ?", fs.nextSchedulingExpr(), time.Now().UTC()).
 		Order("last_retrieved_at NULLS FIRST, id")
 
 	var feeds []*models.Feed
@@ -87,6 +100,62 @@ func (fs *FeedScheduler) findAndScheduleFeeds(ctx context.Context) error {
 	return res.Error
 }
 
+// timeUntilNextFeed returns the time to wait until the next Feed is due to
+// be scheduled, never exceeding the configured time interval.
+func (fs *FeedScheduler) timeUntilNextFeed(ctx context.Context) (time.Duration, error) {
+	var next sql.NullTime
+	err := fs.db.WithContext(ctx).
+		Model(&models.Feed{}).
+		Select("MIN(?)", fs.nextSchedulingExpr()).
+		Where("enabled = true").
+		Row().Scan(&next)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching the next Feed scheduling time: %w", err)
+	}
+
+	if !next.Valid {
+		return fs.conf.TimeInterval, nil
+	}
+	d := time.Until(next.Time)
+	switch {
+	case d < minWaitTime:
+		return minWaitTime, nil
+	case d > fs.conf.TimeInterval:
+		return fs.conf.TimeInterval, nil
+	default:
+		return d, nil
+	}
+}
+
+// nextSchedulingExpr returns the SQL expression of the time when a Feed is
+// due to be scheduled again.
+//
+// The interval explicitly set on the Feed always has precedence. Otherwise,
+// if adaptive polling is enabled and the publication rate is known, the
+// interval is the expected time between two items, within the configured
+// bounds. In any other case, the global time interval is used.
+func (fs *FeedScheduler) nextSchedulingExpr() clause.Expr {
+	defaultSeconds := fs.conf.TimeInterval.Seconds()
+
+	ap := fs.conf.AdaptivePolling
+	if !ap.Enabled {
+		return gorm.Expr(
+			"last_scheduled_at + make_interval(secs => COALESCE(fetch_interval_seconds, ?))",
+			defaultSeconds,
+		)
+	}
+
+	return gorm.Expr(
+		"last_scheduled_at + make_interval(secs => COALESCE(fetch_interval_seconds, "+
+			"CASE WHEN items_per_day IS NULL THEN ? WHEN items_per_day <= 0 THEN ? "+
+			"ELSE LEAST(GREATEST(86400 / items_per_day, ?), ?) END))",
+		defaultSeconds,
+		ap.MaxInterval.Seconds(),
+		ap.MinInterval.Seconds(),
+		ap.MaxInterval.Seconds(),
+	)
+}
+
 func (fs *FeedScheduler) processBatch(ctx context.Context, feeds []*models.Feed) error {
 	for _, feed := range feeds {
 		if ctxIsDone(ctx) {
@@ -106,6 +175,8 @@ func (fs *FeedScheduler) scheduleFeedJobs(feed *models.Feed) error {
 	// the full set of jobs is scheduled for each feed, even if the context
 	// is canceled in the meanwhile.
 
+	scheduledAt := time.Now().UTC()
+
 	for _, fj := range fs.conf.Jobs {
 		job := faktory.NewJob(fj.JobType, feed.ID)
 		job.Queue = fj.Queue
@@ -120,6 +191,13 @@ func (fs *FeedScheduler) scheduleFeedJobs(feed *models.Feed) error {
 			return fmt.Errorf("error pushing Job %+v for feed %d: %w", fj, feed.ID, err)
 		}
 	}
+
+	// The scheduling time is only saved once all jobs are pushed, so that a
+	// failed push leaves the feed due for scheduling.
+	res := fs.db.Model(feed).UpdateColumn("last_scheduled_at", scheduledAt)
+	if res.Error != nil {
+		return fmt.Errorf("error updating scheduling time of feed %d: %w", feed.ID, res.Error)
+	}
 	return nil
 }
 
@@ -8,13 +8,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"github.com/SpecializedGeneralist/gdelt"
 	"github.com/SpecializedGeneralist/gdelt/events"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/gdeltdata"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/sets"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/tasks/gdeltupdates"
 	faktory "github.com/contribsys/faktory/client"
 	"github.com/jackc/pgtype"
 	"github.com/rs/zerolog"
@@ -26,21 +27,35 @@ import (
 // GDELTFetcher implements the mechanism for periodically fetching GDELT
 // Events, collecting each event's first news report URL,
 // enriched with essential Event metadata.
+//
+// Each processed GDELT update is recorded as a models.GDELTUpdate, so that
+// the updates missed while the fetcher was not running can be processed
+// later.
 type GDELTFetcher struct {
-	conf config.GDELTFetcher
-	db   *gorm.DB
-	fk   *faktory.Client
-	log  zerolog.Logger
+	conf    config.GDELTFetcher
+	log     zerolog.Logger
+	client  *gdeltdata.Client
+	updates *gdeltupdates.Processor
 }
 
 // New creates a new GDELTFetcher.
 func New(conf config.GDELTFetcher, db *gorm.DB, fk *faktory.Client) *GDELTFetcher {
-	return &GDELTFetcher{
-		conf: conf,
-		db:   db,
-		fk:   fk,
-		log:  log.Logger.Level(zerolog.Level(conf.LogLevel)),
+	gf := &GDELTFetcher{
+		conf:   conf,
+		log:    log.Logger.Level(zerolog.Level(conf.LogLevel)),
+		client: gdeltdata.NewClient(gdeltdata.DefaultBaseURL, conf.RequestTimeout),
+	}
+	gf.updates = &gdeltupdates.Processor{
+		Name:       "GDELT",
+		FileType:   gdeltdata.Export,
+		MaxCatchUp: conf.MaxCatchUp,
+		Client:     gf.client,
+		DB:         db,
+		FK:         fk,
+		Log:        gf.log,
+		Read:       gf.readEvents,
 	}
+	return gf
 }
 
 // Run starts the GDELT fetching process.
@@ -53,7 +68,7 @@ func (gf *GDELTFetcher) Run(ctx context.Context) (err error) {
 Loop:
 	for {
 		gf.log.Info().Msg("fetching and processing events")
-		err = gf.fetchAndProcessEvents(ctx)
+		err = gf.updates.ProcessLatest(ctx)
 		if err != nil {
 			break
 		}
@@ -76,18 +91,20 @@ Loop:
 	return nil
 }
 
-func (gf *GDELTFetcher) fetchAndProcessEvents(ctx context.Context) error {
-	gf.log.Debug().Msg("getting latest events")
-	evs, err := gdelt.GetLatestEvents()
+// Backfill processes the GDELT updates published from "from" to "to",
+// both included. The updates which were already processed are skipped.
+func (gf *GDELTFetcher) Backfill(ctx context.Context, from, to time.Time) error {
+	return gf.updates.Backfill(ctx, from, to)
+}
+
+// readEvents downloads the events of a GDELT update.
+func (gf *GDELTFetcher) readEvents(ctx context.Context, u gdeltdata.Update) (int, gdeltupdates.ProcessRecords, error) {
+	evs, err := gf.client.Events(ctx, u)
 	if err != nil {
-		return fmt.Errorf("error fetching latest GDELT events: %w", err)
+		return 0, nil, err
 	}
 
-	js := jobscheduler.New()
-
-	gf.log.Debug().Msg("processing all events")
-
-	err = gf.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	processEvents := func(tx *gorm.DB, js *jobscheduler.JobScheduler) error {
 		visitedURLs := sets.NewStringSetWithSize(len(evs))
 
 		for _, ev := range evs {
@@ -96,18 +113,14 @@ func (gf *GDELTFetcher) fetchAndProcessEvents(ctx context.Context) error {
 			}
 			visitedURLs.Add(ev.SourceURL)
 
-			err = gf.processEvent(tx, ev, js)
+			err := gf.processEvent(tx, ev, js)
 			if err != nil {
 				return err
 			}
 		}
-		return js.CreatePendingJobs(tx)
-	})
-	if err != nil {
-		return err
+		return nil
 	}
-
-	return js.PushJobsWithClientAndDeletePendingJobs(gf.fk, gf.db)
+	return len(evs), processEvents, nil
 }
 
 func (gf *GDELTFetcher) processEvent(tx *gorm.DB, ev *events.Event, js *jobscheduler.JobScheduler) error {
@@ -0,0 +1,62 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdeltgkgfetcher
+
+import (
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/gdeltdata"
+	"strings"
+)
+
+// Filter selects the GKG records to process, by themes and tone.
+type Filter struct {
+	themeWhitelist []string
+	themeBlacklist []string
+	minTone        float64
+	maxTone        float64
+}
+
+// NewFilter creates a new Filter from the fetcher configuration.
+func NewFilter(conf config.GDELTGKGFetcher) Filter {
+	return Filter{
+		themeWhitelist: conf.ThemeWhitelist,
+		themeBlacklist: conf.ThemeBlacklist,
+		minTone:        conf.MinTone,
+		maxTone:        conf.MaxTone,
+	}
+}
+
+// Reject returns the reason why the record must be skipped, or an empty
+// string if the record is allowed.
+func (f Filter) Reject(r *gdeltdata.GKGRecord) string {
+	if r.Tone.Tone < f.minTone || r.Tone.Tone > f.maxTone {
+		return fmt.Sprintf("tone %g out of range", r.Tone.Tone)
+	}
+	if len(f.themeWhitelist) > 0 && !anyThemeMatches(r.Themes, f.themeWhitelist) {
+		return "no whitelisted themes"
+	}
+	if len(f.themeBlacklist) > 0 && anyThemeMatches(r.Themes, f.themeBlacklist) {
+		return "blacklisted theme"
+	}
+	return ""
+}
+
+// anyThemeMatches reports whether any theme matches any of the patterns,
+// which are either exact themes or prefixes followed by "*".
+func anyThemeMatches(themes, patterns []string) bool {
+	for _, theme := range themes {
+		for _, p := range patterns {
+			if prefix := strings.TrimSuffix(p, "*"); prefix != p {
+				if strings.HasPrefix(theme, prefix) {
+					return true
+				}
+			} else if theme == p {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdeltgkgfetcher
+
+import (
+	"context"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/gdeltdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFilter_Reject(t *testing.T) {
+	t.Parallel()
+
+	record := &gdeltdata.GKGRecord{
+		Themes: []string{"ELECTION", "WB_696_PUBLIC_SECTOR_MANAGEMENT"},
+		Tone:   gdeltdata.GKGTone{Tone: -2.5},
+	}
+
+	testCases := []struct {
+		name   string
+		conf   config.GDELTGKGFetcher
+		reject bool
+	}{
+		{"no filters", config.GDELTGKGFetcher{MinTone: -100, MaxTone: 100}, false},
+		{"tone below minimum", config.GDELTGKGFetcher{MinTone: -2, MaxTone: 100}, true},
+		{"tone above maximum", config.GDELTGKGFetcher{MinTone: -100, MaxTone: -3}, true},
+		{"whitelisted theme", config.GDELTGKGFetcher{ThemeWhitelist: []string{"PROTEST", "ELECTION"}, MinTone: -100, MaxTone: 100}, false},
+		{"whitelisted prefix", config.GDELTGKGFetcher{ThemeWhitelist: []string{"WB_*"}, MinTone: -100, MaxTone: 100}, false},
+		{"no whitelisted themes", config.GDELTGKGFetcher{ThemeWhitelist: []string{"PROTEST", "ELECT"}, MinTone: -100, MaxTone: 100}, true},
+		{"blacklisted theme", config.GDELTGKGFetcher{ThemeBlacklist: []string{"ELECTION"}, MinTone: -100, MaxTone: 100}, true},
+		{"blacklisted prefix", config.GDELTGKGFetcher{ThemeBlacklist: []string{"WB_696_*"}, MinTone: -100, MaxTone: 100}, true},
+		{"no blacklisted themes", config.GDELTGKGFetcher{ThemeBlacklist: []string{"TAX_*"}, MinTone: -100, MaxTone: 100}, false},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			reason := NewFilter(tc.conf).Reject(record)
+			if tc.reject {
+				assert.NotEmpty(t, reason)
+			} else {
+				assert.Empty(t, reason)
+			}
+		})
+	}
+}
+
+func TestFilter_LocalSampleFile(t *testing.T) {
+	t.Parallel()
+
+	client := gdeltdata.NewClient(filepath.Join("..", "..", "gdeltdata", "testdata"), time.Second)
+	u, err := client.LatestUpdate(context.Background(), gdeltdata.GKG)
+	require.NoError(t, err)
+	records, err := client.GKGRecords(context.Background(), u, nil)
+	require.NoError(t, err)
+
+	filter := NewFilter(config.GDELTGKGFetcher{ThemeWhitelist: []string{"ELECTION", "NATURAL_*"}, MinTone: -5, MaxTone: 100})
+	var allowed []string
+	for _, r := range records {
+		if len(filter.Reject(r)) == 0 {
+			allowed = append(allowed, r.RecordID)
+		}
+	}
+	// The second record is too negative, the third one has other themes.
+	assert.Equal(t, []string{"20211120101500-0"}, allowed)
+}
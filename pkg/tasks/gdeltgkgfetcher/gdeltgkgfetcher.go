@@ -0,0 +1,262 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdeltgkgfetcher
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/gdeltdata"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/sets"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/tasks/gdeltupdates"
+	faktory "github.com/contribsys/faktory/client"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"time"
+)
+
+// GDELTGKGFetcher implements the mechanism for periodically fetching the
+// GDELT Global Knowledge Graph (GKG), collecting the URL of each source
+// document, enriched with its themes, persons, organizations, locations
+// and tone.
+//
+// Just like gdeltfetcher.GDELTFetcher, each processed GDELT update is
+// recorded as a models.GDELTUpdate.
+type GDELTGKGFetcher struct {
+	conf    config.GDELTGKGFetcher
+	log     zerolog.Logger
+	client  *gdeltdata.Client
+	filter  Filter
+	updates *gdeltupdates.Processor
+}
+
+// New creates a new GDELTGKGFetcher.
+func New(conf config.GDELTGKGFetcher, db *gorm.DB, fk *faktory.Client) *GDELTGKGFetcher {
+	gf := &GDELTGKGFetcher{
+		conf:   conf,
+		log:    log.Logger.Level(zerolog.Level(conf.LogLevel)),
+		client: gdeltdata.NewClient(conf.BaseURL, conf.RequestTimeout),
+		filter: NewFilter(conf),
+	}
+	gf.updates = &gdeltupdates.Processor{
+		Name:       "GDELT GKG",
+		FileType:   gdeltdata.GKG,
+		MaxCatchUp: conf.MaxCatchUp,
+		Client:     gf.client,
+		DB:         db,
+		FK:         fk,
+		Log:        gf.log,
+		Read:       gf.readRecords,
+	}
+	return gf
+}
+
+// Run starts the GKG fetching process.
+//
+// This function should ideally run forever, unless an error is encountered
+// or the context is done.
+func (gf *GDELTGKGFetcher) Run(ctx context.Context) (err error) {
+	gf.log.Info().Msg("GDELT GKG fetching starts")
+
+Loop:
+	for {
+		gf.log.Info().Msg("fetching and processing GKG records")
+		err = gf.updates.ProcessLatest(ctx)
+		if err != nil {
+			break
+		}
+
+		gf.log.Info().Msgf("waiting %s", gf.conf.TimeInterval)
+		select {
+		case <-time.After(gf.conf.TimeInterval):
+		case <-ctx.Done():
+			gf.log.Warn().Msg("context done")
+			break Loop
+		}
+	}
+
+	if err != nil {
+		gf.log.Err(err).Msg("GDELT GKG fetching ends with error")
+		return err
+	}
+
+	gf.log.Info().Msg("GDELT GKG fetching ends")
+	return nil
+}
+
+// Backfill processes the GKG updates published from "from" to "to",
+// both included. The updates which were already processed are skipped.
+func (gf *GDELTGKGFetcher) Backfill(ctx context.Context, from, to time.Time) error {
+	return gf.updates.Backfill(ctx, from, to)
+}
+
+// readRecords downloads the records of a GKG update. The malformed records
+// are logged and skipped.
+func (gf *GDELTGKGFetcher) readRecords(ctx context.Context, u gdeltdata.Update) (int, gdeltupdates.ProcessRecords, error) {
+	logger := gf.log.With().Time("Update", u.Time).Logger()
+
+	records, err := gf.client.GKGRecords(ctx, u, func(e *gdeltdata.InvalidGKGRecordError) {
+		logger.Warn().Err(e).Msg("skipping invalid GKG record")
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	processRecords := func(tx *gorm.DB, js *jobscheduler.JobScheduler) error {
+		visitedURLs := sets.NewStringSetWithSize(len(records))
+
+		for _, r := range records {
+			if visitedURLs.Has(r.DocumentIdentifier) {
+				continue
+			}
+			visitedURLs.Add(r.DocumentIdentifier)
+
+			err := gf.processRecord(tx, r, js)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return len(records), processRecords, nil
+}
+
+func (gf *GDELTGKGFetcher) processRecord(tx *gorm.DB, r *gdeltdata.GKGRecord, js *jobscheduler.JobScheduler) error {
+	logger := gf.log.With().Str("GKGRecordID", r.RecordID).Logger()
+
+	if r.SourceCollection != gdeltdata.GKGWebSource || len(r.DocumentIdentifier) == 0 {
+		logger.Debug().Msg("not a web document: skipping record")
+		return nil
+	}
+
+	if reason := gf.filter.Reject(r); len(reason) > 0 {
+		logger.Debug().Msgf("%s: skipping record", reason)
+		return nil
+	}
+
+	webResource, err := findWebResource(tx, r.DocumentIdentifier)
+	if err != nil {
+		return err
+	}
+
+	gkgRecord, err := newGDELTGKGRecord(r)
+	if err != nil {
+		logger.Err(err).Msg("error making new GDELT GKG record")
+		return nil
+	}
+
+	if webResource != nil {
+		logger = logger.With().Uint("WebResource", webResource.ID).Logger()
+
+		logger.Debug().Msg("WebResource already exists")
+
+		if webResource.GDELTGKGRecord != nil {
+			logger.Debug().Uint("GDELTGKGRecord", webResource.GDELTGKGRecord.ID).Msg("a GDELT GKG record already exists")
+			return nil
+		}
+
+		logger.Debug().Msg("creating new GDELTGKGRecord")
+
+		gkgRecord.WebResourceID = webResource.ID
+		return createGDELTGKGRecord(tx, logger, gkgRecord)
+	}
+
+	logger.Debug().Msg("creating new WebResource and GDELTGKGRecord")
+
+	webResource = &models.WebResource{
+		URL:            r.DocumentIdentifier,
+		GDELTGKGRecord: gkgRecord,
+	}
+
+	res := tx.Create(webResource)
+	if database.IsUniqueViolationError(res.Error) {
+		logger.Warn().Err(res.Error).Msg("WebResource and GDELTGKGRecord creation constraint violation")
+		return nil
+	}
+	if res.Error != nil {
+		return fmt.Errorf("error creating WebResource: %w", res.Error)
+	}
+	return js.AddJobs(gf.conf.NewWebResourceJobs, webResource.ID)
+}
+
+func createGDELTGKGRecord(tx *gorm.DB, logger zerolog.Logger, r *models.GDELTGKGRecord) error {
+	res := tx.Create(r)
+	if database.IsUniqueViolationError(res.Error) {
+		logger.Warn().Err(res.Error).Msg("GDELTGKGRecord creation constraint violation")
+		return nil
+	}
+	if res.Error != nil {
+		return fmt.Errorf("error creating GDELTGKGRecord: %w", res.Error)
+	}
+	return nil
+}
+
+func findWebResource(tx *gorm.DB, url string) (*models.WebResource, error) {
+	var webResource *models.WebResource
+	result := tx.Joins("GDELTGKGRecord").Limit(1).Find(&webResource, "url = ?", url)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error fetching WebResource by URL %#v: %w", url, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return webResource, nil
+}
+
+func newGDELTGKGRecord(r *gdeltdata.GKGRecord) (*models.GDELTGKGRecord, error) {
+	locations := r.Locations
+	if locations == nil {
+		locations = make([]gdeltdata.GKGLocation, 0)
+	}
+	locationsJSON, err := json.Marshal(locations)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling locations: %w", err)
+	}
+
+	gkgRecord := &models.GDELTGKGRecord{
+		GKGRecordID:      r.RecordID,
+		Date:             r.Date,
+		SourceCommonName: makeNullString(r.SourceCommonName),
+		Locations:        locationsJSON,
+		Tone:             r.Tone.Tone,
+		PositiveScore:    r.Tone.Positive,
+		NegativeScore:    r.Tone.Negative,
+		Polarity:         r.Tone.Polarity,
+		WordCount:        r.Tone.WordCount,
+		SharingImage:     makeNullString(r.SharingImage),
+	}
+	if err = gkgRecord.Themes.Set(makeStringSlice(r.Themes)); err != nil {
+		return nil, fmt.Errorf("error setting Themes: %w", err)
+	}
+	if err = gkgRecord.Persons.Set(makeStringSlice(r.Persons)); err != nil {
+		return nil, fmt.Errorf("error setting Persons: %w", err)
+	}
+	if err = gkgRecord.Organizations.Set(makeStringSlice(r.Organizations)); err != nil {
+		return nil, fmt.Errorf("error setting Organizations: %w", err)
+	}
+	return gkgRecord, nil
+}
+
+// makeStringSlice returns a non-nil slice, so that an empty array, rather
+// than NULL, is stored.
+func makeStringSlice(values []string) []string {
+	if values == nil {
+		return make([]string, 0)
+	}
+	return values
+}
+
+func makeNullString(s string) sql.NullString {
+	if len(s) == 0 {
+		return sql.NullString{Valid: false, String: ""}
+	}
+	return sql.NullString{Valid: true, String: s}
+}
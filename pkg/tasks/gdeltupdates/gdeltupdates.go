@@ -0,0 +1,220 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gdeltupdates implements the processing of the GDELT updates,
+// shared by the tasks reading the different GDELT data files.
+package gdeltupdates
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/gdeltdata"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	faktory "github.com/contribsys/faktory/client"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Processor processes the GDELT updates of a data file type. Each processed
+// update is recorded as a models.GDELTUpdate, so that the updates missed
+// while the task was not running can be processed later.
+type Processor struct {
+	// Name identifies the data in log messages (e.g. "GDELT").
+	Name     string
+	FileType gdeltdata.FileType
+	// MaxCatchUp limits how far back in time the missed updates are
+	// processed. Zero means no limits.
+	MaxCatchUp time.Duration
+	Client     *gdeltdata.Client
+	DB         *gorm.DB
+	FK         *faktory.Client
+	Log        zerolog.Logger
+	Read       Read
+}
+
+// Read downloads the data file of an update and reads its records. It
+// returns the number of records read, and a function which processes them.
+type Read func(ctx context.Context, u gdeltdata.Update) (int, ProcessRecords, error)
+
+// ProcessRecords processes the records of a data file, within the same
+// transaction which records the update. The jobs to schedule for new
+// models are added to js.
+type ProcessRecords func(tx *gorm.DB, js *jobscheduler.JobScheduler) error
+
+// ProcessLatest processes the latest update, preceded by the ones missed
+// since the last processed update, if any.
+func (p *Processor) ProcessLatest(ctx context.Context) error {
+	p.Log.Debug().Msg("getting latest update")
+	latest, err := p.Client.LatestUpdate(ctx, p.FileType)
+	if err != nil {
+		return fmt.Errorf("error fetching latest %s update: %w", p.Name, err)
+	}
+
+	last, err := p.lastProcessedUpdateTime(p.DB.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	for _, t := range p.pendingUpdateTimes(last, latest.Time) {
+		if ctxIsDone(ctx) {
+			return nil
+		}
+		u := latest
+		if !t.Equal(latest.Time) {
+			u = p.Client.Update(t, p.FileType)
+		}
+		err = p.processUpdate(ctx, u)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Backfill processes the updates published from "from" to "to", both
+// included. The updates which were already processed are skipped.
+func (p *Processor) Backfill(ctx context.Context, from, to time.Time) error {
+	times := gdeltdata.Range(from, to)
+	p.Log.Info().Msgf("%s backfill of %d updates starts", p.Name, len(times))
+
+	for _, t := range times {
+		if ctxIsDone(ctx) {
+			p.Log.Warn().Msg("context done")
+			return ctx.Err()
+		}
+		err := p.processUpdate(ctx, p.Client.Update(t, p.FileType))
+		if err != nil {
+			return err
+		}
+	}
+
+	p.Log.Info().Msgf("%s backfill ends", p.Name)
+	return nil
+}
+
+// pendingUpdateTimes returns the times of the updates following the last
+// processed one, up to the latest one, within the MaxCatchUp limit.
+func (p *Processor) pendingUpdateTimes(last sql.NullTime, latest time.Time) []time.Time {
+	if !last.Valid {
+		return []time.Time{latest}
+	}
+	from := last.Time.Add(gdeltdata.UpdateInterval)
+	if p.MaxCatchUp > 0 {
+		if limit := latest.Add(-p.MaxCatchUp); from.Before(limit) {
+			p.Log.Warn().Msgf("skipping %s updates from %s to %s: beyond the catch-up limit",
+				p.Name, from.Format(time.RFC3339), limit.Format(time.RFC3339))
+			from = limit
+		}
+	}
+	times := gdeltdata.Range(from, latest)
+	if len(times) > 1 {
+		p.Log.Info().Msgf("catching up %d %s updates", len(times)-1, p.Name)
+	}
+	return times
+}
+
+func (p *Processor) processUpdate(ctx context.Context, u gdeltdata.Update) error {
+	logger := p.Log.With().Time("Update", u.Time).Logger()
+
+	processed, err := p.updateIsProcessed(p.DB.WithContext(ctx), u.Time)
+	if err != nil {
+		return err
+	}
+	if processed {
+		logger.Debug().Msgf("%s update already processed", p.Name)
+		return nil
+	}
+
+	logger.Debug().Msg("reading data file")
+	count, processRecords, err := p.Read(ctx, u)
+	if errors.Is(err, gdeltdata.ErrNotFound) {
+		logger.Warn().Err(err).Msgf("%s update not available", p.Name)
+		return nil
+	}
+	if errors.Is(err, gdeltdata.ErrInvalidFile) {
+		// Retrying would fail again, blocking all the following updates.
+		logger.Error().Err(err).Msgf("invalid %s update: skipping it", p.Name)
+		return p.recordFailedUpdate(p.DB.WithContext(ctx), u.Time, err)
+	}
+	if err != nil {
+		return fmt.Errorf("error reading %s data file: %w", p.Name, err)
+	}
+
+	js := jobscheduler.New()
+
+	logger.Debug().Msg("processing all records")
+
+	err = p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := processRecords(tx, js)
+		if err != nil {
+			return err
+		}
+
+		res := tx.Create(&models.GDELTUpdate{
+			Type:         string(p.FileType),
+			Time:         u.Time,
+			RecordsCount: count,
+		})
+		if res.Error != nil {
+			return fmt.Errorf("error creating GDELTUpdate: %w", res.Error)
+		}
+
+		return js.CreatePendingJobs(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	return js.PushJobsWithClientAndDeletePendingJobs(p.FK, p.DB)
+}
+
+// recordFailedUpdate records an invalid update, so that it is not processed
+// again.
+func (p *Processor) recordFailedUpdate(tx *gorm.DB, t time.Time, failure error) error {
+	res := tx.Create(&models.GDELTUpdate{
+		Type:    string(p.FileType),
+		Time:    t,
+		Failure: sql.NullString{String: failure.Error(), Valid: true},
+	})
+	if res.Error != nil {
+		return fmt.Errorf("error creating failed GDELTUpdate: %w", res.Error)
+	}
+	return nil
+}
+
+func (p *Processor) lastProcessedUpdateTime(tx *gorm.DB) (sql.NullTime, error) {
+	var t sql.NullTime
+	res := tx.Model(&models.GDELTUpdate{}).
+		Where("type = ?", string(p.FileType)).
+		Select("MAX(time)").
+		Scan(&t)
+	if res.Error != nil {
+		return sql.NullTime{}, fmt.Errorf("error fetching last GDELTUpdate time: %w", res.Error)
+	}
+	return t, nil
+}
+
+func (p *Processor) updateIsProcessed(tx *gorm.DB, t time.Time) (bool, error) {
+	var count int64
+	res := tx.Model(&models.GDELTUpdate{}).
+		Where("type = ? AND time = ?", string(p.FileType), t).
+		Count(&count)
+	if res.Error != nil {
+		return false, fmt.Errorf("error fetching GDELTUpdate: %w", res.Error)
+	}
+	return count > 0, nil
+}
+
+func ctxIsDone(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
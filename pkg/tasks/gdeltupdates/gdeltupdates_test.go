@@ -0,0 +1,43 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gdeltupdates
+
+import (
+	"database/sql"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestProcessor_pendingUpdateTimes(t *testing.T) {
+	t.Parallel()
+
+	latest := time.Date(2021, 11, 20, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return latest.Add(d) }
+
+	testCases := []struct {
+		name       string
+		maxCatchUp time.Duration
+		last       sql.NullTime
+		want       []time.Time
+	}{
+		{"nothing processed", 0, sql.NullTime{}, []time.Time{latest}},
+		{"latest processed", 0, sql.NullTime{Time: latest, Valid: true}, []time.Time{}},
+		{"missed updates", 0, sql.NullTime{Time: at(-45 * time.Minute), Valid: true},
+			[]time.Time{at(-30 * time.Minute), at(-15 * time.Minute), latest}},
+		{"catch-up limit", 15 * time.Minute, sql.NullTime{Time: at(-45 * time.Minute), Valid: true},
+			[]time.Time{at(-15 * time.Minute), latest}},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			p := &Processor{Name: "GDELT", MaxCatchUp: tc.maxCatchUp, Log: zerolog.Nop()}
+			got := p.pendingUpdateTimes(tc.last, latest)
+			assert.ElementsMatch(t, tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mastodonscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	faktory "github.com/contribsys/faktory/client"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"time"
+)
+
+// MastodonScheduler implements the mechanism for periodically fetching all
+// enabled MastodonSources and scheduling a set of jobs for each MastodonSource.
+type MastodonScheduler struct {
+	conf config.MastodonScheduler
+	db   *gorm.DB
+	fk   *faktory.Client
+	log  zerolog.Logger
+}
+
+// New creates a new MastodonScheduler.
+func New(conf config.MastodonScheduler, db *gorm.DB, fk *faktory.Client) *MastodonScheduler {
+	return &MastodonScheduler{
+		conf: conf,
+		db:   db,
+		fk:   fk,
+		log:  log.Logger.Level(zerolog.Level(conf.LogLevel)),
+	}
+}
+
+const batchSize = 100
+
+var errStop = errors.New("stop")
+
+// Run starts the mastodon-sources scheduling process.
+//
+// This function should ideally run forever, unless an error is encountered
+// or the context is done.
+func (ms *MastodonScheduler) Run(ctx context.Context) (err error) {
+	ms.log.Info().Msg("mastodon-sources scheduling starts")
+
+Loop:
+	for {
+		err = ms.findAndScheduleSources(ctx)
+		if err != nil {
+			break
+		}
+
+		ms.log.Info().Msgf("waiting %s", ms.conf.TimeInterval)
+		select {
+		case <-time.After(ms.conf.TimeInterval):
+		case <-ctx.Done():
+			ms.log.Warn().Msg("context done")
+			break Loop
+		}
+	}
+
+	if err != nil && err != errStop {
+		ms.log.Err(err).Msg("mastodon-sources scheduling ends with error")
+		return err
+	}
+
+	ms.log.Info().Msg("mastodon-sources scheduling ends")
+	return nil
+}
+
+func (ms *MastodonScheduler) findAndScheduleSources(ctx context.Context) error {
+	ms.log.Info().Msg("scheduling all mastodon-sources")
+
+	query := ms.db.WithContext(ctx).
+		Where("enabled = true").
+		Order("last_retrieved_at NULLS FIRST, id")
+
+	var sources []*models.MastodonSource
+	res := query.FindInBatches(&sources, batchSize, func(_ *gorm.DB, batch int) error {
+		ms.log.Debug().Msgf("batch %d", batch)
+		return ms.processBatch(ctx, sources)
+	})
+	return res.Error
+}
+
+func (ms *MastodonScheduler) processBatch(ctx context.Context, sources []*models.MastodonSource) error {
+	for _, source := range sources {
+		if ctxIsDone(ctx) {
+			ms.log.Warn().Msg("context done")
+			return errStop
+		}
+		err := ms.scheduleSourceJobs(source)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ms *MastodonScheduler) scheduleSourceJobs(source *models.MastodonSource) error {
+	// The Context is ignored on purpose here, so that it is more likely that
+	// the full set of jobs is scheduled for each mastodon-source, even if the
+	// context is canceled in the meanwhile.
+
+	for _, fj := range ms.conf.Jobs {
+		job := faktory.NewJob(fj.JobType, source.ID)
+		job.Queue = fj.Queue
+		job.ReserveFor = fj.ReserveFor
+		job.Retry = new(int)
+		*job.Retry = fj.Retry
+
+		ms.log.Trace().Interface("job", job).Msg("push job")
+
+		err := ms.fk.Push(job)
+		if err != nil {
+			return fmt.Errorf("error pushing Job %+v for MastodonSource %d: %w", fj, source.ID, err)
+		}
+	}
+	return nil
+}
+
+func ctxIsDone(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package newsletterscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	faktory "github.com/contribsys/faktory/client"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"time"
+)
+
+// NewsletterScheduler implements the mechanism for periodically fetching all
+// enabled NewsletterSources and scheduling a set of jobs for each
+// NewsletterSource.
+type NewsletterScheduler struct {
+	conf config.NewsletterScheduler
+	db   *gorm.DB
+	fk   *faktory.Client
+	log  zerolog.Logger
+}
+
+// New creates a new NewsletterScheduler.
+func New(conf config.NewsletterScheduler, db *gorm.DB, fk *faktory.Client) *NewsletterScheduler {
+	return &NewsletterScheduler{
+		conf: conf,
+		db:   db,
+		fk:   fk,
+		log:  log.Logger.Level(zerolog.Level(conf.LogLevel)),
+	}
+}
+
+const batchSize = 100
+
+var errStop = errors.New("stop")
+
+// Run starts the newsletter sources scheduling process.
+//
+// This function should ideally run forever, unless an error is encountered
+// or the context is done.
+func (ns *NewsletterScheduler) Run(ctx context.Context) (err error) {
+	ns.log.Info().Msg("newsletter sources scheduling starts")
+
+Loop:
+	for {
+		err = ns.findAndScheduleSources(ctx)
+		if err != nil {
+			break
+		}
+
+		ns.log.Info().Msgf("waiting %s", ns.conf.TimeInterval)
+		select {
+		case <-time.After(ns.conf.TimeInterval):
+		case <-ctx.Done():
+			ns.log.Warn().Msg("context done")
+			break Loop
+		}
+	}
+
+	if err != nil && err != errStop {
+		ns.log.Err(err).Msg("newsletter sources scheduling ends with error")
+		return err
+	}
+
+	ns.log.Info().Msg("newsletter sources scheduling ends")
+	return nil
+}
+
+func (ns *NewsletterScheduler) findAndScheduleSources(ctx context.Context) error {
+	ns.log.Info().Msg("scheduling all newsletter sources")
+
+	query := ns.db.WithContext(ctx).
+		Where("enabled = true").
+		Order("last_retrieved_at NULLS FIRST, id")
+
+	var sources []*models.NewsletterSource
+	res := query.FindInBatches(&sources, batchSize, func(_ *gorm.DB, batch int) error {
+		ns.log.Debug().Msgf("batch %d", batch)
+		return ns.processBatch(ctx, sources)
+	})
+	return res.Error
+}
+
+func (ns *NewsletterScheduler) processBatch(ctx context.Context, sources []*models.NewsletterSource) error {
+	for _, source := range sources {
+		if ctxIsDone(ctx) {
+			ns.log.Warn().Msg("context done")
+			return errStop
+		}
+		err := ns.scheduleSourceJobs(source)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ns *NewsletterScheduler) scheduleSourceJobs(source *models.NewsletterSource) error {
+	// The Context is ignored on purpose here, so that it is more likely that
+	// the full set of jobs is scheduled for each newsletter source, even if
+	// the context is canceled in the meanwhile.
+
+	for _, fj := range ns.conf.Jobs {
+		job := faktory.NewJob(fj.JobType, source.ID)
+		job.Queue = fj.Queue
+		job.ReserveFor = fj.ReserveFor
+		job.Retry = new(int)
+		*job.Retry = fj.Retry
+
+		ns.log.Trace().Interface("job", job).Msg("push job")
+
+		err := ns.fk.Push(job)
+		if err != nil {
+			return fmt.Errorf("error pushing Job %+v for NewsletterSource %d: %w", fj, source.ID, err)
+		}
+	}
+	return nil
+}
+
+func ctxIsDone(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
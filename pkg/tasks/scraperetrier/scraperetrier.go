@@ -0,0 +1,197 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scraperetrier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	faktory "github.com/contribsys/faktory/client"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// ScrapeRetrier implements the mechanism for periodically rescheduling the
+// WebResources whose scraping failed for a transient reason, with an
+// exponential backoff.
+type ScrapeRetrier struct {
+	conf config.ScrapeRetrier
+	db   *gorm.DB
+	fk   *faktory.Client
+	log  zerolog.Logger
+}
+
+// New creates a new ScrapeRetrier.
+func New(conf config.ScrapeRetrier, db *gorm.DB, fk *faktory.Client) *ScrapeRetrier {
+	return &ScrapeRetrier{
+		conf: conf,
+		db:   db,
+		fk:   fk,
+		log:  log.Logger.Level(zerolog.Level(conf.LogLevel)),
+	}
+}
+
+const batchSize = 100
+
+var errStop = errors.New("stop")
+
+// Run starts the scraping retry process.
+//
+// This function should ideally run forever, unless an error is encountered
+// or the context is done.
+func (sr *ScrapeRetrier) Run(ctx context.Context) (err error) {
+	sr.log.Info().Msg("scraping retry starts")
+
+Loop:
+	for {
+		err = sr.findAndRetryFailures(ctx)
+		if err != nil {
+			break
+		}
+
+		sr.log.Info().Msgf("waiting %s", sr.conf.TimeInterval)
+		select {
+		case <-time.After(sr.conf.TimeInterval):
+		case <-ctx.Done():
+			sr.log.Warn().Msg("context done")
+			break Loop
+		}
+	}
+
+	if err != nil && err != errStop {
+		sr.log.Err(err).Msg("scraping retry ends with error")
+		return err
+	}
+
+	sr.log.Info().Msg("scraping retry ends")
+	return nil
+}
+
+func (sr *ScrapeRetrier) findAndRetryFailures(ctx context.Context) error {
+	err := sr.reclaimLostRetries(ctx)
+	if err != nil {
+		return err
+	}
+
+	sr.log.Info().Msg("retrying failed scrapings")
+
+	cond, args := sr.retryCondition(time.Now())
+	if len(cond) == 0 {
+		return nil
+	}
+	query := sr.db.WithContext(ctx).
+		Where("scrape_status = ?", models.FailedScrape).
+		Where(cond, args...)
+
+	var wrs []*models.WebResource
+	res := query.FindInBatches(&wrs, batchSize, func(_ *gorm.DB, batch int) error {
+		sr.log.Debug().Msgf("batch %d", batch)
+		if ctxIsDone(ctx) {
+			sr.log.Warn().Msg("context done")
+			return errStop
+		}
+		return sr.processBatch(ctx, wrs)
+	})
+	return res.Error
+}
+
+// retryCondition returns the SQL condition (and its arguments) satisfied
+// by the failed WebResources which can be retried at the given time: they
+// were attempted less than MaxAttempts times, and their backoff is elapsed.
+// It returns an empty condition if no retries are allowed.
+func (sr *ScrapeRetrier) retryCondition(now time.Time) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	for attempts := 1; attempts < sr.conf.MaxAttempts; attempts++ {
+		conds = append(conds, "(scrape_attempts = ? AND last_scrape_attempt_at <= ?)")
+		args = append(args, attempts, now.Add(-sr.backoff(attempts)))
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
+// backoff returns the delay before retrying a WebResource after the given
+// number of failed attempts: InitialBackoff * 2^(attempts-1), up to
+// MaxBackoff.
+func (sr *ScrapeRetrier) backoff(attempts int) time.Duration {
+	d := sr.conf.InitialBackoff
+	for i := 1; i < attempts && d < sr.conf.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > sr.conf.MaxBackoff {
+		d = sr.conf.MaxBackoff
+	}
+	return d
+}
+
+// reclaimLostRetries marks as failed again the WebResources which are still
+// marked as retrying after RetryingTimeout, since their jobs were lost (for
+// example, they could not be pushed), so that they can be rescheduled.
+//
+// The timeout is measured from the last update of the resource, that is,
+// when it was marked as retrying.
+func (sr *ScrapeRetrier) reclaimLostRetries(ctx context.Context) error {
+	res := sr.db.WithContext(ctx).Model(&models.WebResource{}).
+		Where("scrape_status = ?", models.RetryingScrape).
+		Where("updated_at <= NOW() - ? * INTERVAL '1 second'", sr.conf.RetryingTimeout.Seconds()).
+		Update("scrape_status", models.FailedScrape)
+	if res.Error != nil {
+		return fmt.Errorf("error reclaiming lost scraping retries: %w", res.Error)
+	}
+	if res.RowsAffected > 0 {
+		sr.log.Warn().Int64("WebResources", res.RowsAffected).Msg("reclaimed lost scraping retries")
+	}
+	return nil
+}
+
+func (sr *ScrapeRetrier) processBatch(ctx context.Context, wrs []*models.WebResource) error {
+	ids := make([]uint, len(wrs))
+	for i, wr := range wrs {
+		ids[i] = wr.ID
+	}
+
+	js := jobscheduler.New()
+	err := sr.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Marking the resources prevents them from being rescheduled again
+		// while the jobs are still enqueued.
+		res := tx.Model(&models.WebResource{}).
+			Where("id IN ? AND scrape_status = ?", ids, models.FailedScrape).
+			Update("scrape_status", models.RetryingScrape)
+		if res.Error != nil {
+			return fmt.Errorf("error updating WebResources scrape status: %w", res.Error)
+		}
+
+		for _, wr := range wrs {
+			sr.log.Trace().Uint("WebResource", wr.ID).Int("Attempts", wr.ScrapeAttempts).Msg("retry")
+			err := js.AddJobs(sr.conf.Jobs, wr.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return js.CreatePendingJobs(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	return js.PushJobsWithClientAndDeletePendingJobs(sr.fk, sr.db)
+}
+
+func ctxIsDone(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package scraperetrier
+
+import (
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func testConfig() config.ScrapeRetrier {
+	return config.ScrapeRetrier{
+		MaxAttempts:    4,
+		InitialBackoff: 10 * time.Minute,
+		MaxBackoff:     time.Hour,
+	}
+}
+
+func TestScrapeRetrier_backoff(t *testing.T) {
+	t.Parallel()
+
+	sr := New(testConfig(), nil, nil)
+
+	testCases := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 10 * time.Minute},
+		{2, 20 * time.Minute},
+		{3, 40 * time.Minute},
+		{4, time.Hour},
+		{5, time.Hour},
+		{1000, time.Hour},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, sr.backoff(tc.attempts), "attempts %d", tc.attempts)
+	}
+}
+
+func TestScrapeRetrier_retryCondition(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 11, 20, 12, 0, 0, 0, time.UTC)
+
+	cond, args := New(testConfig(), nil, nil).retryCondition(now)
+	assert.Equal(t, "((scrape_attempts = ? AND last_scrape_attempt_at <= ?) OR "+
+		"(scrape_attempts = ? AND last_scrape_attempt_at <= ?) OR "+
+		"(scrape_attempts = ? AND last_scrape_attempt_at <= ?))", cond)
+	assert.Equal(t, []interface{}{
+		1, now.Add(-10 * time.Minute),
+		2, now.Add(-20 * time.Minute),
+		3, now.Add(-40 * time.Minute),
+	}, args)
+
+	for _, maxAttempts := range []int{0, 1} {
+		conf := testConfig()
+		conf.MaxAttempts = maxAttempts
+		cond, args = New(conf, nil, nil).retryCondition(now)
+		assert.Empty(t, cond, "max attempts %d", maxAttempts)
+		assert.Empty(t, args)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sitemapscheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	faktory "github.com/contribsys/faktory/client"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"time"
+)
+
+// SitemapScheduler implements the mechanism for periodically fetching all
+// enabled Sitemaps and scheduling a set of jobs for each Sitemap.
+type SitemapScheduler struct {
+	conf config.SitemapScheduler
+	db   *gorm.DB
+	fk   *faktory.Client
+	log  zerolog.Logger
+}
+
+// New creates a new SitemapScheduler.
+func New(conf config.SitemapScheduler, db *gorm.DB, fk *faktory.Client) *SitemapScheduler {
+	return &SitemapScheduler{
+		conf: conf,
+		db:   db,
+		fk:   fk,
+		log:  log.Logger.Level(zerolog.Level(conf.LogLevel)),
+	}
+}
+
+const batchSize = 100
+
+var errStop = errors.New("stop")
+
+// Run starts the sitemaps scheduling process.
+//
+// This function should ideally run forever, unless an error is encountered
+// or the context is done.
+func (ss *SitemapScheduler) Run(ctx context.Context) (err error) {
+	ss.log.Info().Msg("sitemaps scheduling starts")
+
+Loop:
+	for {
+		err = ss.findAndScheduleSitemaps(ctx)
+		if err != nil {
+			break
+		}
+
+		ss.log.Info().Msgf("waiting %s", ss.conf.TimeInterval)
+		select {
+		case <-time.After(ss.conf.TimeInterval):
+		case <-ctx.Done():
+			ss.log.Warn().Msg("context done")
+			break Loop
+		}
+	}
+
+	if err != nil && err != errStop {
+		ss.log.Err(err).Msg("sitemaps scheduling ends with error")
+		return err
+	}
+
+	ss.log.Info().Msg("sitemaps scheduling ends")
+	return nil
+}
+
+func (ss *SitemapScheduler) findAndScheduleSitemaps(ctx context.Context) error {
+	ss.log.Info().Msg("scheduling all sitemaps")
+
+	query := ss.db.WithContext(ctx).
+		Where("enabled = true").
+		Order("last_retrieved_at NULLS FIRST, id")
+
+	var sitemaps []*models.Sitemap
+	res := query.FindInBatches(&sitemaps, batchSize, func(_ *gorm.DB, batch int) error {
+		ss.log.Debug().Msgf("batch %d", batch)
+		return ss.processBatch(ctx, sitemaps)
+	})
+	return res.Error
+}
+
+func (ss *SitemapScheduler) processBatch(ctx context.Context, sitemaps []*models.Sitemap) error {
+	for _, sitemap := range sitemaps {
+		if ctxIsDone(ctx) {
+			ss.log.Warn().Msg("context done")
+			return errStop
+		}
+		err := ss.scheduleSitemapJobs(sitemap)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ss *SitemapScheduler) scheduleSitemapJobs(sitemap *models.Sitemap) error {
+	// The Context is ignored on purpose here, so that it is more likely that
+	// the full set of jobs is scheduled for each sitemap, even if the
+	// context is canceled in the meanwhile.
+
+	for _, fj := range ss.conf.Jobs {
+		job := faktory.NewJob(fj.JobType, sitemap.ID)
+		job.Queue = fj.Queue
+		job.ReserveFor = fj.ReserveFor
+		job.Retry = new(int)
+		*job.Retry = fj.Retry
+
+		ss.log.Trace().Interface("job", job).Msg("push job")
+
+		err := ss.fk.Push(job)
+		if err != nil {
+			return fmt.Errorf("error pushing Job %+v for Sitemap %d: %w", fj, sitemap.ID, err)
+		}
+	}
+	return nil
+}
+
+func ctxIsDone(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package translationprovider
+
+import (
+	"context"
+	"fmt"
+	translatorapi "github.com/SpecializedGeneralist/translator/pkg/api"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/grpcconn"
+	"github.com/rs/zerolog"
+	"strings"
+)
+
+// GRPCProvider is a TranslationProvider which uses the SpecializedGeneralist
+// translator gRPC service.
+type GRPCProvider struct {
+	name string
+	conf config.GRPCServer
+	log  zerolog.Logger
+}
+
+// NewGRPCProvider creates a new GRPCProvider.
+func NewGRPCProvider(name string, conf config.GRPCServer, log zerolog.Logger) *GRPCProvider {
+	return &GRPCProvider{
+		name: name,
+		conf: conf,
+		log:  log,
+	}
+}
+
+// Name returns the configured name of the provider.
+func (p *GRPCProvider) Name() string {
+	return p.name
+}
+
+// Translate translates each text with a separate TranslateText call, over
+// the same connection.
+func (p *GRPCProvider) Translate(
+	ctx context.Context,
+	texts []string,
+	sourceLanguage, targetLanguage string,
+) ([]string, error) {
+	translatorConn, err := grpcconn.Dial(ctx, p.conf)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := translatorConn.Close(); err != nil {
+			p.log.Err(err).Msg("error closing translator connection")
+		}
+	}()
+	translatorClient := translatorapi.NewApiClient(translatorConn)
+
+	translations := make([]string, len(texts))
+	for i, text := range texts {
+		resp, err := translatorClient.TranslateText(ctx, &translatorapi.TranslateTextRequest{
+			TranslateTextInput: &translatorapi.TranslateTextInput{
+				SourceLanguage: sourceLanguage,
+				TargetLanguage: targetLanguage,
+				Text:           text,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("TranslateText error: %w", err)
+		}
+		if resp.Errors != nil && len(resp.Errors.Value) > 0 {
+			return nil, fmt.Errorf("TranslateText responded with errors; first message: %s", resp.Errors.Value[0].Message)
+		}
+		translations[i] = strings.TrimSpace(resp.Data.TranslatedText)
+	}
+	return translations, nil
+}
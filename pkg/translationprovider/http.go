@@ -0,0 +1,117 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package translationprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodyLength is the maximum number of bytes of an error response
+// body which are reported in the error message.
+const maxErrorBodyLength = 512
+
+// HTTPProvider is a TranslationProvider which uses a LibreTranslate-compatible
+// HTTP API.
+type HTTPProvider struct {
+	name   string
+	conf   config.TranslationHTTPAPI
+	client *http.Client
+}
+
+// NewHTTPProvider creates a new HTTPProvider.
+func NewHTTPProvider(name string, conf config.TranslationHTTPAPI) *HTTPProvider {
+	return &HTTPProvider{
+		name:   name,
+		conf:   conf,
+		client: &http.Client{Timeout: conf.RequestTimeout},
+	}
+}
+
+// Name returns the configured name of the provider.
+func (p *HTTPProvider) Name() string {
+	return p.name
+}
+
+type httpTranslateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+type httpTranslateResponse struct {
+	TranslatedText string `json:"translatedText"`
+	Error          string `json:"error"`
+}
+
+// Translate translates each text with a separate HTTP request.
+func (p *HTTPProvider) Translate(
+	ctx context.Context,
+	texts []string,
+	sourceLanguage, targetLanguage string,
+) ([]string, error) {
+	translations := make([]string, len(texts))
+	for i, text := range texts {
+		translation, err := p.translate(ctx, text, sourceLanguage, targetLanguage)
+		if err != nil {
+			return nil, err
+		}
+		translations[i] = translation
+	}
+	return translations, nil
+}
+
+func (p *HTTPProvider) translate(ctx context.Context, text, sourceLanguage, targetLanguage string) (_ string, err error) {
+	reqBody, err := json.Marshal(httpTranslateRequest{
+		Q:      text,
+		Source: sourceLanguage,
+		Target: targetLanguage,
+		Format: "text",
+		APIKey: p.conf.APIKey,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error encoding translation request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.conf.URL, bytes.NewReader(reqBody))
+	if err != nil {
+		return "", fmt.Errorf("error creating translation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("translation request error: %w", err)
+	}
+	defer func() {
+		if e := resp.Body.Close(); e != nil && err == nil {
+			err = fmt.Errorf("error closing response body: %w", e)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+		return "", fmt.Errorf("translation request failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	var respBody httpTranslateResponse
+	err = json.NewDecoder(resp.Body).Decode(&respBody)
+	if err != nil {
+		return "", fmt.Errorf("error decoding translation response: %w", err)
+	}
+	if len(respBody.Error) > 0 {
+		return "", fmt.Errorf("translation responded with error: %s", respBody.Error)
+	}
+	return strings.TrimSpace(respBody.TranslatedText), nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package translationprovider provides a common interface to different
+// machine translation services.
+package translationprovider
+
+import (
+	"context"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/rs/zerolog"
+)
+
+// AnyLanguage matches any language in a config.TranslationLanguagePair.
+const AnyLanguage = "*"
+
+// TranslationProvider is implemented by any translation service.
+type TranslationProvider interface {
+	// Name returns the configured name of the provider.
+	Name() string
+	// Translate translates each text from the source language to the
+	// target language, returning the translations in the same order.
+	Translate(ctx context.Context, texts []string, sourceLanguage, targetLanguage string) ([]string, error)
+}
+
+// New creates a new TranslationProvider of the configured type.
+func New(conf config.TranslationProvider, log zerolog.Logger) (TranslationProvider, error) {
+	switch conf.Type {
+	case config.GRPCTranslationProvider:
+		return NewGRPCProvider(conf.Name, conf.GRPCServer, log), nil
+	case config.HTTPTranslationProvider:
+		return NewHTTPProvider(conf.Name, conf.HTTPAPI), nil
+	case config.IdentityTranslationProvider:
+		return NewIdentityProvider(conf.Name), nil
+	default:
+		return nil, fmt.Errorf("invalid type %#v for translation provider %#v", conf.Type, conf.Name)
+	}
+}
+
+// Router chooses the TranslationProvider for each pair of languages.
+type Router struct {
+	pairs     []config.TranslationLanguagePair
+	providers map[string]TranslationProvider
+}
+
+// NewRouter creates a new Router from the given providers and language
+// pairs settings.
+func NewRouter(
+	providersConf []config.TranslationProvider,
+	pairs []config.TranslationLanguagePair,
+	log zerolog.Logger,
+) (*Router, error) {
+	providers := make(map[string]TranslationProvider, len(providersConf))
+	for _, pc := range providersConf {
+		if _, exists := providers[pc.Name]; exists {
+			return nil, fmt.Errorf("duplicate translation provider %#v", pc.Name)
+		}
+		p, err := New(pc, log)
+		if err != nil {
+			return nil, err
+		}
+		providers[pc.Name] = p
+	}
+
+	for _, pair := range pairs {
+		if _, ok := providers[pair.Provider]; !ok {
+			return nil, fmt.Errorf("unknown translation provider %#v for languages %#v -> %#v",
+				pair.Provider, pair.SourceLanguage, pair.TargetLanguage)
+		}
+	}
+
+	return &Router{
+		pairs:     pairs,
+		providers: providers,
+	}, nil
+}
+
+// Provider returns the provider of the first language pair matching the
+// given languages, or nil if no pair matches.
+func (r *Router) Provider(sourceLanguage, targetLanguage string) TranslationProvider {
+	for _, pair := range r.pairs {
+		if matchLanguage(pair.SourceLanguage, sourceLanguage) && matchLanguage(pair.TargetLanguage, targetLanguage) {
+			return r.providers[pair.Provider]
+		}
+	}
+	return nil
+}
+
+func matchLanguage(pattern, language string) bool {
+	return pattern == AnyLanguage || pattern == language
+}
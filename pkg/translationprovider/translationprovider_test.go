@@ -0,0 +1,134 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package translationprovider
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRouter_Provider(t *testing.T) {
+	t.Parallel()
+
+	providers := []config.TranslationProvider{
+		{Name: "a", Type: config.IdentityTranslationProvider},
+		{Name: "b", Type: config.IdentityTranslationProvider},
+	}
+	pairs := []config.TranslationLanguagePair{
+		{SourceLanguage: "fr", TargetLanguage: "en", Provider: "a"},
+		{SourceLanguage: "*", TargetLanguage: "de", Provider: "b"},
+		{SourceLanguage: "it", TargetLanguage: "*", Provider: "a"},
+	}
+	r, err := NewRouter(providers, pairs, zerolog.Nop())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		source, target string
+		expected       string
+	}{
+		{"fr", "en", "a"},
+		{"fr", "de", "b"},
+		{"it", "de", "b"},
+		{"it", "es", "a"},
+		{"fr", "es", ""},
+	}
+	for _, tc := range testCases {
+		p := r.Provider(tc.source, tc.target)
+		if tc.expected == "" {
+			assert.Nil(t, p, "%s -> %s", tc.source, tc.target)
+			continue
+		}
+		require.NotNil(t, p, "%s -> %s", tc.source, tc.target)
+		assert.Equal(t, tc.expected, p.Name(), "%s -> %s", tc.source, tc.target)
+	}
+}
+
+func TestNewRouter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown provider", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewRouter(
+			[]config.TranslationProvider{{Name: "a", Type: config.IdentityTranslationProvider}},
+			[]config.TranslationLanguagePair{{SourceLanguage: "*", TargetLanguage: "*", Provider: "b"}},
+			zerolog.Nop(),
+		)
+		assert.Error(t, err)
+	})
+
+	t.Run("duplicate provider", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewRouter(
+			[]config.TranslationProvider{
+				{Name: "a", Type: config.IdentityTranslationProvider},
+				{Name: "a", Type: config.IdentityTranslationProvider},
+			},
+			nil,
+			zerolog.Nop(),
+		)
+		assert.Error(t, err)
+	})
+}
+
+func TestIdentityProvider_Translate(t *testing.T) {
+	t.Parallel()
+	p := NewIdentityProvider("identity")
+	result, err := p.Translate(context.Background(), []string{"foo", "bar"}, "it", "en")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"foo", "bar"}, result)
+}
+
+func TestHTTPProvider_Translate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("successful translation", func(t *testing.T) {
+		t.Parallel()
+
+		var requests []httpTranslateRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req httpTranslateRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			requests = append(requests, req)
+			_ = json.NewEncoder(w).Encode(httpTranslateResponse{TranslatedText: " [" + req.Q + "] "})
+		}))
+		defer server.Close()
+
+		p := NewHTTPProvider("http", config.TranslationHTTPAPI{
+			URL:            server.URL,
+			APIKey:         "secret",
+			RequestTimeout: 10 * time.Second,
+		})
+		result, err := p.Translate(context.Background(), []string{"foo", "bar"}, "it", "en")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"[foo]", "[bar]"}, result)
+		assert.Equal(t, []httpTranslateRequest{
+			{Q: "foo", Source: "it", Target: "en", Format: "text", APIKey: "secret"},
+			{Q: "bar", Source: "it", Target: "en", Format: "text", APIKey: "secret"},
+		}, requests)
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(httpTranslateResponse{Error: "unsupported language"})
+		}))
+		defer server.Close()
+
+		p := NewHTTPProvider("http", config.TranslationHTTPAPI{URL: server.URL})
+		_, err := p.Translate(context.Background(), []string{"foo"}, "xx", "en")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported language")
+	})
+}
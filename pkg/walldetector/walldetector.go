@@ -0,0 +1,129 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package walldetector recognizes the paywall and consent-wall pages which
+// some websites serve in place of their articles.
+package walldetector
+
+import (
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Wall acts as an enumeration type for the kinds of wall.
+type Wall string
+
+const (
+	// NoWall means that no wall was detected.
+	NoWall Wall = ""
+	// Paywall means that the content is reserved for subscribers.
+	Paywall Wall = "paywall"
+	// ConsentWall means that the content is hidden until the user accepts
+	// the cookies (or other privacy terms).
+	ConsentWall Wall = "consent_wall"
+)
+
+// Detector detects the walls with configurable heuristics.
+type Detector struct {
+	rules         []rule
+	maxBodyLength int
+}
+
+type rule struct {
+	wall          Wall
+	titlePatterns []*regexp.Regexp
+	selectors     []string
+}
+
+// New creates a new Detector. It returns an error if a title pattern is
+// not a valid regular expression. Invalid CSS selectors never match.
+func New(conf config.WallDetection) (*Detector, error) {
+	paywall, err := newRule(Paywall, conf.PaywallTitlePatterns, conf.PaywallSelectors)
+	if err != nil {
+		return nil, err
+	}
+	consentWall, err := newRule(ConsentWall, conf.ConsentWallTitlePatterns, conf.ConsentWallSelectors)
+	if err != nil {
+		return nil, err
+	}
+	d := &Detector{
+		rules:         []rule{consentWall, paywall},
+		maxBodyLength: conf.MaxWallBodyLength,
+	}
+	return d, nil
+}
+
+func newRule(wall Wall, patterns, selectors []string) (r rule, err error) {
+	r = rule{
+		wall:          wall,
+		titlePatterns: make([]*regexp.Regexp, len(patterns)),
+		selectors:     selectors,
+	}
+	for i, p := range patterns {
+		r.titlePatterns[i], err = regexp.Compile("(?i)" + p)
+		if err != nil {
+			return rule{}, fmt.Errorf("invalid %s title pattern %#v: %w", wall, p, err)
+		}
+	}
+	return r, nil
+}
+
+// Detect reports which wall, if any, the page is. The title is the one
+// extracted from the page, which is checked together with the HTML title
+// element of doc (which can be nil); the body is the text extracted from
+// the page.
+//
+// A matching title is enough to detect a wall. The selectors, instead,
+// only count when the body is short, since many regular articles include
+// a cookie banner, or a subscription box, too. Consent walls are checked
+// first, since they hide any other content.
+func (d *Detector) Detect(title, body string, doc *goquery.Document) Wall {
+	titles := []string{strings.TrimSpace(title)}
+	if doc != nil {
+		titles = append(titles, strings.TrimSpace(doc.Find("title").First().Text()))
+	}
+
+	for _, r := range d.rules {
+		if r.matchesTitle(titles) {
+			return r.wall
+		}
+	}
+
+	if doc == nil || (d.maxBodyLength > 0 && utf8.RuneCountInString(strings.TrimSpace(body)) >= d.maxBodyLength) {
+		return NoWall
+	}
+	for _, r := range d.rules {
+		if r.matchesDoc(doc) {
+			return r.wall
+		}
+	}
+	return NoWall
+}
+
+func (r rule) matchesTitle(titles []string) bool {
+	for _, t := range titles {
+		if len(t) == 0 {
+			continue
+		}
+		for _, p := range r.titlePatterns {
+			if p.MatchString(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r rule) matchesDoc(doc *goquery.Document) bool {
+	for _, s := range r.selectors {
+		if doc.Find(s).Length() > 0 {
+			return true
+		}
+	}
+	return false
+}
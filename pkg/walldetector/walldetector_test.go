@@ -0,0 +1,117 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package walldetector
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testConfig() config.WallDetection {
+	return config.WallDetection{
+		PaywallTitlePatterns:     []string{"^subscribe to continue"},
+		ConsentWallTitlePatterns: []string{"^before you continue"},
+		PaywallSelectors:         []string{".paywall"},
+		ConsentWallSelectors:     []string{"#consent-banner"},
+		MaxWallBodyLength:        20,
+	}
+}
+
+func parse(t *testing.T, content string) *goquery.Document {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	conf := testConfig()
+	conf.PaywallTitlePatterns = []string{"("}
+	_, err := New(conf)
+	assert.Error(t, err)
+}
+
+func TestDetector_Detect(t *testing.T) {
+	t.Parallel()
+
+	d, err := New(testConfig())
+	require.NoError(t, err)
+
+	longBody := "This is the long body of a regular article."
+
+	testCases := []struct {
+		name  string
+		title string
+		body  string
+		html  string
+		want  Wall
+	}{
+		{"regular article", "Breaking news", longBody, "<title>Breaking news</title>", NoWall},
+		{"paywall title", "Subscribe to continue reading", longBody, "", Paywall},
+		{"consent wall HTML title", "Google", "", "<title>Before you continue to Google</title>", ConsentWall},
+		{"paywall selector", "Breaking news", "Short.", `<div class="paywall">Join us</div>`, Paywall},
+		{"consent selector", "Breaking news", "", `<div id="consent-banner"></div><div class="paywall"></div>`, ConsentWall},
+		{"selector with long body", "Breaking news", longBody, `<div class="paywall"></div>`, NoWall},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, d.Detect(tc.title, tc.body, parse(t, tc.html)))
+		})
+	}
+
+	assert.Equal(t, Paywall, d.Detect("Subscribe to continue", "", nil))
+	assert.Equal(t, NoWall, d.Detect("Breaking news", "", nil))
+}
+
+func TestDetector_Detect_NoBodyLimit(t *testing.T) {
+	t.Parallel()
+
+	conf := testConfig()
+	conf.MaxWallBodyLength = 0
+	d, err := New(conf)
+	require.NoError(t, err)
+
+	doc := parse(t, `<div class="paywall"></div>`)
+	assert.Equal(t, Paywall, d.Detect("Breaking news", strings.Repeat("text ", 100), doc))
+}
+
+func TestDetector_Detect_SampleConfig(t *testing.T) {
+	t.Parallel()
+
+	conf, err := config.FromYAMLFile(filepath.Join("..", "..", "sample-config.yml"))
+	require.NoError(t, err)
+	d, err := New(conf.Workers.WebScraper.WallDetection)
+	require.NoError(t, err)
+
+	walls := map[string]Wall{
+		"Subscribe to continue reading": Paywall,
+		"Subscribers only":              Paywall,
+		"Before you continue to Google": ConsentWall,
+		"Cookie settings":               ConsentWall,
+		"We value your privacy":         ConsentWall,
+	}
+	for title, want := range walls {
+		assert.Equal(t, want, d.Detect(title, "", nil), title)
+	}
+
+	// Real headlines mentioning the same words are not walls.
+	headlines := []string{
+		"EU regulators tighten cookie consent rules",
+		"Why companies claim to value your privacy",
+		"Newspaper launches subscribers only podcast",
+	}
+	for _, title := range headlines {
+		assert.Equal(t, NoWall, d.Detect(title, "", nil), title)
+	}
+}
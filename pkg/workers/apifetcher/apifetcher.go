@@ -0,0 +1,276 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package apifetcher
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/apisource"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/languagerecognition"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
+	"github.com/contribsys/faktory_worker_go"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"time"
+)
+
+// APIFetcher implements a Faktory worker for fetching API sources and
+// creating new API items.
+type APIFetcher struct {
+	basemodelworker.Worker
+	conf    config.APIFetcher
+	fetcher *apisource.Fetcher
+}
+
+// New creates a new APIFetcher.
+func New(conf config.APIFetcher, db *gorm.DB, fk *faktory_worker.Manager) *APIFetcher {
+	af := &APIFetcher{
+		conf:    conf,
+		fetcher: apisource.NewFetcher(conf.RequestTimeout, conf.UserAgent),
+	}
+	af.Worker = basemodelworker.Worker{
+		Name:        "APIFetcher",
+		DB:          db,
+		FK:          fk,
+		Log:         log.Logger.Level(zerolog.Level(conf.LogLevel)),
+		Concurrency: conf.Concurrency,
+		Queues:      conf.Queues,
+		Perform:     af.perform,
+	}
+	return af
+}
+
+func (af *APIFetcher) perform(ctx context.Context, sourceID uint) error {
+	tx := af.DB.WithContext(ctx)
+
+	source, err := getAPISource(tx, sourceID)
+	if err != nil {
+		return err
+	}
+	if !source.Enabled {
+		af.Log.Warn().Msgf("skipping API source %d: not enabled", source.ID)
+		return nil
+	}
+
+	items, err := af.fetchNewItems(ctx, tx, source)
+	if err != nil {
+		af.Log.Warn().Err(err).Msgf("error fetching API source %d", source.ID)
+		return af.markSourceWithError(tx, source, err)
+	}
+
+	js := jobscheduler.New()
+	err = tx.Transaction(func(tx *gorm.DB) error {
+		err = af.processItems(tx, source, js, items)
+		if err != nil {
+			return err
+		}
+		return js.CreatePendingJobs(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	return js.PushJobsAndDeletePendingJobs(ctx, af.DB)
+}
+
+func getAPISource(tx *gorm.DB, sourceID uint) (*models.APISource, error) {
+	var source *models.APISource
+	res := tx.First(&source, sourceID)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error fetching APISource %d: %w", sourceID, res.Error)
+	}
+	return source, nil
+}
+
+func (af *APIFetcher) markSourceWithError(tx *gorm.DB, source *models.APISource, sourceError error) error {
+	source.LastError = sql.NullString{Valid: true, String: sourceError.Error()}
+	source.FailuresCount++
+	source.Enabled = source.FailuresCount <= af.conf.MaxAllowedFailures
+
+	err := models.OptimisticSave(tx, source)
+	if err != nil {
+		return fmt.Errorf("error saving API source (marked with error): %w", err)
+	}
+	return nil
+}
+
+// fetchNewItems reads the pages of the API source, stopping after the
+// first page whose items are all already known.
+func (af *APIFetcher) fetchNewItems(ctx context.Context, tx *gorm.DB, source *models.APISource) ([]apisource.Item, error) {
+	var items []apisource.Item
+	err := af.fetcher.Fetch(ctx, source, func(page []apisource.Item) (bool, error) {
+		items = append(items, page...)
+
+		urls := make([]string, len(page))
+		for i, item := range page {
+			urls[i] = item.URL
+		}
+		var count int64
+		res := tx.Model(&models.APIItem{}).
+			Joins("JOIN web_resources ON web_resources.id = api_items.web_resource_id").
+			Where("api_items.api_source_id = ? AND web_resources.url IN ?", source.ID, urls).
+			Count(&count)
+		if res.Error != nil {
+			return false, fmt.Errorf("error counting known APIItems: %w", res.Error)
+		}
+		return count < int64(len(page)), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (af *APIFetcher) processItems(
+	tx *gorm.DB,
+	source *models.APISource,
+	js *jobscheduler.JobScheduler,
+	items []apisource.Item,
+) error {
+	for _, item := range items {
+		err := af.processItem(tx, source, item, js)
+		if err != nil {
+			return fmt.Errorf("error processing API item: %w", err)
+		}
+	}
+
+	source.LastError = sql.NullString{Valid: false, String: ""}
+	source.FailuresCount = 0
+	source.LastRetrievedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	err := models.OptimisticSave(tx, source)
+	if err != nil {
+		return fmt.Errorf("error updating processed APISource: %w", err)
+	}
+	return nil
+}
+
+func (af *APIFetcher) processItem(
+	tx *gorm.DB,
+	source *models.APISource,
+	item apisource.Item,
+	js *jobscheduler.JobScheduler,
+) error {
+	logger := af.Log.With().Uint("APISource", source.ID).Str("URL", item.URL).Logger()
+
+	if af.itemIsTooOld(item) {
+		logger.Debug().Time("PublishedAt", *item.PublishedAt).Msg("item is too old")
+		return nil
+	}
+
+	lang := itemLanguage(source, item)
+	if len(lang) > 0 && !af.languageIsAllowed(lang) {
+		logger.Debug().Str("Title", item.Title).Str("Lang", lang).Msg("language is not allowed")
+		return nil
+	}
+
+	webResource, err := findWebResource(tx, item.URL)
+	if err != nil {
+		return err
+	}
+
+	apiItem := &models.APIItem{
+		APISourceID: source.ID,
+		Title:       item.Title,
+		Description: item.Description,
+		Language:    lang,
+		PublishedAt: makeNullTime(item.PublishedAt),
+	}
+
+	if webResource != nil {
+		logger = logger.With().Uint("WebResource", webResource.ID).Logger()
+
+		if webResource.APIItem != nil {
+			logger.Trace().Uint("APIItem", webResource.APIItem.ID).Msg("an APIItem already exists")
+			return nil
+		}
+
+		apiItem.WebResourceID = webResource.ID
+		return createAPIItem(tx, logger, apiItem)
+	}
+
+	webResource = &models.WebResource{
+		URL:     item.URL,
+		APIItem: apiItem,
+	}
+
+	res := tx.Create(webResource)
+	if database.IsUniqueViolationError(res.Error) {
+		logger.Warn().Err(res.Error).Msg("WebResource and APIItem creation constraint violation")
+		return nil
+	}
+	if res.Error != nil {
+		return fmt.Errorf("error creating WebResource: %w", res.Error)
+	}
+	return js.AddJobs(af.conf.NewWebResourceJobs, webResource.ID)
+}
+
+func createAPIItem(tx *gorm.DB, logger zerolog.Logger, item *models.APIItem) error {
+	res := tx.Create(item)
+	if database.IsUniqueViolationError(res.Error) {
+		logger.Warn().Err(res.Error).Msg("APIItem creation constraint violation")
+		return nil
+	}
+	if res.Error != nil {
+		return fmt.Errorf("error creating APIItem: %w", res.Error)
+	}
+	return nil
+}
+
+func findWebResource(tx *gorm.DB, url string) (*models.WebResource, error) {
+	var webResource *models.WebResource
+	result := tx.Joins("APIItem").Limit(1).Find(&webResource, "url = ?", url)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error fetching WebResource by URL %#v: %w", url, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return webResource, nil
+}
+
+// itemLanguage returns the language forced on the APISource, if any, or
+// the language detected from the item's title. An empty string is returned
+// if the language is unknown: it will be detected later from the scraped
+// article.
+func itemLanguage(source *models.APISource, item apisource.Item) string {
+	if source.Language.Valid {
+		return source.Language.String
+	}
+	if len(item.Title) == 0 {
+		return ""
+	}
+	if lang, ok := languagerecognition.RecognizeLanguage(item.Title); ok {
+		return lang
+	}
+	return ""
+}
+
+func (af *APIFetcher) itemIsTooOld(item apisource.Item) bool {
+	return af.conf.OmitItemsPublishedBefore.Enabled &&
+		item.PublishedAt != nil &&
+		item.PublishedAt.Before(af.conf.OmitItemsPublishedBefore.Time)
+}
+
+func (af *APIFetcher) languageIsAllowed(lang string) bool {
+	for _, l := range af.conf.LanguageFilter {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func makeNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{Valid: false}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/SpecializedGeneralist/whatsnew/pkg/hnswclient"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
 	"github.com/contribsys/faktory_worker_go"
 	"github.com/rs/zerolog"
@@ -112,11 +113,22 @@ func (dd *DuplicateDetector) perform(ctx context.Context, webArticleID uint) err
 
 	js := jobscheduler.New()
 	err = tx.Transaction(func(tx *gorm.DB) error {
+		storyID, err := assignStory(tx, wa, simInfo.ParentID)
+		if err != nil {
+			return err
+		}
+		simInfo.StoryID = &storyID
+
 		res := tx.Create(simInfo)
 		if res.Error != nil {
 			return fmt.Errorf("error creating SimilarityInfo: %w", res.Error)
 		}
 
+		err = stagenotifier.Notify(tx, stagenotifier.DuplicateDetector, wa.ID)
+		if err != nil {
+			return err
+		}
+
 		var jobs []config.FaktoryJob
 		if simInfo.ParentID == nil {
 			jobs = dd.conf.NonDuplicateWebArticleJobs
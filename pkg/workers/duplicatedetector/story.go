@@ -0,0 +1,158 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package duplicatedetector
+
+import (
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/sets"
+	"gorm.io/gorm"
+)
+
+// assignStory makes the WebArticle join the Story of its parent, or start a
+// new Story if it has no parent. It returns the ID of the Story.
+func assignStory(tx *gorm.DB, wa *models.WebArticle, parentID *uint) (uint, error) {
+	if parentID == nil {
+		story, err := findOrCreateStory(tx, wa)
+		if err != nil {
+			return 0, err
+		}
+		return story.ID, nil
+	}
+
+	story, err := getParentStory(tx, *parentID)
+	if err != nil {
+		return 0, err
+	}
+	err = addStoryMember(tx, story, wa)
+	if err != nil {
+		return 0, err
+	}
+	return story.ID, nil
+}
+
+// findOrCreateStory returns the Story having the given WebArticle as
+// representative. If it does not exist, a new Story is created, with the
+// WebArticle as only member.
+//
+// The Story might already exist if a child WebArticle was processed first
+// (see getParentStory).
+func findOrCreateStory(tx *gorm.DB, wa *models.WebArticle) (*models.Story, error) {
+	var story *models.Story
+	res := tx.Limit(1).Find(&story, "representative_id = ?", wa.ID)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error fetching Story of WebArticle %d: %w", wa.ID, res.Error)
+	}
+	if res.RowsAffected > 0 {
+		return story, nil
+	}
+
+	story = &models.Story{
+		RepresentativeID: wa.ID,
+		FirstSeenAt:      wa.PublishDate,
+		LastSeenAt:       wa.PublishDate,
+		MembersCount:     1,
+	}
+	err := story.CountryCodes.Set(countryCodesOf(wa))
+	if err != nil {
+		return nil, fmt.Errorf("error setting Story.CountryCodes: %w", err)
+	}
+
+	res = tx.Create(story)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error creating Story: %w", res.Error)
+	}
+	return story, nil
+}
+
+// getParentStory returns the Story of the parent WebArticle.
+//
+// If the parent has no Story, it means it was processed before Story
+// clustering was introduced, or it was not processed yet: in this case, the
+// Story having the parent as representative is found or created, and the
+// parent's SimilarityInfo (if any) is updated accordingly. When the parent
+// is processed later, it reuses the same Story.
+func getParentStory(tx *gorm.DB, parentID uint) (*models.Story, error) {
+	var parentSimInfo models.SimilarityInfo
+	res := tx.Limit(1).Find(&parentSimInfo, "web_article_id = ?", parentID)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error fetching SimilarityInfo of parent WebArticle %d: %w", parentID, res.Error)
+	}
+	parentSimInfoFound := res.RowsAffected > 0
+
+	if parentSimInfoFound && parentSimInfo.StoryID != nil {
+		var story *models.Story
+		res = tx.First(&story, *parentSimInfo.StoryID)
+		if res.Error != nil {
+			return nil, fmt.Errorf("error fetching Story %d: %w", *parentSimInfo.StoryID, res.Error)
+		}
+		return story, nil
+	}
+
+	var parent *models.WebArticle
+	res = tx.First(&parent, parentID)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error fetching parent WebArticle %d: %w", parentID, res.Error)
+	}
+	story, err := findOrCreateStory(tx, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentSimInfoFound {
+		parentSimInfo.StoryID = &story.ID
+		err = models.OptimisticSave(tx, &parentSimInfo)
+		if err != nil {
+			return nil, fmt.Errorf("error updating SimilarityInfo of parent WebArticle %d: %w", parentID, err)
+		}
+	}
+	return story, nil
+}
+
+// addStoryMember updates the Story for including the given WebArticle.
+func addStoryMember(tx *gorm.DB, story *models.Story, wa *models.WebArticle) error {
+	if wa.PublishDate.Before(story.FirstSeenAt) {
+		story.FirstSeenAt = wa.PublishDate
+	}
+	if wa.PublishDate.After(story.LastSeenAt) {
+		story.LastSeenAt = wa.PublishDate
+	}
+	story.MembersCount++
+
+	codes, err := story.CountryCodesAsStringSlice()
+	if err != nil {
+		return err
+	}
+	err = story.CountryCodes.Set(mergeCountryCodes(codes, countryCodesOf(wa)))
+	if err != nil {
+		return fmt.Errorf("error setting Story.CountryCodes: %w", err)
+	}
+
+	err = models.OptimisticSave(tx, story)
+	if err != nil {
+		return fmt.Errorf("error updating Story %d: %w", story.ID, err)
+	}
+	return nil
+}
+
+func countryCodesOf(wa *models.WebArticle) []string {
+	if !wa.CountryCode.Valid || len(wa.CountryCode.String) == 0 {
+		return []string{}
+	}
+	return []string{wa.CountryCode.String}
+}
+
+// mergeCountryCodes appends to codes the values from newCodes which are not
+// already present.
+func mergeCountryCodes(codes, newCodes []string) []string {
+	set := sets.NewStringSetWithElements(codes...)
+	for _, nc := range newCodes {
+		if !set.Has(nc) {
+			set.Add(nc)
+			codes = append(codes, nc)
+		}
+	}
+	return codes
+}
@@ -0,0 +1,128 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package feedfetcher
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/mmcdole/gofeed"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+// publicationRateWindow is the time window of the items considered for
+// computing the publication rate of a feed.
+const publicationRateWindow = 7 * 24 * time.Hour
+
+// fetchFeed retrieves and parses the content of the feed. If the ETag or
+// Last-Modified validators of a previous response are known, the request is
+// conditional, and a nil feed is returned if the content was not modified.
+// Otherwise, the validators of the Feed are updated from the new response.
+func (ff *FeedFetcher) fetchFeed(ctx context.Context, feed *models.Feed) (_ *gofeed.Feed, err error) {
+	ctxTimeout, cancel := context.WithTimeout(ctx, ff.conf.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctxTimeout, http.MethodGet, feed.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", ff.parser.UserAgent)
+	if feed.ETag.Valid {
+		req.Header.Set("If-None-Match", feed.ETag.String)
+	}
+	if feed.LastModified.Valid {
+		req.Header.Set("If-Modified-Since", feed.LastModified.String)
+	}
+
+	resp, err := ff.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := resp.Body.Close(); e != nil && err == nil {
+			err = fmt.Errorf("error closing response body: %w", e)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, gofeed.HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	parsedFeed, err := ff.parser.Parse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	feed.ETag = makeNullString(resp.Header.Get("ETag"))
+	feed.LastModified = makeNullString(resp.Header.Get("Last-Modified"))
+	return parsedFeed, nil
+}
+
+// markFeedAsNotModified updates the Feed after a successful conditional
+// request, whose content was not modified.
+func markFeedAsNotModified(tx *gorm.DB, feed *models.Feed) error {
+	feed.LastError = sql.NullString{Valid: false, String: ""}
+	feed.FailuresCount = 0
+	feed.LastRetrievedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	err := models.OptimisticSave(tx, feed)
+	if err != nil {
+		return fmt.Errorf("error updating not modified Feed: %w", err)
+	}
+	return nil
+}
+
+// publicationRate returns the number of items per day published in the
+// feed, considering only the recent items (see publicationRateWindow).
+// It returns false if none of the items has a publication date.
+func publicationRate(items []*gofeed.Item, now time.Time) (float64, bool) {
+	windowStart := now.Add(-publicationRateWindow)
+
+	dated := false
+	count := 0
+	oldest := now
+	for _, item := range items {
+		if item.PublishedParsed == nil {
+			continue
+		}
+		dated = true
+
+		published := *item.PublishedParsed
+		if published.Before(windowStart) || published.After(now) {
+			continue
+		}
+		count++
+		if published.Before(oldest) {
+			oldest = published
+		}
+	}
+
+	if !dated {
+		return 0, false
+	}
+	if count == 0 {
+		return 0, true
+	}
+
+	// The period is at least one hour, so that a burst of items published
+	// all together does not result in an excessive rate.
+	period := now.Sub(oldest)
+	if period < time.Hour {
+		period = time.Hour
+	}
+	return float64(count) / period.Hours() * 24, true
+}
+
+func makeNullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: len(s) > 0}
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"net/http"
+	"strings"
 	"time"
 )
 
@@ -28,6 +30,7 @@ type FeedFetcher struct {
 	basemodelworker.Worker
 	conf   config.FeedFetcher
 	parser *gofeed.Parser
+	client *http.Client
 }
 
 // New creates a new FeedFetcher.
@@ -35,6 +38,7 @@ func New(conf config.FeedFetcher, db *gorm.DB, fk *faktory_worker.Manager) *Feed
 	ff := &FeedFetcher{
 		conf:   conf,
 		parser: gofeed.NewParser(),
+		client: &http.Client{},
 	}
 	ff.Worker = basemodelworker.Worker{
 		Name:        "FeedFetcher",
@@ -60,11 +64,15 @@ func (ff *FeedFetcher) perform(ctx context.Context, feedID uint) error {
 		return nil
 	}
 
-	parsedFeed, err := ff.parseFeedURL(ctx, feed.URL)
+	parsedFeed, err := ff.fetchFeed(ctx, feed)
 	if err != nil {
 		ff.Log.Warn().Err(err).Msgf("error parsing feed %d", feed.ID)
 		return ff.markFeedWithError(tx, feed, err)
 	}
+	if parsedFeed == nil {
+		ff.Log.Debug().Msgf("feed %d not modified", feed.ID)
+		return markFeedAsNotModified(tx, feed)
+	}
 
 	js := jobscheduler.New()
 	err = tx.Transaction(func(tx *gorm.DB) error {
@@ -90,12 +98,6 @@ func getFeed(tx *gorm.DB, feedID uint) (*models.Feed, error) {
 	return feed, nil
 }
 
-func (ff *FeedFetcher) parseFeedURL(ctx context.Context, url string) (*gofeed.Feed, error) {
-	ctxTimeout, cancel := context.WithTimeout(ctx, ff.conf.RequestTimeout)
-	defer cancel()
-	return ff.parser.ParseURLWithContext(url, ctxTimeout)
-}
-
 func (ff *FeedFetcher) markFeedWithError(tx *gorm.DB, feed *models.Feed, feedError error) error {
 	feed.LastError = sql.NullString{Valid: true, String: feedError.Error()}
 	feed.FailuresCount++
@@ -121,9 +123,19 @@ func (ff *FeedFetcher) processFeed(
 		}
 	}
 
+	if title := strings.TrimSpace(parsedFeed.Title); len(title) > 0 {
+		feed.Title = sql.NullString{String: title, Valid: true}
+	}
+	if link := strings.TrimSpace(parsedFeed.Link); len(link) > 0 {
+		feed.SiteURL = sql.NullString{String: link, Valid: true}
+	}
+	now := time.Now().UTC()
+	if rate, ok := publicationRate(parsedFeed.Items, now); ok {
+		feed.ItemsPerDay = sql.NullFloat64{Float64: rate, Valid: true}
+	}
 	feed.LastError = sql.NullString{Valid: false, String: ""}
 	feed.FailuresCount = 0
-	feed.LastRetrievedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	feed.LastRetrievedAt = sql.NullTime{Time: now, Valid: true}
 	err := models.OptimisticSave(tx, feed)
 	if err != nil {
 		return fmt.Errorf("error updating processed Feed: %w", err)
@@ -144,7 +156,7 @@ func (ff *FeedFetcher) processParsedFeedItem(
 		return nil
 	}
 
-	lang, langOk := languagerecognition.RecognizeLanguage(item.Title)
+	lang, langOk := feedItemLanguage(feed, item)
 	if !langOk {
 		logger.Warn().Str("Title", item.Title).Msg("failed to detect language")
 		return nil
@@ -220,6 +232,15 @@ func findWebResource(tx *gorm.DB, url string) (*models.WebResource, error) {
 	return webResource, nil
 }
 
+// feedItemLanguage returns the language forced on the Feed, if any, or
+// detects the language of the item's title otherwise.
+func feedItemLanguage(feed *models.Feed, item *gofeed.Item) (string, bool) {
+	if feed.Language.Valid {
+		return feed.Language.String, true
+	}
+	return languagerecognition.RecognizeLanguage(item.Title)
+}
+
 func (ff *FeedFetcher) itemIsTooOld(item *gofeed.Item) bool {
 	return ff.conf.OmitItemsPublishedBefore.Enabled &&
 		item.PublishedParsed != nil &&
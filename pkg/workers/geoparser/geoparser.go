@@ -8,24 +8,26 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/articletext"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/cliff"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
 	"github.com/contribsys/faktory_worker_go"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
-	"strings"
 )
 
 // GeoParser implements a Faktory worker for extracting geo-political
 // entities from WebArticles.
 type GeoParser struct {
 	basemodelworker.Worker
-	conf  config.GeoParser
-	cliff *cliff.Client
+	conf         config.GeoParser
+	cliff        *cliff.Client
+	textSelector *articletext.Selector
 }
 
 // New creates a new GeoParser.
@@ -35,8 +37,9 @@ func New(
 	fk *faktory_worker.Manager,
 ) *GeoParser {
 	gp := &GeoParser{
-		conf:  conf,
-		cliff: cliff.NewClient(conf.CliffURI),
+		conf:         conf,
+		cliff:        cliff.NewClient(conf.CliffURI),
+		textSelector: articletext.NewSelector(conf.TextSelection),
 	}
 	gp.Worker = basemodelworker.Worker{
 		Name:        "GeoParser",
@@ -64,7 +67,7 @@ func (gp *GeoParser) perform(ctx context.Context, webArticleID uint) error {
 		return nil
 	}
 
-	countryOk, err := gp.extractAndStoreCountry(ctx, wa)
+	countryOk, err := gp.extractAndStoreCountry(ctx, tx, wa)
 	if err != nil {
 		return err
 	}
@@ -78,6 +81,11 @@ func (gp *GeoParser) perform(ctx context.Context, webArticleID uint) error {
 			}
 		}
 
+		err := stagenotifier.Notify(tx, stagenotifier.GeoParser, wa.ID)
+		if err != nil {
+			return err
+		}
+
 		return js.AddJobsAndCreatePendingJobs(tx, gp.conf.ProcessedWebArticleJobs, wa.ID)
 	})
 	if err != nil {
@@ -98,19 +106,37 @@ func getWebArticle(tx *gorm.DB, id uint) (*models.WebArticle, error) {
 
 func (gp *GeoParser) extractAndStoreCountry(
 	ctx context.Context,
+	tx *gorm.DB,
 	wa *models.WebArticle,
 ) (bool, error) {
 	logger := gp.Log.With().Uint("WebArticle", wa.ID).Logger()
 
-	textOK, text, lang := chooseText(wa)
-	if !textOK {
+	// Only the texts in a supported language are selected.
+	text, err := gp.textSelector.SelectFunc(tx, wa, func(t articletext.Text) bool {
+		_, ok := languages[t.Language]
+		return ok
+	})
+	if err != nil {
+		return false, err
+	}
+	if text.IsEmpty() {
 		logger.Debug().Msg("no text to parse")
 		return false, nil
 	}
+	lang := languages[text.Language]
 
-	countryOK, code, err := gp.extractCountry(ctx, text, lang)
-	if err != nil {
-		return false, err
+	// If the text is split into more chunks, the first country found
+	// is taken.
+	countryOK := false
+	var code string
+	for _, chunk := range text.Chunks {
+		countryOK, code, err = gp.extractCountry(ctx, chunk, lang)
+		if err != nil {
+			return false, err
+		}
+		if countryOK {
+			break
+		}
 	}
 	if !countryOK {
 		logger.Debug().Msg("no country found")
@@ -127,24 +153,6 @@ var languages = map[string]cliff.Language{
 	"en": cliff.English,
 }
 
-func chooseText(wa *models.WebArticle) (bool, string, cliff.Language) {
-	lang, langOK := languages[wa.Language]
-	text := strings.TrimSpace(wa.Title)
-	if langOK && len(text) > 0 {
-		return true, text, lang
-	}
-
-	if wa.TranslationLanguage.Valid && wa.TranslatedTitle.Valid {
-		lang, langOK = languages[wa.TranslationLanguage.String]
-		text = strings.TrimSpace(wa.TranslatedTitle.String)
-		if langOK && len(text) > 0 {
-			return true, text, lang
-		}
-	}
-
-	return false, "", cliff.English
-}
-
 func (gp *GeoParser) extractCountry(
 	ctx context.Context,
 	text string,
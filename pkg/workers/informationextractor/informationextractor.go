@@ -8,10 +8,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/articletext"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/grpcconn"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
 	"github.com/contribsys/faktory_worker_go"
 	bertgrpcapi "github.com/nlpodyssey/spago/pkg/nlp/transformers/bert/grpcapi"
@@ -25,7 +27,8 @@ import (
 // from WebArticles using spaGO BERT Question Answering service.
 type InformationExtractor struct {
 	basemodelworker.Worker
-	conf config.InformationExtractor
+	conf         config.InformationExtractor
+	textSelector *articletext.Selector
 }
 
 // New creates a new InformationExtractor.
@@ -35,7 +38,8 @@ func New(
 	fk *faktory_worker.Manager,
 ) *InformationExtractor {
 	ie := &InformationExtractor{
-		conf: conf,
+		conf:         conf,
+		textSelector: articletext.NewSelector(conf.TextSelection),
 	}
 
 	ie.Worker = basemodelworker.Worker{
@@ -77,6 +81,11 @@ func (ie *InformationExtractor) perform(ctx context.Context, webArticleID uint)
 			}
 		}
 
+		err := stagenotifier.Notify(tx, stagenotifier.InformationExtractor, wa.ID)
+		if err != nil {
+			return err
+		}
+
 		return js.AddJobsAndCreatePendingJobs(tx, ie.conf.ProcessedWebArticleJobs, wa.ID)
 	})
 	if err != nil {
@@ -107,24 +116,23 @@ func (ie *InformationExtractor) processWebArticle(
 		return nil, errSkip
 	}
 
-	title := strings.TrimSpace(wa.Title)
-	if wa.TranslatedTitle.Valid {
-		title = strings.TrimSpace(wa.TranslatedTitle.String)
+	text, err := ie.textSelector.Select(tx, wa)
+	if err != nil {
+		return nil, err
 	}
-
-	if len(title) == 0 {
-		logger.Debug().Msg("empty title - web article skipped")
+	if text.IsEmpty() {
+		logger.Debug().Msg("empty text - web article skipped")
 		return nil, errSkip
 	}
 
-	return ie.extractAndSaveInfo(ctx, tx, wa, title)
+	return ie.extractAndSaveInfo(ctx, tx, wa, text.Chunks)
 }
 
 func (ie *InformationExtractor) extractAndSaveInfo(
 	ctx context.Context,
 	tx *gorm.DB,
 	wa *models.WebArticle,
-	title string,
+	chunks []string,
 ) ([]*models.ExtractedInfo, error) {
 	rules, err := ie.getRules(tx)
 	if err != nil {
@@ -137,7 +145,7 @@ func (ie *InformationExtractor) extractAndSaveInfo(
 	infos := make([]*models.ExtractedInfo, 0, len(rules))
 
 	for _, rule := range rules {
-		ans, err := ie.getBestAnswer(ctx, title, rule.Question)
+		ans, err := ie.getBestAnswer(ctx, chunks, rule.Question)
 		if err != nil {
 			return nil, err
 		}
@@ -169,9 +177,11 @@ func (ie *InformationExtractor) extractAndSaveInfo(
 	return infos, nil
 }
 
+// getBestAnswer returns the answer with the highest confidence, among the
+// best answers for each chunk of the text.
 func (ie *InformationExtractor) getBestAnswer(
 	ctx context.Context,
-	passage,
+	chunks []string,
 	question string,
 ) (*bertgrpcapi.Answer, error) {
 	bertConn, err := grpcconn.Dial(ctx, ie.conf.SpagoBERTServer)
@@ -185,17 +195,23 @@ func (ie *InformationExtractor) getBestAnswer(
 	}()
 	bertClient := bertgrpcapi.NewBERTClient(bertConn)
 
-	reply, err := bertClient.Answer(ctx, &bertgrpcapi.AnswerRequest{
-		Passage:  strings.ToLower(passage),
-		Question: strings.ToLower(question),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("BERT Q/A Answer error: %w", err)
-	}
-	if len(reply.Answers) == 0 {
-		return nil, nil
+	var best *bertgrpcapi.Answer
+	for _, passage := range chunks {
+		reply, err := bertClient.Answer(ctx, &bertgrpcapi.AnswerRequest{
+			Passage:  strings.ToLower(passage),
+			Question: strings.ToLower(question),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("BERT Q/A Answer error: %w", err)
+		}
+		if len(reply.Answers) == 0 {
+			continue
+		}
+		if best == nil || reply.Answers[0].Confidence > best.Confidence {
+			best = reply.Answers[0]
+		}
 	}
-	return reply.Answers[0], nil
+	return best, nil
 }
 
 func (ie *InformationExtractor) getRules(tx *gorm.DB) ([]models.InfoExtractionRule, error) {
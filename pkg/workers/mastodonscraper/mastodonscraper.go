@@ -0,0 +1,387 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mastodonscraper
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/languagerecognition"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/mastodon"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
+	"github.com/contribsys/faktory_worker_go"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MastodonScraper implements a Faktory worker for reading the posts of a
+// specific Mastodon source and creating new web articles.
+type MastodonScraper struct {
+	basemodelworker.Worker
+	conf   config.MastodonScraper
+	client *mastodon.Client
+}
+
+// New creates a new MastodonScraper.
+func New(conf config.MastodonScraper, db *gorm.DB, fk *faktory_worker.Manager) *MastodonScraper {
+	ms := &MastodonScraper{
+		conf:   conf,
+		client: mastodon.NewClient(conf.RequestTimeout, conf.UserAgent),
+	}
+	ms.Worker = basemodelworker.Worker{
+		Name:        "MastodonScraper",
+		DB:          db,
+		FK:          fk,
+		Log:         log.Logger.Level(zerolog.Level(conf.LogLevel)),
+		Concurrency: conf.Concurrency,
+		Queues:      conf.Queues,
+		Perform:     ms.perform,
+	}
+	return ms
+}
+
+func (ms *MastodonScraper) perform(ctx context.Context, mastodonSourceID uint) error {
+	tx := ms.DB.WithContext(ctx)
+
+	src, err := getMastodonSource(tx, mastodonSourceID)
+	if err != nil {
+		return err
+	}
+	if !src.Enabled {
+		ms.Log.Warn().Msgf("skipping MastodonSource %d: not enabled", src.ID)
+		return nil
+	}
+
+	statuses, err := ms.fetchStatuses(ctx, src)
+	if err != nil {
+		ms.Log.Err(err).Msgf("error reading MastodonSource %d", src.ID)
+		return ms.markSourceWithError(tx, src, err)
+	}
+
+	js := jobscheduler.New()
+	err = tx.Transaction(func(tx *gorm.DB) error {
+		for _, status := range statuses {
+			err := ms.processPost(tx, src, status, js)
+			if err != nil {
+				return fmt.Errorf("error processing mastodon post: %w", err)
+			}
+		}
+
+		err = ms.resetSourceErrors(tx, src)
+		if err != nil {
+			return err
+		}
+
+		return js.CreatePendingJobs(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	return js.PushJobsAndDeletePendingJobs(ctx, ms.DB)
+}
+
+func getMastodonSource(tx *gorm.DB, msID uint) (*models.MastodonSource, error) {
+	var src *models.MastodonSource
+	res := tx.First(&src, msID)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error fetching MastodonSource %d: %w", msID, res.Error)
+	}
+	return src, nil
+}
+
+func (ms *MastodonScraper) fetchStatuses(ctx context.Context, src *models.MastodonSource) ([]mastodon.Status, error) {
+	switch src.Type {
+	case models.AccountMastodonSource:
+		account, err := ms.client.LookupAccount(ctx, src.InstanceURL, src.Text)
+		if err != nil {
+			return nil, err
+		}
+		return ms.client.AccountStatuses(ctx, src.InstanceURL, account.ID, ms.conf.MaxPostsNumber)
+	case models.HashtagMastodonSource:
+		return ms.client.HashtagStatuses(ctx, src.InstanceURL, src.Text, ms.conf.MaxPostsNumber)
+	default:
+		return nil, fmt.Errorf("unexpected mastodon-source type %#v", src.Type)
+	}
+}
+
+func (ms *MastodonScraper) processPost(
+	tx *gorm.DB,
+	src *models.MastodonSource,
+	status mastodon.Status,
+	js *jobscheduler.JobScheduler,
+) error {
+	logger := ms.Log.With().Uint("MastodonSource", src.ID).Str("Status", status.URI).Logger()
+
+	if status.Reblog != nil {
+		logger.Debug().Msg("the post is a reblog")
+		return nil
+	}
+	if ms.postIsTooOld(status) {
+		logger.Debug().Time("CreatedAt", status.CreatedAt).Msg("the post is too old")
+		return nil
+	}
+
+	text := status.Text()
+	if len(text) == 0 {
+		logger.Debug().Msg("the post has no text")
+		return nil
+	}
+
+	lang, langOk := postLanguage(status, text)
+	if !langOk {
+		logger.Warn().Str("Text", text).Msg("failed to detect language")
+		return nil
+	}
+	if !ms.languageIsAllowed(lang) {
+		logger.Debug().Str("Text", text).Str("Lang", lang).Msg("language is not allowed")
+		return nil
+	}
+
+	webResource, err := findWebResource(tx, status.PermanentURL())
+	if err != nil {
+		return err
+	}
+
+	post := newMastodonPost(src, status, text)
+	webArticle := newWebArticle(status, text, lang)
+
+	if webResource != nil {
+		logger = logger.With().Uint("WebResource", webResource.ID).Logger()
+
+		if webResource.MastodonPost != nil {
+			logger.Debug().Uint("MastodonPost", webResource.MastodonPost.ID).Msg("a MastodonPost already exists")
+		} else {
+			post.WebResourceID = webResource.ID
+			err = createMastodonPost(tx, logger, post)
+			if err != nil {
+				return err
+			}
+			err = ms.followLinks(tx, logger, post, status, js)
+			if err != nil {
+				return err
+			}
+		}
+
+		if webResource.WebArticle != nil {
+			logger.Debug().Uint("WebArticle", webResource.WebArticle.ID).Msg("a WebArticle already exists")
+			return nil
+		}
+
+		webArticle.WebResourceID = webResource.ID
+		err = createWebArticle(tx, logger, webArticle)
+		if err != nil {
+			return err
+		}
+		if webArticle.ID == 0 {
+			return nil // not created, because of constraint violation
+		}
+		return ms.scheduleNewWebArticle(tx, webArticle.ID, js)
+	}
+
+	webResource = &models.WebResource{
+		URL:          status.PermanentURL(),
+		MastodonPost: post,
+		WebArticle:   webArticle,
+	}
+
+	res := tx.Create(webResource)
+	if database.IsUniqueViolationError(res.Error) {
+		logger.Warn().Err(res.Error).Msg("WebResource, MastodonPost and WebArticle creation constraint violation")
+		return nil
+	}
+	if res.Error != nil {
+		return fmt.Errorf("error creating WebResource: %w", res.Error)
+	}
+	err = ms.followLinks(tx, logger, webResource.MastodonPost, status, js)
+	if err != nil {
+		return err
+	}
+	return ms.scheduleNewWebArticle(tx, webResource.WebArticle.ID, js)
+}
+
+// scheduleNewWebArticle notifies the creation of the WebArticle of a post
+// and adds its jobs.
+func (ms *MastodonScraper) scheduleNewWebArticle(tx *gorm.DB, webArticleID uint, js *jobscheduler.JobScheduler) error {
+	err := stagenotifier.Notify(tx, stagenotifier.MastodonScraper, webArticleID)
+	if err != nil {
+		return err
+	}
+	return js.AddJobs(ms.conf.NewWebArticleJobs, webArticleID)
+}
+
+// followLinks creates a new WebResource for each URL linked by a newly
+// created post, unless it already exists, and records the
+// MastodonPostLink. Jobs are added only for the new WebResources. The URLs
+// of the instance itself are ignored.
+func (ms *MastodonScraper) followLinks(
+	tx *gorm.DB,
+	logger zerolog.Logger,
+	post *models.MastodonPost,
+	status mastodon.Status,
+	js *jobscheduler.JobScheduler,
+) error {
+	if !ms.conf.FollowLinks || post.ID == 0 {
+		return nil
+	}
+
+	postHost := ""
+	if u, err := url.Parse(status.PermanentURL()); err == nil {
+		postHost = u.Host
+	}
+
+	for _, link := range status.Links() {
+		if u, err := url.Parse(link); err != nil || u.Host == postHost {
+			continue
+		}
+
+		webResourceID, created, err := models.FindOrCreateWebResource(tx, link)
+		if err != nil {
+			return err
+		}
+		if created {
+			logger.Trace().Str("URL", link).Uint("WebResource", webResourceID).Msg("new linked WebResource")
+			err = js.AddJobs(ms.conf.NewWebResourceJobs, webResourceID)
+			if err != nil {
+				return err
+			}
+		}
+
+		pl := &models.MastodonPostLink{
+			MastodonPostID: post.ID,
+			WebResourceID:  webResourceID,
+		}
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(pl)
+		if res.Error != nil {
+			return fmt.Errorf("error creating MastodonPostLink: %w", res.Error)
+		}
+	}
+	return nil
+}
+
+func newMastodonPost(src *models.MastodonSource, status mastodon.Status, text string) *models.MastodonPost {
+	return &models.MastodonPost{
+		MastodonSourceID: src.ID,
+		UpstreamID:       status.URI,
+		Text:             text,
+		PublishedAt:      status.CreatedAt,
+		Username:         status.Account.Acct,
+		AccountURL:       status.Account.URL,
+	}
+}
+
+func newWebArticle(status mastodon.Status, text, language string) *models.WebArticle {
+	wa := &models.WebArticle{
+		Title: text,
+		ScrapedPublishDate: sql.NullTime{
+			Valid: true,
+			Time:  status.CreatedAt,
+		},
+		Language:    language,
+		PublishDate: status.CreatedAt,
+	}
+	if image := status.ImageURL(); len(image) > 0 {
+		wa.TopImage = sql.NullString{
+			Valid:  true,
+			String: image,
+		}
+	}
+	return wa
+}
+
+// postLanguage returns the language declared by the post, if any, otherwise
+// the language detected from its text.
+func postLanguage(status mastodon.Status, text string) (string, bool) {
+	if lang := strings.ToLower(strings.TrimSpace(status.Language)); len(lang) > 0 {
+		if i := strings.IndexAny(lang, "-_"); i > 0 {
+			lang = lang[:i]
+		}
+		return lang, true
+	}
+	return languagerecognition.RecognizeLanguage(text)
+}
+
+func createMastodonPost(tx *gorm.DB, logger zerolog.Logger, post *models.MastodonPost) error {
+	res := tx.Create(post)
+	if database.IsUniqueViolationError(res.Error) {
+		logger.Warn().Err(res.Error).Msg("MastodonPost creation constraint violation")
+		return nil
+	}
+	if res.Error != nil {
+		return fmt.Errorf("error creating MastodonPost: %w", res.Error)
+	}
+	return nil
+}
+
+func createWebArticle(tx *gorm.DB, logger zerolog.Logger, wa *models.WebArticle) error {
+	res := tx.Create(wa)
+	if database.IsUniqueViolationError(res.Error) {
+		logger.Warn().Err(res.Error).Msg("WebArticle creation constraint violation")
+		return nil
+	}
+	if res.Error != nil {
+		return fmt.Errorf("error creating WebArticle: %w", res.Error)
+	}
+	return nil
+}
+
+func (ms *MastodonScraper) markSourceWithError(tx *gorm.DB, src *models.MastodonSource, sourceErr error) error {
+	src.LastError = sql.NullString{Valid: true, String: sourceErr.Error()}
+	src.FailuresCount++
+
+	err := models.OptimisticSave(tx, src)
+	if err != nil {
+		return fmt.Errorf("error saving MastodonSource (marked with error): %w", err)
+	}
+	return nil
+}
+
+func (ms *MastodonScraper) resetSourceErrors(tx *gorm.DB, src *models.MastodonSource) error {
+	src.LastRetrievedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	src.LastError = sql.NullString{Valid: false, String: ""}
+	src.FailuresCount = 0
+
+	err := models.OptimisticSave(tx, src)
+	if err != nil {
+		return fmt.Errorf("error saving MastodonSource (resetting errors): %w", err)
+	}
+	return nil
+}
+
+func findWebResource(tx *gorm.DB, url string) (*models.WebResource, error) {
+	var webResource *models.WebResource
+	result := tx.Joins("MastodonPost").Joins("WebArticle").Limit(1).Find(&webResource, "url = ?", url)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error fetching WebResource by URL %#v: %w", url, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return webResource, nil
+}
+
+func (ms *MastodonScraper) postIsTooOld(status mastodon.Status) bool {
+	return ms.conf.OmitPostsPublishedBefore.Enabled &&
+		status.CreatedAt.Before(ms.conf.OmitPostsPublishedBefore.Time)
+}
+
+func (ms *MastodonScraper) languageIsAllowed(lang string) bool {
+	for _, l := range ms.conf.LanguageFilter {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,411 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package newsletterfetcher
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/imapclient"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/languagerecognition"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/newsletter"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
+	"github.com/contribsys/faktory_worker_go"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// NewsletterFetcher implements a Faktory worker for reading the new messages
+// of a specific NewsletterSource from its IMAP folder, and creating either
+// new web articles or new linked web resources.
+type NewsletterFetcher struct {
+	basemodelworker.Worker
+	conf config.NewsletterFetcher
+}
+
+// New creates a new NewsletterFetcher.
+func New(conf config.NewsletterFetcher, db *gorm.DB, fk *faktory_worker.Manager) *NewsletterFetcher {
+	nf := &NewsletterFetcher{
+		conf: conf,
+	}
+	nf.Worker = basemodelworker.Worker{
+		Name:        "NewsletterFetcher",
+		DB:          db,
+		FK:          fk,
+		Log:         log.Logger.Level(zerolog.Level(conf.LogLevel)),
+		Concurrency: conf.Concurrency,
+		Queues:      conf.Queues,
+		Perform:     nf.perform,
+	}
+	return nf
+}
+
+// rawMessage is a message read from the IMAP folder.
+type rawMessage struct {
+	uid     uint32
+	content []byte
+}
+
+func (nf *NewsletterFetcher) perform(ctx context.Context, newsletterSourceID uint) error {
+	tx := nf.DB.WithContext(ctx)
+
+	src, err := getNewsletterSource(tx, newsletterSourceID)
+	if err != nil {
+		return err
+	}
+	if !src.Enabled {
+		nf.Log.Warn().Msgf("skipping NewsletterSource %d: not enabled", src.ID)
+		return nil
+	}
+
+	var linkPattern *regexp.Regexp
+	if src.Mode == models.LinksNewsletterMode && len(src.LinkPattern) > 0 {
+		linkPattern, err = regexp.Compile(src.LinkPattern)
+		if err != nil {
+			err = fmt.Errorf("invalid link pattern: %w", err)
+			nf.Log.Err(err).Msgf("error reading NewsletterSource %d", src.ID)
+			return nf.markSourceWithError(tx, src, err)
+		}
+	}
+
+	uidValidity, messages, err := nf.fetchMessages(ctx, src)
+	if err != nil {
+		nf.Log.Err(err).Msgf("error reading NewsletterSource %d", src.ID)
+		return nf.markSourceWithError(tx, src, err)
+	}
+
+	js := jobscheduler.New()
+	err = tx.Transaction(func(tx *gorm.DB) error {
+		for _, rm := range messages {
+			err := nf.processMessage(tx, src, rm, linkPattern, js)
+			if err != nil {
+				return fmt.Errorf("error processing newsletter message: %w", err)
+			}
+			src.LastUID = int64(rm.uid)
+		}
+		src.UIDValidity = int64(uidValidity)
+
+		err = nf.resetSourceErrors(tx, src)
+		if err != nil {
+			return err
+		}
+
+		return js.CreatePendingJobs(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	return js.PushJobsAndDeletePendingJobs(ctx, nf.DB)
+}
+
+func getNewsletterSource(tx *gorm.DB, nsID uint) (*models.NewsletterSource, error) {
+	var src *models.NewsletterSource
+	res := tx.First(&src, nsID)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error fetching NewsletterSource %d: %w", nsID, res.Error)
+	}
+	return src, nil
+}
+
+// fetchMessages reads from the source folder the oldest messages which
+// were not read yet, up to the configured maximum number. If the
+// UIDValidity of the folder has changed, all the messages are considered
+// unread: the ones already processed are then recognized by Message-ID.
+func (nf *NewsletterFetcher) fetchMessages(ctx context.Context, src *models.NewsletterSource) (uint32, []rawMessage, error) {
+	addr := net.JoinHostPort(src.Host, strconv.Itoa(src.Port))
+	c, err := imapclient.Dial(ctx, addr, !src.DisableTLS, nf.conf.RequestTimeout)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			nf.Log.Debug().Err(err).Msg("error closing IMAP connection")
+		}
+	}()
+
+	err = c.Login(src.Username, src.Password)
+	if err != nil {
+		return 0, nil, err
+	}
+	mb, err := c.Examine(src.Folder)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	afterUID := uint32(src.LastUID)
+	if int64(mb.UIDValidity) != src.UIDValidity {
+		if src.UIDValidity != 0 {
+			nf.Log.Warn().Uint("NewsletterSource", src.ID).Msg("UIDVALIDITY has changed: reading all messages again")
+		}
+		afterUID = 0
+	}
+
+	uids, err := c.SearchUIDs(afterUID, src.SenderFilter)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(uids) > nf.conf.MaxMessagesNumber {
+		uids = uids[:nf.conf.MaxMessagesNumber]
+	}
+
+	messages := make([]rawMessage, 0, len(uids))
+	for _, uid := range uids {
+		content, err := c.FetchMessage(uid)
+		if err != nil {
+			return 0, nil, err
+		}
+		messages = append(messages, rawMessage{uid: uid, content: content})
+	}
+
+	err = c.Logout()
+	if err != nil {
+		return 0, nil, err
+	}
+	return mb.UIDValidity, messages, nil
+}
+
+func (nf *NewsletterFetcher) processMessage(
+	tx *gorm.DB,
+	src *models.NewsletterSource,
+	rm rawMessage,
+	linkPattern *regexp.Regexp,
+	js *jobscheduler.JobScheduler,
+) error {
+	logger := nf.Log.With().Uint("NewsletterSource", src.ID).Uint32("UID", rm.uid).Logger()
+
+	msg, err := newsletter.Parse(rm.content)
+	if err != nil {
+		logger.Warn().Err(err).Msg("invalid message")
+		return nil
+	}
+	logger = logger.With().Str("MessageID", msg.MessageID).Logger()
+
+	publishedAt := msg.Date
+	if publishedAt.IsZero() {
+		publishedAt = time.Now().UTC()
+	}
+	if nf.messageIsTooOld(publishedAt) {
+		logger.Debug().Time("PublishedAt", publishedAt).Msg("the message is too old")
+		return nil
+	}
+
+	nm := &models.NewsletterMessage{
+		NewsletterSourceID: src.ID,
+		MessageID:          msg.MessageID,
+		UID:                int64(rm.uid),
+		Subject:            msg.Subject,
+		FromAddress:        msg.From,
+		FromName:           msg.FromName,
+		PublishedAt:        publishedAt,
+	}
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(nm)
+	if res.Error != nil {
+		return fmt.Errorf("error creating NewsletterMessage: %w", res.Error)
+	}
+	if nm.ID == 0 {
+		logger.Debug().Msg("the message was already processed")
+		return nil
+	}
+	logger = logger.With().Uint("NewsletterMessage", nm.ID).Logger()
+
+	switch src.Mode {
+	case models.ArticleNewsletterMode:
+		return nf.createWebArticle(tx, logger, src, nm, msg, js)
+	case models.LinksNewsletterMode:
+		return nf.followLinks(tx, logger, nm, msg, linkPattern, js)
+	default:
+		return fmt.Errorf("unexpected newsletter-source mode %#v", src.Mode)
+	}
+}
+
+// createWebArticle creates a new WebResource and WebArticle from the
+// message, and associates the WebResource with the NewsletterMessage.
+//
+// The URL of the WebResource is the "mid" URL of the message (RFC 2392).
+func (nf *NewsletterFetcher) createWebArticle(
+	tx *gorm.DB,
+	logger zerolog.Logger,
+	src *models.NewsletterSource,
+	nm *models.NewsletterMessage,
+	msg *newsletter.Message,
+	js *jobscheduler.JobScheduler,
+) error {
+	if len(msg.Text) == 0 {
+		logger.Debug().Msg("the message has no text")
+		return nil
+	}
+
+	lang, langOk := messageLanguage(src, msg)
+	if !langOk {
+		logger.Warn().Str("Subject", msg.Subject).Msg("failed to detect language")
+		return nil
+	}
+	if !nf.languageIsAllowed(lang) {
+		logger.Debug().Str("Subject", msg.Subject).Str("Lang", lang).Msg("language is not allowed")
+		return nil
+	}
+
+	wr := &models.WebResource{URL: "mid:" + url.PathEscape(msg.MessageID)}
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(wr)
+	if res.Error != nil {
+		return fmt.Errorf("error creating WebResource: %w", res.Error)
+	}
+	if wr.ID == 0 {
+		// For example, the same message was read from another source.
+		logger.Debug().Str("URL", wr.URL).Msg("a WebResource already exists")
+		return nil
+	}
+
+	wa, err := newWebArticle(wr.ID, nm, msg, lang)
+	if err != nil {
+		return err
+	}
+	res = tx.Create(wa)
+	if res.Error != nil {
+		return fmt.Errorf("error creating WebArticle: %w", res.Error)
+	}
+
+	nm.WebResourceID = &wr.ID
+	res = tx.Model(nm).Update("web_resource_id", wr.ID)
+	if res.Error != nil {
+		return fmt.Errorf("error updating NewsletterMessage: %w", res.Error)
+	}
+
+	err = stagenotifier.Notify(tx, stagenotifier.NewsletterFetcher, wa.ID)
+	if err != nil {
+		return err
+	}
+	return js.AddJobs(nf.conf.NewWebArticleJobs, wa.ID)
+}
+
+// followLinks creates a new WebResource for each URL linked by the message
+// (and matching the link pattern, if any), unless it already exists, and
+// records the NewsletterMessageLink. Jobs are added only for the new
+// WebResources.
+func (nf *NewsletterFetcher) followLinks(
+	tx *gorm.DB,
+	logger zerolog.Logger,
+	nm *models.NewsletterMessage,
+	msg *newsletter.Message,
+	linkPattern *regexp.Regexp,
+	js *jobscheduler.JobScheduler,
+) error {
+	for _, link := range msg.Links {
+		if linkPattern != nil && !linkPattern.MatchString(link) {
+			continue
+		}
+
+		webResourceID, created, err := models.FindOrCreateWebResource(tx, link)
+		if err != nil {
+			return err
+		}
+		if created {
+			logger.Trace().Str("URL", link).Uint("WebResource", webResourceID).Msg("new linked WebResource")
+			err = js.AddJobs(nf.conf.NewWebResourceJobs, webResourceID)
+			if err != nil {
+				return err
+			}
+		}
+
+		ml := &models.NewsletterMessageLink{
+			NewsletterMessageID: nm.ID,
+			WebResourceID:       webResourceID,
+		}
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(ml)
+		if res.Error != nil {
+			return fmt.Errorf("error creating NewsletterMessageLink: %w", res.Error)
+		}
+	}
+	return nil
+}
+
+func newWebArticle(webResourceID uint, nm *models.NewsletterMessage, msg *newsletter.Message, language string) (*models.WebArticle, error) {
+	content := &models.WebArticleContent{
+		Body: msg.Text,
+	}
+	authors := make([]string, 0, 1)
+	if len(nm.FromName) > 0 {
+		authors = append(authors, nm.FromName)
+	}
+	err := content.Authors.Set(authors)
+	if err != nil {
+		return nil, fmt.Errorf("error setting WebArticleContent.Authors: %w", err)
+	}
+	err = content.Keywords.Set([]string{})
+	if err != nil {
+		return nil, fmt.Errorf("error setting WebArticleContent.Keywords: %w", err)
+	}
+
+	wa := &models.WebArticle{
+		WebResourceID: webResourceID,
+		Title:         msg.Subject,
+		Language:      language,
+		PublishDate:   nm.PublishedAt,
+		Content:       content,
+	}
+	if !msg.Date.IsZero() {
+		wa.ScrapedPublishDate = sql.NullTime{Valid: true, Time: msg.Date}
+	}
+	return wa, nil
+}
+
+// messageLanguage returns the language of the source, if it is set,
+// otherwise the language detected from the subject and text of the message.
+func messageLanguage(src *models.NewsletterSource, msg *newsletter.Message) (string, bool) {
+	if src.Language.Valid && len(src.Language.String) > 0 {
+		return src.Language.String, true
+	}
+	return languagerecognition.RecognizeLanguage(msg.Subject + "\n\n" + msg.Text)
+}
+
+func (nf *NewsletterFetcher) markSourceWithError(tx *gorm.DB, src *models.NewsletterSource, sourceErr error) error {
+	src.LastError = sql.NullString{Valid: true, String: sourceErr.Error()}
+	src.FailuresCount++
+	src.Enabled = src.FailuresCount <= nf.conf.MaxAllowedFailures
+
+	err := models.OptimisticSave(tx, src)
+	if err != nil {
+		return fmt.Errorf("error saving NewsletterSource (marked with error): %w", err)
+	}
+	return nil
+}
+
+func (nf *NewsletterFetcher) resetSourceErrors(tx *gorm.DB, src *models.NewsletterSource) error {
+	src.LastRetrievedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	src.LastError = sql.NullString{Valid: false, String: ""}
+	src.FailuresCount = 0
+
+	err := models.OptimisticSave(tx, src)
+	if err != nil {
+		return fmt.Errorf("error saving NewsletterSource (resetting errors): %w", err)
+	}
+	return nil
+}
+
+func (nf *NewsletterFetcher) messageIsTooOld(publishedAt time.Time) bool {
+	return nf.conf.OmitMessagesPublishedBefore.Enabled &&
+		publishedAt.Before(nf.conf.OmitMessagesPublishedBefore.Time)
+}
+
+func (nf *NewsletterFetcher) languageIsAllowed(lang string) bool {
+	for _, l := range nf.conf.LanguageFilter {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,352 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sitemapfetcher
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/languagerecognition"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/sitemap"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
+	"github.com/contribsys/faktory_worker_go"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSitemapSize is the maximum size of a sitemap document, as defined by
+// the sitemaps protocol.
+const maxSitemapSize = 50 * 1024 * 1024
+
+// SitemapFetcher implements a Faktory worker for fetching sitemaps and
+// creating new sitemap items.
+type SitemapFetcher struct {
+	basemodelworker.Worker
+	conf   config.SitemapFetcher
+	client *http.Client
+}
+
+// New creates a new SitemapFetcher.
+func New(conf config.SitemapFetcher, db *gorm.DB, fk *faktory_worker.Manager) *SitemapFetcher {
+	sf := &SitemapFetcher{
+		conf:   conf,
+		client: &http.Client{},
+	}
+	sf.Worker = basemodelworker.Worker{
+		Name:        "SitemapFetcher",
+		DB:          db,
+		FK:          fk,
+		Log:         log.Logger.Level(zerolog.Level(conf.LogLevel)),
+		Concurrency: conf.Concurrency,
+		Queues:      conf.Queues,
+		Perform:     sf.perform,
+	}
+	return sf
+}
+
+func (sf *SitemapFetcher) perform(ctx context.Context, sitemapID uint) error {
+	tx := sf.DB.WithContext(ctx)
+
+	sm, err := getSitemap(tx, sitemapID)
+	if err != nil {
+		return err
+	}
+	if !sm.Enabled {
+		sf.Log.Warn().Msgf("skipping sitemap %d: not enabled", sm.ID)
+		return nil
+	}
+
+	urls, err := sf.fetchURLs(ctx, sm)
+	if err != nil {
+		sf.Log.Warn().Err(err).Msgf("error fetching sitemap %d", sm.ID)
+		return sf.markSitemapWithError(tx, sm, err)
+	}
+
+	js := jobscheduler.New()
+	err = tx.Transaction(func(tx *gorm.DB) error {
+		err = sf.processSitemap(tx, sm, js, urls)
+		if err != nil {
+			return err
+		}
+		return js.CreatePendingJobs(tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	return js.PushJobsAndDeletePendingJobs(ctx, sf.DB)
+}
+
+func getSitemap(tx *gorm.DB, sitemapID uint) (*models.Sitemap, error) {
+	var sm *models.Sitemap
+	res := tx.First(&sm, sitemapID)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error fetching Sitemap %d: %w", sitemapID, res.Error)
+	}
+	return sm, nil
+}
+
+func (sf *SitemapFetcher) markSitemapWithError(tx *gorm.DB, sm *models.Sitemap, sitemapError error) error {
+	sm.LastError = sql.NullString{Valid: true, String: sitemapError.Error()}
+	sm.FailuresCount++
+	sm.Enabled = sm.FailuresCount <= sf.conf.MaxAllowedFailures
+
+	err := models.OptimisticSave(tx, sm)
+	if err != nil {
+		return fmt.Errorf("error saving sitemap (marked with error): %w", err)
+	}
+	return nil
+}
+
+// fetchURLs retrieves the URLs listed in the sitemap. If the sitemap is a
+// sitemap index, the URLs of the most recently modified sitemaps are
+// returned instead. A failure fetching one of those sitemaps is only
+// logged, so that the remaining ones are still processed.
+func (sf *SitemapFetcher) fetchURLs(ctx context.Context, sm *models.Sitemap) ([]sitemap.URL, error) {
+	parsed, err := sf.fetchSitemap(ctx, sm.URL)
+	if err != nil {
+		return nil, err
+	}
+	if !parsed.IsIndex() {
+		return parsed.URLs, nil
+	}
+
+	var urls []sitemap.URL
+	for _, entry := range sf.selectIndexEntries(parsed.Sitemaps) {
+		logger := sf.Log.With().Uint("Sitemap", sm.ID).Str("Loc", entry.Loc).Logger()
+
+		child, err := sf.fetchSitemap(ctx, entry.Loc)
+		if err != nil {
+			logger.Warn().Err(err).Msg("error fetching sitemap from index")
+			continue
+		}
+		if child.IsIndex() {
+			logger.Warn().Msg("nested sitemap indexes are not supported")
+			continue
+		}
+		urls = append(urls, child.URLs...)
+	}
+	return urls, nil
+}
+
+// selectIndexEntries returns at most MaxSitemaps entries of a sitemap index,
+// most recently modified first, excluding the sitemaps which were not
+// modified after the omission time.
+func (sf *SitemapFetcher) selectIndexEntries(entries []sitemap.Entry) []sitemap.Entry {
+	selected := make([]sitemap.Entry, 0, len(entries))
+	for _, e := range entries {
+		if !sf.isTooOld(e.LastMod) {
+			selected = append(selected, e)
+		}
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		a, b := selected[i].LastMod, selected[j].LastMod
+		return a != nil && (b == nil || a.After(*b))
+	})
+
+	if len(selected) > sf.conf.MaxSitemaps {
+		selected = selected[:sf.conf.MaxSitemaps]
+	}
+	return selected
+}
+
+func (sf *SitemapFetcher) fetchSitemap(ctx context.Context, url string) (_ *sitemap.Sitemap, err error) {
+	ctxTimeout, cancel := context.WithTimeout(ctx, sf.conf.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctxTimeout, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request for %#v: %w", url, err)
+	}
+	req.Header.Set("User-Agent", sf.conf.UserAgent)
+
+	resp, err := sf.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error performing request for %#v: %w", url, err)
+	}
+	defer func() {
+		if e := resp.Body.Close(); e != nil && err == nil {
+			err = fmt.Errorf("error closing response body: %w", e)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request for %#v returned status code %d", url, resp.StatusCode)
+	}
+
+	return sitemap.Parse(io.LimitReader(resp.Body, maxSitemapSize))
+}
+
+func (sf *SitemapFetcher) processSitemap(
+	tx *gorm.DB,
+	sm *models.Sitemap,
+	js *jobscheduler.JobScheduler,
+	urls []sitemap.URL,
+) error {
+	for _, u := range urls {
+		err := sf.processURL(tx, sm, u, js)
+		if err != nil {
+			return fmt.Errorf("error processing sitemap URL: %w", err)
+		}
+	}
+
+	sm.LastError = sql.NullString{Valid: false, String: ""}
+	sm.FailuresCount = 0
+	sm.LastRetrievedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	err := models.OptimisticSave(tx, sm)
+	if err != nil {
+		return fmt.Errorf("error updating processed Sitemap: %w", err)
+	}
+	return nil
+}
+
+func (sf *SitemapFetcher) processURL(
+	tx *gorm.DB,
+	sm *models.Sitemap,
+	u sitemap.URL,
+	js *jobscheduler.JobScheduler,
+) error {
+	logger := sf.Log.With().Uint("Sitemap", sm.ID).Str("Loc", u.Loc).Logger()
+
+	publishedAt := u.PublishedAt()
+	if sf.isTooOld(publishedAt) {
+		logger.Debug().Time("PublishedAt", *publishedAt).Msg("item is too old")
+		return nil
+	}
+
+	var title string
+	if u.News != nil {
+		title = u.News.Title
+	}
+
+	lang := itemLanguage(sm, u)
+	if len(lang) > 0 && !sf.languageIsAllowed(lang) {
+		logger.Debug().Str("Title", title).Str("Lang", lang).Msg("language is not allowed")
+		return nil
+	}
+
+	webResource, err := findWebResource(tx, u.Loc)
+	if err != nil {
+		return err
+	}
+
+	sitemapItem := &models.SitemapItem{
+		SitemapID:   sm.ID,
+		Title:       title,
+		Language:    lang,
+		PublishedAt: makeNullTime(publishedAt),
+	}
+
+	if webResource != nil {
+		logger = logger.With().Uint("WebResource", webResource.ID).Logger()
+
+		if webResource.SitemapItem != nil {
+			logger.Trace().Uint("SitemapItem", webResource.SitemapItem.ID).Msg("a SitemapItem already exists")
+			return nil
+		}
+
+		sitemapItem.WebResourceID = webResource.ID
+		return createSitemapItem(tx, logger, sitemapItem)
+	}
+
+	webResource = &models.WebResource{
+		URL:         u.Loc,
+		SitemapItem: sitemapItem,
+	}
+
+	res := tx.Create(webResource)
+	if database.IsUniqueViolationError(res.Error) {
+		logger.Warn().Err(res.Error).Msg("WebResource and SitemapItem creation constraint violation")
+		return nil
+	}
+	if res.Error != nil {
+		return fmt.Errorf("error creating WebResource: %w", res.Error)
+	}
+	return js.AddJobs(sf.conf.NewWebResourceJobs, webResource.ID)
+}
+
+func createSitemapItem(tx *gorm.DB, logger zerolog.Logger, si *models.SitemapItem) error {
+	res := tx.Create(si)
+	if database.IsUniqueViolationError(res.Error) {
+		logger.Warn().Err(res.Error).Msg("SitemapItem creation constraint violation")
+		return nil
+	}
+	if res.Error != nil {
+		return fmt.Errorf("error creating SitemapItem: %w", res.Error)
+	}
+	return nil
+}
+
+func findWebResource(tx *gorm.DB, url string) (*models.WebResource, error) {
+	var webResource *models.WebResource
+	result := tx.Joins("SitemapItem").Limit(1).Find(&webResource, "url = ?", url)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error fetching WebResource by URL %#v: %w", url, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return webResource, nil
+}
+
+// itemLanguage returns the language forced on the Sitemap, if any, or the
+// news publication language, or the language detected from the news title.
+// An empty string is returned if the language is unknown: it will be
+// detected later from the scraped article.
+func itemLanguage(sm *models.Sitemap, u sitemap.URL) string {
+	if sm.Language.Valid {
+		return sm.Language.String
+	}
+	if u.News == nil {
+		return ""
+	}
+	if lang := u.News.PublicationLanguage; len(lang) > 0 {
+		// News sitemaps might use regional codes, such as "zh-cn".
+		lang = strings.ToLower(lang)
+		if i := strings.IndexByte(lang, '-'); i > 0 {
+			lang = lang[:i]
+		}
+		return lang
+	}
+	if len(u.News.Title) > 0 {
+		if lang, ok := languagerecognition.RecognizeLanguage(u.News.Title); ok {
+			return lang
+		}
+	}
+	return ""
+}
+
+func (sf *SitemapFetcher) isTooOld(t *time.Time) bool {
+	return sf.conf.OmitItemsPublishedBefore.Enabled &&
+		t != nil &&
+		t.Before(sf.conf.OmitItemsPublishedBefore.Time)
+}
+
+func (sf *SitemapFetcher) languageIsAllowed(lang string) bool {
+	for _, l := range sf.conf.LanguageFilter {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
+func makeNullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{Valid: false}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
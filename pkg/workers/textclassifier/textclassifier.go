@@ -8,17 +8,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/articletext"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/grpcconn"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/textclassification"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
 	"github.com/contribsys/faktory_worker_go"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
-	"strings"
 )
 
 // TextClassifier implements a Faktory worker for classifying existing
@@ -31,6 +32,7 @@ type TextClassifier struct {
 	// The default value is DefaultShouldScheduleNextJobs.
 	ShouldScheduleNextJobs ShouldScheduleNextJobsFn
 	conf                   config.TextClassifier
+	textSelector           *articletext.Selector
 }
 
 // ShouldScheduleNextJobsFn is a function which returns a boolean flag
@@ -62,6 +64,7 @@ func New(
 	tc := &TextClassifier{
 		conf:                   conf,
 		ShouldScheduleNextJobs: DefaultShouldScheduleNextJobs,
+		textSelector:           articletext.NewSelector(conf.TextSelection),
 	}
 
 	tc.Worker = basemodelworker.Worker{
@@ -85,7 +88,7 @@ func (tc *TextClassifier) perform(ctx context.Context, webArticleID uint) error
 		return err
 	}
 
-	classes, err := tc.processWebArticle(ctx, wa)
+	classes, err := tc.processWebArticle(ctx, tx, wa)
 	if errors.Is(err, errSkip) {
 		return nil
 	}
@@ -102,6 +105,11 @@ func (tc *TextClassifier) perform(ctx context.Context, webArticleID uint) error
 			}
 		}
 
+		err := stagenotifier.Notify(tx, stagenotifier.TextClassifier, wa.ID)
+		if err != nil {
+			return err
+		}
+
 		wa.TextClasses = classes
 		shouldSchedule, err := tc.ShouldScheduleNextJobs(tx, wa)
 		if err != nil {
@@ -131,6 +139,7 @@ func getWebArticle(tx *gorm.DB, id uint) (*models.WebArticle, error) {
 
 func (tc *TextClassifier) processWebArticle(
 	ctx context.Context,
+	tx *gorm.DB,
 	wa *models.WebArticle,
 ) ([]models.TextClass, error) {
 	logger := tc.Log.With().Uint("WebArticle", wa.ID).Logger()
@@ -140,13 +149,12 @@ func (tc *TextClassifier) processWebArticle(
 		return nil, errSkip
 	}
 
-	title := strings.TrimSpace(wa.Title)
-	if wa.TranslatedTitle.Valid {
-		title = strings.TrimSpace(wa.TranslatedTitle.String)
+	text, err := tc.textSelector.Select(tx, wa)
+	if err != nil {
+		return nil, err
 	}
-
-	if len(title) == 0 {
-		logger.Debug().Msg("empty title - web article skipped")
+	if text.IsEmpty() {
+		logger.Debug().Msg("empty text - web article skipped")
 		return nil, errSkip
 	}
 
@@ -161,19 +169,38 @@ func (tc *TextClassifier) processWebArticle(
 	}()
 	classifierClient := textclassification.NewClassifierClient(classifierConn)
 
-	req := &textclassification.ClassifyTextRequest{Text: title}
-	reply, err := classifierClient.ClassifyText(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("ClassifyText request error: %w", err)
+	// If the text is split into more chunks, each chunk is classified
+	// separately, and the highest confidence of each class is kept.
+	type classKey struct{ Type, Label string }
+	var keys []classKey
+	confidences := make(map[classKey]float32)
+
+	for _, chunk := range text.Chunks {
+		req := &textclassification.ClassifyTextRequest{Text: chunk}
+		reply, err := classifierClient.ClassifyText(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("ClassifyText request error: %w", err)
+		}
+
+		for _, repClass := range reply.Classes {
+			key := classKey{Type: repClass.Type, Label: repClass.Label}
+			c, seen := confidences[key]
+			if !seen {
+				keys = append(keys, key)
+			}
+			if !seen || repClass.Confidence > c {
+				confidences[key] = repClass.Confidence
+			}
+		}
 	}
 
-	classes := make([]models.TextClass, len(reply.Classes))
-	for i, repClass := range reply.Classes {
+	classes := make([]models.TextClass, len(keys))
+	for i, key := range keys {
 		classes[i] = models.TextClass{
 			WebArticleID: wa.ID,
-			Type:         repClass.Type,
-			Label:        repClass.Label,
-			Confidence:   repClass.Confidence,
+			Type:         key.Type,
+			Label:        key.Label,
+			Confidence:   confidences[key],
 		}
 	}
 
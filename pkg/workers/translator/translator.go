@@ -9,17 +9,19 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	translatorapi "github.com/SpecializedGeneralist/translator/pkg/api"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/articletext"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
-	"github.com/SpecializedGeneralist/whatsnew/pkg/grpcconn"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/sets"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/translationprovider"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
 	"github.com/contribsys/faktory_worker_go"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"strings"
 )
 
@@ -29,25 +31,36 @@ type Translator struct {
 	basemodelworker.Worker
 	conf              config.Translator
 	languageWhitelist sets.StringSet
+	textSelector      *articletext.Selector
+	providers         *translationprovider.Router
 }
 
 // New creates a new Translator.
-func New(conf config.Translator, db *gorm.DB, fk *faktory_worker.Manager) *Translator {
+func New(conf config.Translator, db *gorm.DB, fk *faktory_worker.Manager) (*Translator, error) {
+	logger := log.Logger.Level(zerolog.Level(conf.LogLevel))
+
+	providers, err := translationprovider.NewRouter(conf.Providers, conf.LanguagePairs, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	t := &Translator{
 		conf:              conf,
 		languageWhitelist: sets.NewStringSetWithElements(conf.LanguageWhitelist...),
+		textSelector:      articletext.NewSelector(conf.TextSelection),
+		providers:         providers,
 	}
 
 	t.Worker = basemodelworker.Worker{
 		Name:        "Translator",
 		DB:          db,
 		FK:          fk,
-		Log:         log.Logger.Level(zerolog.Level(conf.LogLevel)),
+		Log:         logger,
 		Concurrency: conf.Concurrency,
 		Queues:      conf.Queues,
 		Perform:     t.perform,
 	}
-	return t
+	return t, nil
 }
 
 var errSkip = errors.New("skip")
@@ -60,7 +73,7 @@ func (t *Translator) perform(ctx context.Context, webArticleID uint) error {
 		return err
 	}
 
-	translationOk, err := t.processWebArticle(ctx, wa)
+	titleOk, translations, err := t.processWebArticle(ctx, tx, wa)
 	if errors.Is(err, errSkip) {
 		return nil
 	}
@@ -69,14 +82,37 @@ func (t *Translator) perform(ctx context.Context, webArticleID uint) error {
 	}
 
 	js := jobscheduler.New()
+	if len(translations) == 0 {
+		// Nothing was translated (for example, the language is not
+		// whitelisted): the following jobs are scheduled anyway, but there
+		// is no stage to notify.
+		err = tx.Transaction(func(tx *gorm.DB) error {
+			return js.AddJobsAndCreatePendingJobs(tx, t.conf.ProcessedWebArticleJobs, wa.ID)
+		})
+		if err != nil {
+			return err
+		}
+		return js.PushJobsAndDeletePendingJobs(ctx, t.DB)
+	}
+
 	err = tx.Transaction(func(tx *gorm.DB) error {
-		if translationOk {
+		if titleOk {
 			err := models.OptimisticSave(tx, wa)
 			if err != nil {
 				return fmt.Errorf("error saving WebArticle with translated title: %w", err)
 			}
 		}
 
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&translations)
+		if res.Error != nil {
+			return fmt.Errorf("error creating Translations: %w", res.Error)
+		}
+
+		err := stagenotifier.Notify(tx, stagenotifier.Translator, wa.ID)
+		if err != nil {
+			return err
+		}
+
 		return js.AddJobsAndCreatePendingJobs(tx, t.conf.ProcessedWebArticleJobs, wa.ID)
 	})
 	if err != nil {
@@ -95,66 +131,191 @@ func getWebArticle(tx *gorm.DB, id uint) (*models.WebArticle, error) {
 	return wa, nil
 }
 
+// A pendingTranslation is a text which has yet to be translated into a
+// target language.
+type pendingTranslation struct {
+	field          config.TextSource
+	isTitle        bool
+	segments       [][]string
+	sourceLanguage string
+	targetLanguage string
+	provider       translationprovider.TranslationProvider
+}
+
+// processWebArticle translates the text selected with TextSelection and
+// the configured fields into each target language, skipping the pairs of
+// field and language which are already translated.
+//
+// The title translation into the first target language, if any, is also
+// set to the WebArticle. The new translations are returned.
 func (t *Translator) processWebArticle(
 	ctx context.Context,
+	tx *gorm.DB,
 	wa *models.WebArticle,
-) (bool, error) {
+) (titleOk bool, translations []models.Translation, err error) {
 	logger := t.Log.With().Uint("WebArticle", wa.ID).Logger()
 
-	if wa.TranslatedTitle.Valid && wa.TranslationLanguage.Valid {
-		logger.Warn().Msg("this WebArticle already has a translated title")
-		return false, errSkip
+	title, err := t.textSelector.Select(tx, wa)
+	if err != nil {
+		return false, nil, err
+	}
+	fieldTexts, err := t.readFields(tx, wa, title.Source)
+	if err != nil {
+		return false, nil, err
+	}
+	if title.IsEmpty() && len(fieldTexts) == 0 {
+		logger.Debug().Msg("empty text - web article skipped")
+		return false, nil, errSkip
 	}
 
-	title := strings.TrimSpace(wa.Title)
-	if len(title) == 0 {
-		logger.Debug().Msg("empty title - web article skipped")
-		return false, errSkip
+	translated, err := getTranslatedPairs(tx, wa)
+	if err != nil {
+		return false, nil, err
+	}
+	if !title.IsEmpty() && wa.TranslatedTitle.Valid && wa.TranslationLanguage.Valid {
+		translated.Add(pairKey(title.Source, wa.TranslationLanguage.String))
 	}
 
-	if !t.languageWhitelist.Has(wa.Language) {
-		return false, nil
+	pending := make([]pendingTranslation, 0)
+	alreadyTranslated := false
+	addPending := func(field config.TextSource, isTitle bool, segments [][]string, sourceLanguage string) {
+		if !t.languageWhitelist.Has(sourceLanguage) {
+			return
+		}
+		for _, targetLanguage := range t.conf.TargetLanguages {
+			if targetLanguage == sourceLanguage {
+				continue
+			}
+			if translated.Has(pairKey(field, targetLanguage)) {
+				alreadyTranslated = true
+				continue
+			}
+			provider := t.providers.Provider(sourceLanguage, targetLanguage)
+			if provider == nil {
+				logger.Warn().Msgf("no translation provider for %#v -> %#v", sourceLanguage, targetLanguage)
+				continue
+			}
+			pending = append(pending, pendingTranslation{
+				field:          field,
+				isTitle:        isTitle,
+				segments:       segments,
+				sourceLanguage: sourceLanguage,
+				targetLanguage: targetLanguage,
+				provider:       provider,
+			})
+		}
 	}
 
-	err := t.translateTitle(ctx, wa, title)
-	if err != nil {
-		return false, err
+	if !title.IsEmpty() {
+		addPending(title.Source, true, [][]string{title.Chunks}, title.Language)
 	}
-	return true, nil
+	for _, ft := range fieldTexts {
+		addPending(ft.field, false, articletext.Segment(ft.text, t.conf.MaxSegmentLength), ft.language)
+	}
+
+	if len(pending) == 0 {
+		if alreadyTranslated {
+			logger.Warn().Msg("this WebArticle is already translated")
+			return false, nil, errSkip
+		}
+		return false, nil, nil
+	}
+
+	translations = make([]models.Translation, 0, len(pending))
+	for _, p := range pending {
+		translatedText, err := translateSegments(ctx, p)
+		if err != nil {
+			return false, nil, fmt.Errorf("error translating %s into %#v: %w", p.field, p.targetLanguage, err)
+		}
+		translations = append(translations, models.Translation{
+			WebArticleID: wa.ID,
+			Field:        string(p.field),
+			Language:     p.targetLanguage,
+			Text:         translatedText,
+			Provider:     p.provider.Name(),
+		})
+
+		if p.isTitle && p.targetLanguage == t.conf.TargetLanguages[0] {
+			wa.TranslatedTitle = sql.NullString{String: translatedText, Valid: true}
+			wa.TranslationLanguage = sql.NullString{String: p.targetLanguage, Valid: true}
+			titleOk = true
+		}
+	}
+
+	return titleOk, translations, nil
 }
 
-func (t *Translator) translateTitle(ctx context.Context, wa *models.WebArticle, title string) error {
-	translatorConn, err := grpcconn.Dial(ctx, t.conf.TranslatorServer)
-	if err != nil {
-		return err
+// getTranslatedPairs returns the set of field and language pairs (see
+// pairKey) which are already translated for the given WebArticle.
+func getTranslatedPairs(tx *gorm.DB, wa *models.WebArticle) (sets.StringSet, error) {
+	var existing []models.Translation
+	res := tx.Select("field", "language").Find(&existing, "web_article_id = ?", wa.ID)
+	if res.Error != nil {
+		return nil, fmt.Errorf("error fetching Translations of WebArticle %d: %w", wa.ID, res.Error)
 	}
-	defer func() {
-		if err := translatorConn.Close(); err != nil {
-			t.Log.Err(err).Msg("error closing translator connection")
+
+	pairs := sets.NewStringSet()
+	for _, tr := range existing {
+		pairs.Add(pairKey(config.TextSource(tr.Field), tr.Language))
+	}
+	return pairs, nil
+}
+
+func pairKey(field config.TextSource, language string) string {
+	return fmt.Sprintf("%s:%s", field, language)
+}
+
+type fieldText struct {
+	field    config.TextSource
+	text     string
+	language string
+}
+
+// readFields reads the text of each configured field, omitting the empty
+// ones and the one already selected as title.
+func (t *Translator) readFields(tx *gorm.DB, wa *models.WebArticle, titleSource config.TextSource) ([]fieldText, error) {
+	r := articletext.NewReader(tx, wa)
+	fieldTexts := make([]fieldText, 0, len(t.conf.Fields))
+	for _, field := range t.conf.Fields {
+		if field == titleSource {
+			continue
 		}
-	}()
-	translatorClient := translatorapi.NewApiClient(translatorConn)
-
-	resp, err := translatorClient.TranslateText(ctx, &translatorapi.TranslateTextRequest{
-		TranslateTextInput: &translatorapi.TranslateTextInput{
-			SourceLanguage: wa.Language,
-			TargetLanguage: t.conf.TargetLanguage,
-			Text:           title,
-		},
-	})
+		text, lang, err := r.Read(field)
+		if err != nil {
+			return nil, err
+		}
+		if len(strings.TrimSpace(text)) == 0 {
+			continue
+		}
+		fieldTexts = append(fieldTexts, fieldText{field: field, text: text, language: lang})
+	}
+	return fieldTexts, nil
+}
+
+// translateSegments translates all segments of the pending translation
+// (see articletext.Segment), and puts the translations back together.
+func translateSegments(ctx context.Context, p pendingTranslation) (string, error) {
+	texts := make([]string, 0)
+	for _, segments := range p.segments {
+		texts = append(texts, segments...)
+	}
+
+	translatedTexts, err := p.provider.Translate(ctx, texts, p.sourceLanguage, p.targetLanguage)
 	if err != nil {
-		return fmt.Errorf("TranslateText error: %w", err)
+		return "", err
 	}
-	if resp.Errors != nil && len(resp.Errors.Value) > 0 {
-		return fmt.Errorf("TranslateText responded with errors; first message: %s", resp.Errors.Value[0].Message)
+	if len(translatedTexts) != len(texts) {
+		return "", fmt.Errorf("expected %d translations, got %d", len(texts), len(translatedTexts))
 	}
 
-	translatedTitle := strings.TrimSpace(resp.Data.TranslatedText)
-	if len(translatedTitle) == 0 {
-		return fmt.Errorf("the title translation is empty")
+	translatedParagraphs := make([][]string, len(p.segments))
+	for i, segments := range p.segments {
+		translatedParagraphs[i], translatedTexts = translatedTexts[:len(segments)], translatedTexts[len(segments):]
 	}
 
-	wa.TranslatedTitle = sql.NullString{String: translatedTitle, Valid: true}
-	wa.TranslationLanguage = sql.NullString{String: t.conf.TargetLanguage, Valid: true}
-	return nil
+	translatedText := strings.TrimSpace(articletext.JoinSegments(translatedParagraphs))
+	if len(translatedText) == 0 {
+		return "", fmt.Errorf("the translation is empty")
+	}
+	return translatedText, nil
 }
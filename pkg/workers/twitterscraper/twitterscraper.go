@@ -13,12 +13,16 @@ import (
 	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/languagerecognition"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
 	"github.com/contribsys/faktory_worker_go"
 	"github.com/n0madic/twitter-scraper"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -33,8 +37,10 @@ type TwitterScraper struct {
 // New creates a new TwitterScraper.
 func New(conf config.TwitterScraper, db *gorm.DB, fk *faktory_worker.Manager) *TwitterScraper {
 	ts := &TwitterScraper{
-		conf:    conf,
-		scraper: twitterscraper.New(),
+		conf: conf,
+		// Search results are read from the most recent, so that the
+		// scraping can stop at the high-water mark of each source.
+		scraper: twitterscraper.New().SetSearchMode(twitterscraper.SearchLatest),
 	}
 	ts.Worker = basemodelworker.Worker{
 		Name:        "TwitterScraper",
@@ -91,33 +97,89 @@ func (ts *TwitterScraper) processTwitterSource(
 	src *models.TwitterSource,
 	js *jobscheduler.JobScheduler,
 ) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	var ch <-chan *twitterscraper.TweetResult
 
 	switch src.Type {
 	case models.UserTwitterSource:
 		ch = ts.scraper.GetTweets(ctx, src.Text, ts.conf.MaxTweetsNumber)
 	case models.SearchTwitterSource:
-		ch = ts.scraper.SearchTweets(ctx, src.Text, ts.conf.MaxTweetsNumber)
+		ch = ts.scraper.SearchTweets(ctx, searchQuery(src), ts.conf.MaxTweetsNumber)
 	default:
 		err := fmt.Errorf("unexpected twitter-source type %#v", src.Type)
 		ts.Log.Err(err).Msgf("error reading TwitterSource %d", src.ID)
 		return ts.markSourceWithError(tx, src, err)
 	}
 
+	// The stream is drained on return, so that the scraper goroutine can
+	// terminate after the cancellation of the context.
+	defer func() {
+		cancel()
+		for range ch {
+		}
+	}()
+
+	newest := twitterscraper.Tweet{ID: src.LastTweetUpstreamID.String}
+	tweetsCount := 0
+
 	for tr := range ch {
 		if tr.Error != nil {
 			ts.Log.Err(tr.Error).Msgf("error reading TwitterSource %d results", src.ID)
 			return ts.markSourceWithError(tx, src, tr.Error)
 		}
+
+		if src.LastTweetUpstreamID.Valid && !tr.IsPin &&
+			compareTweetIDs(tr.ID, src.LastTweetUpstreamID.String) <= 0 {
+			ts.Log.Debug().Msgf("TwitterSource %d: high-water mark reached after %d new tweets", src.ID, tweetsCount)
+			break
+		}
+		tweetsCount++
+		if compareTweetIDs(tr.ID, newest.ID) > 0 {
+			newest = tr.Tweet
+		}
+
 		err := ts.processTweet(tx, src, tr.Tweet, js)
 		if err != nil {
 			return fmt.Errorf("error processing tweet result: %w", err)
 		}
 	}
 
+	if newest.ID != src.LastTweetUpstreamID.String {
+		src.LastTweetUpstreamID = sql.NullString{Valid: true, String: newest.ID}
+		src.LastTweetPublishedAt = sql.NullTime{Valid: true, Time: newest.TimeParsed}
+	}
 	return ts.resetSourceErrors(tx, src)
 }
 
+// searchQuery returns the search query of a TwitterSource. When the source
+// has a high-water mark, the query is restricted to newer tweets. The
+// original query is parenthesized, since AND takes precedence over OR:
+// "a OR b since_id:X" would only restrict "b".
+func searchQuery(src *models.TwitterSource) string {
+	if !src.LastTweetUpstreamID.Valid {
+		return src.Text
+	}
+	return fmt.Sprintf("(%s) since_id:%s", src.Text, src.LastTweetUpstreamID.String)
+}
+
+// compareTweetIDs compares two tweet IDs, returning -1, 0 or +1. Tweet IDs
+// are increasing numbers, so a greater ID identifies a more recent tweet.
+// An empty ID precedes any other one.
+func compareTweetIDs(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
 func (ts *TwitterScraper) processTweet(
 	tx *gorm.DB,
 	src *models.TwitterSource,
@@ -160,6 +222,10 @@ func (ts *TwitterScraper) processTweet(
 			if err != nil {
 				return err
 			}
+			err = ts.followLinks(tx, logger, tweet, scrapedTweet, js)
+			if err != nil {
+				return err
+			}
 		}
 
 		if webResource.WebArticle != nil {
@@ -172,6 +238,13 @@ func (ts *TwitterScraper) processTweet(
 		if err != nil {
 			return err
 		}
+		if webArticle.ID == 0 {
+			return nil // not created, because of constraint violation
+		}
+		err = stagenotifier.Notify(tx, stagenotifier.TwitterScraper, webArticle.ID)
+		if err != nil {
+			return err
+		}
 		return js.AddJobs(ts.conf.NewWebArticleJobs, webArticle.ID)
 	}
 
@@ -189,9 +262,80 @@ func (ts *TwitterScraper) processTweet(
 	if res.Error != nil {
 		return fmt.Errorf("error creating WebResource: %w", res.Error)
 	}
+	err = ts.followLinks(tx, logger, webResource.Tweet, scrapedTweet, js)
+	if err != nil {
+		return err
+	}
+	err = stagenotifier.Notify(tx, stagenotifier.TwitterScraper, webResource.WebArticle.ID)
+	if err != nil {
+		return err
+	}
 	return js.AddJobs(ts.conf.NewWebArticleJobs, webResource.WebArticle.ID)
 }
 
+// followLinks creates a new WebResource for each URL linked by a newly
+// created tweet, unless it already exists, and records the TweetLink.
+// Jobs are added only for the new WebResources. Links to Twitter itself
+// (such as quoted tweets) are ignored.
+func (ts *TwitterScraper) followLinks(
+	tx *gorm.DB,
+	logger zerolog.Logger,
+	tweet *models.Tweet,
+	scrapedTweet twitterscraper.Tweet,
+	js *jobscheduler.JobScheduler,
+) error {
+	if !ts.conf.FollowLinks || tweet.ID == 0 {
+		return nil
+	}
+
+	for _, link := range linkedURLs(scrapedTweet.URLs) {
+		webResourceID, created, err := models.FindOrCreateWebResource(tx, link)
+		if err != nil {
+			return err
+		}
+		if created {
+			logger.Trace().Str("URL", link).Uint("WebResource", webResourceID).Msg("new linked WebResource")
+			err = js.AddJobs(ts.conf.NewWebResourceJobs, webResourceID)
+			if err != nil {
+				return err
+			}
+		}
+
+		tl := &models.TweetLink{
+			TweetID:       tweet.ID,
+			WebResourceID: webResourceID,
+		}
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(tl)
+		if res.Error != nil {
+			return fmt.Errorf("error creating TweetLink: %w", res.Error)
+		}
+	}
+	return nil
+}
+
+// linkedURLs returns the distinct HTTP(S) URLs, excluding those of Twitter.
+func linkedURLs(urls []string) []string {
+	result := make([]string, 0, len(urls))
+	seen := make(map[string]struct{}, len(urls))
+	for _, rawURL := range urls {
+		u, err := url.Parse(strings.TrimSpace(rawURL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		if host == "t.co" || host == "twitter.com" || strings.HasSuffix(host, ".twitter.com") {
+			continue
+		}
+		s := u.String()
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		result = append(result, s)
+	}
+	return result
+}
+
 func newTweet(src *models.TwitterSource, scrapedTweet twitterscraper.Tweet) *models.Tweet {
 	return &models.Tweet{
 		TwitterSourceID: src.ID,
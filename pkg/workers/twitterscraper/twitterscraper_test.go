@@ -0,0 +1,64 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package twitterscraper
+
+import (
+	"database/sql"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSearchQuery(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		text   string
+		lastID sql.NullString
+		want   string
+	}{
+		{"no high-water mark", "golang", sql.NullString{}, "golang"},
+		{"high-water mark", "golang", sql.NullString{String: "123", Valid: true}, "(golang) since_id:123"},
+		{"OR query", "a OR b", sql.NullString{String: "123", Valid: true}, "(a OR b) since_id:123"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			src := &models.TwitterSource{
+				Type:                models.SearchTwitterSource,
+				Text:                tc.text,
+				LastTweetUpstreamID: tc.lastID,
+			}
+			assert.Equal(t, tc.want, searchQuery(src))
+		})
+	}
+}
+
+func TestCompareTweetIDs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"123", "123", 0},
+		{"122", "123", -1},
+		{"124", "123", 1},
+		{"99", "100", -1},
+		{"1000", "999", 1},
+		{"1460000000000000000", "999999999999999999", 1},
+		{"00123", "123", 0},
+		{"0099", "100", -1},
+		{"100", "0099", 1},
+		{"", "1", -1},
+		{"1", "", 1},
+		{"", "", 0},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, compareTweetIDs(tc.a, tc.b), "%#v vs %#v", tc.a, tc.b)
+	}
+}
@@ -8,29 +8,29 @@ import (
 	"context"
 	"fmt"
 	hnswpb "github.com/SpecializedGeneralist/hnsw-grpc-server/pkg/grpcapi"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/articletext"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/bertencoder"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/grpcconn"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/hnswclient"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
 	"github.com/contribsys/faktory_worker_go"
 	"github.com/jackc/pgtype"
-	"github.com/nlpodyssey/spago/pkg/mat32"
-	bertgrpcapi "github.com/nlpodyssey/spago/pkg/nlp/transformers/bert/grpcapi"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
-	"strings"
 )
 
 // Vectorizer implements a Faktory worker for creating and storing a vector
-// representation of WebArticles' titles.
+// representation of WebArticles' texts.
 type Vectorizer struct {
 	basemodelworker.Worker
-	conf     config.Vectorizer
-	hnswConf config.HNSW
-	bertgrpcapi.BERTClient
+	conf         config.Vectorizer
+	hnswConf     config.HNSW
+	textSelector *articletext.Selector
 }
 
 // New creates a new WebScraper.
@@ -41,8 +41,9 @@ func New(
 	fk *faktory_worker.Manager,
 ) *Vectorizer {
 	v := &Vectorizer{
-		conf:     conf,
-		hnswConf: hnswConf,
+		conf:         conf,
+		hnswConf:     hnswConf,
+		textSelector: articletext.NewSelector(conf.TextSelection),
 	}
 	v.Worker = basemodelworker.Worker{
 		Name:        "Vectorizer",
@@ -64,7 +65,7 @@ func (v *Vectorizer) perform(ctx context.Context, webArticleID uint) error {
 		return err
 	}
 
-	vecModel, err := v.processWebArticle(ctx, wa)
+	vecModel, err := v.processWebArticle(ctx, tx, wa)
 	if err != nil {
 		return err
 	}
@@ -79,6 +80,11 @@ func (v *Vectorizer) perform(ctx context.Context, webArticleID uint) error {
 			return fmt.Errorf("error saving Vector: %w", res.Error)
 		}
 
+		err := stagenotifier.Notify(tx, stagenotifier.Vectorizer, wa.ID)
+		if err != nil {
+			return err
+		}
+
 		return js.AddJobsAndCreatePendingJobs(tx, v.conf.VectorizedWebArticleJobs, wa.ID)
 	})
 	if err != nil {
@@ -99,6 +105,7 @@ func getWebArticle(tx *gorm.DB, id uint) (*models.WebArticle, error) {
 
 func (v *Vectorizer) processWebArticle(
 	ctx context.Context,
+	tx *gorm.DB,
 	wa *models.WebArticle,
 ) (*models.Vector, error) {
 	logger := v.Log.With().Uint("WebArticle", wa.ID).Logger()
@@ -108,13 +115,12 @@ func (v *Vectorizer) processWebArticle(
 		return nil, nil
 	}
 
-	title := strings.TrimSpace(wa.Title)
-	if wa.TranslatedTitle.Valid {
-		title = strings.TrimSpace(wa.TranslatedTitle.String)
+	text, err := v.textSelector.Select(tx, wa)
+	if err != nil {
+		return nil, err
 	}
-
-	if len(title) == 0 {
-		logger.Debug().Msg("empty title - web article skipped")
+	if text.IsEmpty() {
+		logger.Debug().Msg("empty text - web article skipped")
 		return nil, nil
 	}
 
@@ -129,7 +135,7 @@ func (v *Vectorizer) processWebArticle(
 	}()
 	hnswClient := hnswclient.New(hnswpb.NewServerClient(hnswConn), v.hnswConf.Index)
 
-	vector, err := v.vectorize(ctx, title)
+	vector, err := bertencoder.New(v.conf.SpagoBERTServer, v.Log).EncodeChunks(ctx, text.Chunks)
 	if err != nil {
 		return nil, err
 	}
@@ -150,33 +156,3 @@ func (v *Vectorizer) processWebArticle(
 
 	return vectorModel, nil
 }
-
-// vectorize returns a dense vector representation of the given text.
-// It is expected to work well with models such as LaBSE (Language-agnostic
-// BERT Sentence Embedding).
-//
-// It simply calls the remote BERT Encode method to get a vector, which is
-// then normalized and returned.
-func (v *Vectorizer) vectorize(ctx context.Context, text string) ([]float32, error) {
-	bertConn, err := grpcconn.Dial(ctx, v.conf.SpagoBERTServer)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if err := bertConn.Close(); err != nil {
-			v.Log.Err(err).Msg("error closing BERT connection")
-		}
-	}()
-	bertClient := bertgrpcapi.NewBERTClient(bertConn)
-
-	request := &bertgrpcapi.EncodeRequest{Text: text}
-	encoding, err := bertClient.Encode(ctx, request)
-	if err != nil {
-		return nil, fmt.Errorf("BERT encoding error: %w", err)
-	}
-	return normalize(encoding.Vector), nil
-}
-
-func normalize(xs []float32) []float32 {
-	return mat32.NewVecDense(xs).Normalize2().Data()
-}
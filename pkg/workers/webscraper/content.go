@@ -0,0 +1,105 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webscraper
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/sets"
+	"strings"
+)
+
+// authorsMetaSelectors are the selectors of the HTML meta tags which
+// usually provide the authors of an article.
+var authorsMetaSelectors = []string{
+	`meta[name="author"]`,
+	`meta[property="article:author"]`,
+	`meta[name="article:author"]`,
+	`meta[name="byl"]`,
+	`meta[name="dc.creator"]`,
+	`meta[name="DC.creator"]`,
+	`meta[name="parsely-author"]`,
+	`meta[name="sailthru.author"]`,
+}
+
+func newWebArticleContent(ex *extraction) (*models.WebArticleContent, error) {
+	article := ex.article
+	c := &models.WebArticleContent{
+		Body:               strings.TrimSpace(article.CleanedText),
+		MetaDescription:    makeNullString(article.MetaDescription),
+		CanonicalLink:      makeNullString(article.CanonicalLink),
+		ExtractionStrategy: ex.strategy,
+	}
+
+	authors := ex.authors
+	if len(authors) == 0 {
+		authors = extractAuthors(article.Doc)
+	}
+	err := c.Authors.Set(authors)
+	if err != nil {
+		return nil, fmt.Errorf("error setting WebArticleContent.Authors: %w", err)
+	}
+	err = c.Keywords.Set(splitKeywords(article.MetaKeywords))
+	if err != nil {
+		return nil, fmt.Errorf("error setting WebArticleContent.Keywords: %w", err)
+	}
+	return c, nil
+}
+
+// extractAuthors looks for the authors in the meta tags of the document.
+// Values which look like URLs (e.g. social network profiles) are ignored.
+func extractAuthors(doc *goquery.Document) []string {
+	authors := make([]string, 0)
+	if doc == nil {
+		return authors
+	}
+
+	seen := sets.NewStringSet()
+	for _, selector := range authorsMetaSelectors {
+		doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
+			content := strings.TrimSpace(s.AttrOr("content", ""))
+			content = strings.TrimSpace(trimPrefixFold(content, "by "))
+			if len(content) == 0 || strings.HasPrefix(content, "http://") || strings.HasPrefix(content, "https://") {
+				return
+			}
+			if seen.Has(content) {
+				return
+			}
+			seen.Add(content)
+			authors = append(authors, content)
+		})
+	}
+	return authors
+}
+
+// splitKeywords splits the comma-separated keywords from the HTML meta
+// tag, removing empty and duplicate values.
+func splitKeywords(metaKeywords string) []string {
+	keywords := make([]string, 0)
+	seen := sets.NewStringSet()
+	for _, k := range strings.Split(metaKeywords, ",") {
+		k = strings.TrimSpace(k)
+		if len(k) == 0 || seen.Has(k) {
+			continue
+		}
+		seen.Add(k)
+		keywords = append(keywords, k)
+	}
+	return keywords
+}
+
+func trimPrefixFold(s, prefix string) string {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):]
+	}
+	return s
+}
+
+func makeNullString(s string) sql.NullString {
+	s = strings.TrimSpace(s)
+	return sql.NullString{String: s, Valid: len(s) > 0}
+}
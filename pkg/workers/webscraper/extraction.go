@@ -0,0 +1,128 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webscraper
+
+import (
+	"context"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/articlemeta"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	goose "github.com/advancedlogic/GoOse"
+	"github.com/rs/zerolog"
+)
+
+// extraction is an article extracted from a web page.
+type extraction struct {
+	article  *goose.Article
+	strategy models.ExtractionStrategy
+	// authors are the authors found in the structured data of the page, if
+	// any, which are preferred over the ones from the meta tags.
+	authors []string
+}
+
+// extractArticle extracts the article from the content of a web page. The
+// doc is the same content, parsed without any cleaning.
+//
+// GoOse is tried first. If it finds no title, which is common on pages
+// built with JavaScript, the following strategies are tried in order: the
+// AMP version of the page, its JSON-LD Article data, and its OpenGraph and
+// Twitter Card meta tags. As a last resort, the title of the feed item of
+// the WebResource is used, together with the content found by GoOse. In any
+// case, the structured data of the page complement the values which were
+// not found.
+//
+// It returns nil if no strategy succeeds.
+func (ws *WebScraper) extractArticle(
+	ctx context.Context,
+	logger zerolog.Logger,
+	content string,
+	doc *goquery.Document,
+	wr *models.WebResource,
+) (*extraction, error) {
+	pageURL := wr.URL
+	article, err := ws.extractFromHTML(content, pageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonLD := articlemeta.FromJSONLD(doc)
+	metaTags := articlemeta.FromMetaTags(doc)
+
+	if len(article.Title) > 0 {
+		return newExtraction(article, models.GooseExtraction, jsonLD, metaTags), nil
+	}
+
+	if ampURL := articlemeta.AMPURL(doc, pageURL); len(ampURL) > 0 {
+		ampArticle, err := ws.extractAMPArticle(ctx, logger, ampURL)
+		if err != nil {
+			logger.Debug().Err(err).Str("AMP", ampURL).Msg("AMP page extraction failed")
+		} else if ampArticle != nil && len(ampArticle.Title) > 0 {
+			return newExtraction(ampArticle, models.AMPExtraction, jsonLD, metaTags), nil
+		}
+	}
+
+	if jsonLD != nil {
+		article.Title = jsonLD.Headline
+		return newExtraction(article, models.JSONLDExtraction, jsonLD, metaTags), nil
+	}
+
+	if metaTags != nil {
+		article.Title = metaTags.Headline
+		return newExtraction(article, models.MetaTagsExtraction, metaTags), nil
+	}
+
+	if wr.FeedItem != nil && len(wr.FeedItem.Title) > 0 {
+		article.Title = wr.FeedItem.Title
+		return newExtraction(article, models.FeedItemExtraction), nil
+	}
+
+	return nil, nil
+}
+
+// extractAMPArticle scrapes and extracts the AMP version of a page. It
+// returns nil if the page has no content.
+func (ws *WebScraper) extractAMPArticle(ctx context.Context, logger zerolog.Logger, ampURL string) (*goose.Article, error) {
+	content, err := ws.scrapeURL(ctx, logger, ampURL)
+	if err != nil || len(content) == 0 {
+		return nil, err
+	}
+	return ws.extractFromHTML(content, ampURL)
+}
+
+// newExtraction creates a new extraction, setting the values of the article
+// which are still missing from the given metadata, in order of preference.
+func newExtraction(article *goose.Article, strategy models.ExtractionStrategy, metadata ...*articlemeta.Metadata) *extraction {
+	ex := &extraction{
+		article:  article,
+		strategy: strategy,
+	}
+	for _, md := range metadata {
+		if md == nil {
+			continue
+		}
+		if len(ex.authors) == 0 {
+			ex.authors = md.Authors
+		}
+		if len(article.MetaDescription) == 0 {
+			article.MetaDescription = md.Description
+		}
+		if len(article.CleanedText) == 0 {
+			article.CleanedText = md.Body
+		}
+		if len(article.TopImage) == 0 {
+			article.TopImage = md.Image
+		}
+		if article.PublishDate == nil {
+			article.PublishDate = md.DatePublished
+		}
+	}
+
+	// With no better content, the description is the most meaningful text
+	// recovered from the structured data.
+	if strategy != models.GooseExtraction && strategy != models.AMPExtraction && len(article.CleanedText) == 0 {
+		article.CleanedText = article.MetaDescription
+	}
+	return ex
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webscraper
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+// scrapeFailure is an error which prevented the scraping of a web page. It
+// is recorded on the WebResource, instead of making the job fail.
+type scrapeFailure struct {
+	status     models.ScrapeStatus
+	reason     models.ScrapeReason
+	httpStatus int
+	err        error
+}
+
+func (f *scrapeFailure) Error() string {
+	return fmt.Sprintf("%s (%s): %v", f.status, f.reason, f.err)
+}
+
+func (f *scrapeFailure) Unwrap() error {
+	return f.err
+}
+
+// failed creates a new scrapeFailure for a transient reason.
+func failed(reason models.ScrapeReason, err error) *scrapeFailure {
+	return &scrapeFailure{status: models.FailedScrape, reason: reason, err: err}
+}
+
+// skipped creates a new scrapeFailure for a permanent reason.
+func skipped(reason models.ScrapeReason, err error) *scrapeFailure {
+	return &scrapeFailure{status: models.SkippedScrape, reason: reason, err: err}
+}
+
+// httpStatusFailure creates a new scrapeFailure for a response status code
+// other than 200. Server errors, timeouts and rate limits are transient.
+func httpStatusFailure(url string, code int) *scrapeFailure {
+	f := skipped(models.HTTPStatusScrapeReason, fmt.Errorf("request for %#v returned status code %d", url, code))
+	if code >= 500 || code == http.StatusRequestTimeout || code == http.StatusTooManyRequests {
+		f.status = models.FailedScrape
+	}
+	f.httpStatus = code
+	return f
+}
+
+// startScrapeAttempt resets the outcome of the previous scraping attempt of
+// the WebResource, and counts a new one.
+func startScrapeAttempt(wr *models.WebResource) {
+	wr.ScrapeStatus = models.NotScraped
+	wr.ScrapeReason = ""
+	wr.ScrapeHTTPStatus = 0
+	wr.ScrapeAttempts++
+	wr.LastScrapeAttemptAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+}
+
+// setScrapeStatus sets the outcome of the current scraping attempt of the
+// WebResource.
+func setScrapeStatus(wr *models.WebResource, status models.ScrapeStatus, reason models.ScrapeReason) {
+	wr.ScrapeStatus = status
+	wr.ScrapeReason = reason
+}
+
+// saveScrapeStatus stores the outcome of the current scraping attempt of
+// the WebResource.
+func saveScrapeStatus(tx *gorm.DB, wr *models.WebResource) error {
+	res := tx.Model(wr).Updates(map[string]interface{}{
+		"scrape_status":          wr.ScrapeStatus,
+		"scrape_reason":          wr.ScrapeReason,
+		"scrape_http_status":     wr.ScrapeHTTPStatus,
+		"scrape_attempts":        wr.ScrapeAttempts,
+		"last_scrape_attempt_at": wr.LastScrapeAttemptAt,
+	})
+	if res.Error != nil {
+		return fmt.Errorf("error saving WebResource scrape status: %w", res.Error)
+	}
+	return nil
+}
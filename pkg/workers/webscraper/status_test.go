@@ -0,0 +1,77 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webscraper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHTTPStatusFailure(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		code   int
+		status models.ScrapeStatus
+	}{
+		{301, models.SkippedScrape},
+		{400, models.SkippedScrape},
+		{401, models.SkippedScrape},
+		{403, models.SkippedScrape},
+		{404, models.SkippedScrape},
+		{408, models.FailedScrape},
+		{410, models.SkippedScrape},
+		{429, models.FailedScrape},
+		{500, models.FailedScrape},
+		{502, models.FailedScrape},
+		{503, models.FailedScrape},
+		{504, models.FailedScrape},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("%d", tc.code), func(t *testing.T) {
+			t.Parallel()
+			f := httpStatusFailure("https://example.com", tc.code)
+			assert.Equal(t, tc.status, f.status)
+			assert.Equal(t, models.HTTPStatusScrapeReason, f.reason)
+			assert.Equal(t, tc.code, f.httpStatus)
+		})
+	}
+}
+
+func TestScrapeFailure(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("wrapped: %w", failed(models.NetworkErrorScrapeReason, cause))
+
+	var failure *scrapeFailure
+	require.True(t, errors.As(err, &failure))
+	assert.Equal(t, models.FailedScrape, failure.status)
+	assert.Equal(t, models.NetworkErrorScrapeReason, failure.reason)
+	assert.True(t, errors.Is(err, cause))
+	assert.Equal(t, "wrapped: failed (network_error): connection refused", err.Error())
+}
+
+func TestStartScrapeAttempt(t *testing.T) {
+	t.Parallel()
+
+	wr := &models.WebResource{
+		ScrapeStatus:     models.FailedScrape,
+		ScrapeReason:     models.HTTPStatusScrapeReason,
+		ScrapeHTTPStatus: 503,
+		ScrapeAttempts:   2,
+	}
+	startScrapeAttempt(wr)
+	assert.Equal(t, models.NotScraped, wr.ScrapeStatus)
+	assert.Empty(t, wr.ScrapeReason)
+	assert.Zero(t, wr.ScrapeHTTPStatus)
+	assert.Equal(t, 3, wr.ScrapeAttempts)
+	assert.True(t, wr.LastScrapeAttemptAt.Valid)
+}
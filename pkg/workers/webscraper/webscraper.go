@@ -7,14 +7,19 @@ package webscraper
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/articletext"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/database"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/languagerecognition"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/scrapepolicy"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/walldetector"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
 	goose "github.com/advancedlogic/GoOse"
 	"github.com/contribsys/faktory_worker_go"
@@ -27,6 +32,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -34,22 +40,27 @@ import (
 // new WebArticles from existing WebResources.
 type WebScraper struct {
 	basemodelworker.Worker
-	conf    config.WebScraper
-	client  *http.Client
-	scraper goose.Goose
+	conf     config.WebScraper
+	client   *scrapepolicy.Client
+	scraper  goose.Goose
+	detector *walldetector.Detector
+
+	policiesMu       sync.Mutex
+	policiesLoadedAt time.Time
 }
 
 // New creates a new WebScraper.
-func New(conf config.WebScraper, db *gorm.DB, fk *faktory_worker.Manager) *WebScraper {
+func New(conf config.WebScraper, db *gorm.DB, fk *faktory_worker.Manager) (*WebScraper, error) {
+	detector, err := walldetector.New(conf.WallDetection)
+	if err != nil {
+		return nil, err
+	}
+
 	ws := &WebScraper{
-		conf: conf,
-		client: &http.Client{
-			Timeout: conf.RequestTimeout,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-		},
-		scraper: goose.New(),
+		conf:     conf,
+		client:   scrapepolicy.NewClient(conf),
+		scraper:  goose.New(),
+		detector: detector,
 	}
 	ws.Worker = basemodelworker.Worker{
 		Name:        "WebScraper",
@@ -60,27 +71,58 @@ func New(conf config.WebScraper, db *gorm.DB, fk *faktory_worker.Manager) *WebSc
 		Queues:      conf.Queues,
 		Perform:     ws.perform,
 	}
-	return ws
+	return ws, nil
 }
 
 func (ws *WebScraper) perform(ctx context.Context, webResourceID uint) error {
 	tx := ws.DB.WithContext(ctx)
 
+	err := ws.refreshPolicies(tx)
+	if err != nil {
+		return err
+	}
+
 	wr, err := getWebResourceWithRelations(tx, webResourceID)
 	if err != nil {
 		return err
 	}
+	if wr.WebArticle != nil {
+		ws.Log.Warn().Uint("WebResource", wr.ID).Uint("WebArticle", wr.WebArticle.ID).Msg("a WebArticle already exists")
+		return nil
+	}
 
+	startScrapeAttempt(wr)
 	wa, err := ws.processWebResource(ctx, tx, wr)
+	var failure *scrapeFailure
+	if errors.As(err, &failure) {
+		level := zerolog.DebugLevel
+		if failure.status == models.FailedScrape {
+			level = zerolog.WarnLevel
+		}
+		ws.Log.WithLevel(level).Err(err).Uint("WebResource", wr.ID).Msg("scraping unsuccessful")
+		setScrapeStatus(wr, failure.status, failure.reason)
+		if failure.httpStatus != 0 {
+			wr.ScrapeHTTPStatus = failure.httpStatus
+		}
+		return ws.saveScrapeStatusOrFail(tx, wr)
+	}
 	if err != nil {
-		return err
+		return ws.failAttempt(wr, err)
 	}
 	if wa == nil {
-		return nil // skipped
+		return ws.saveScrapeStatusOrFail(tx, wr) // blocked
+	}
+	if wr.ScrapeStatus == models.NotScraped {
+		setScrapeStatus(wr, models.SucceededScrape, "")
 	}
 
 	js := jobscheduler.New()
 	err = tx.Transaction(func(tx *gorm.DB) error {
+		err := saveScrapeStatus(tx, wr)
+		if err != nil {
+			return err
+		}
+
 		res := tx.Create(wa)
 		if database.IsUniqueViolationError(res.Error) {
 			ws.Log.Warn().Err(res.Error).Uint("WebResource", wr.ID).
@@ -91,15 +133,45 @@ func (ws *WebScraper) perform(ctx context.Context, webResourceID uint) error {
 			return fmt.Errorf("error creating WebArticle: %w", res.Error)
 		}
 
+		err = stagenotifier.Notify(tx, stagenotifier.WebScraper, wa.ID)
+		if err != nil {
+			return err
+		}
+
 		return js.AddJobsAndCreatePendingJobs(tx, ws.conf.NewWebArticleJobs, wa.ID)
 	})
 	if err != nil {
-		return err
+		return ws.failAttempt(wr, err)
 	}
 
 	return js.PushJobsAndDeletePendingJobs(ctx, ws.DB)
 }
 
+// saveScrapeStatusOrFail stores the outcome of the current scraping attempt
+// of the WebResource, or records the attempt as failed if it cannot be
+// stored.
+func (ws *WebScraper) saveScrapeStatusOrFail(tx *gorm.DB, wr *models.WebResource) error {
+	err := saveScrapeStatus(tx, wr)
+	if err != nil {
+		return ws.failAttempt(wr, err)
+	}
+	return nil
+}
+
+// failAttempt records the current scraping attempt of the WebResource as
+// failed after an unexpected error, which is returned, so that the
+// WebResource is never left in the "retrying" status by a failing job.
+//
+// The job context is not used, since the error might be caused by its
+// cancellation.
+func (ws *WebScraper) failAttempt(wr *models.WebResource, err error) error {
+	setScrapeStatus(wr, models.FailedScrape, models.UnexpectedErrorScrapeReason)
+	if saveErr := saveScrapeStatus(ws.DB, wr); saveErr != nil {
+		ws.Log.Err(saveErr).Uint("WebResource", wr.ID).Msg("error recording the failed scraping attempt")
+	}
+	return err
+}
+
 func getWebResourceWithRelations(tx *gorm.DB, wrID uint) (*models.WebResource, error) {
 	var wr *models.WebResource
 
@@ -112,7 +184,10 @@ func getWebResourceWithRelations(tx *gorm.DB, wrID uint) (*models.WebResource, e
 	res := tx.
 		Joins("WebArticle").
 		Joins("FeedItem").
+		Joins("SitemapItem").
+		Joins("APIItem").
 		Joins("GDELTEvent").
+		Joins("GDELTGKGRecord").
 		First(&wr, wrID)
 	if res.Error != nil {
 		return nil, fmt.Errorf("error fetching WebResource %d: %w", wrID, res.Error)
@@ -120,6 +195,10 @@ func getWebResourceWithRelations(tx *gorm.DB, wrID uint) (*models.WebResource, e
 	return wr, nil
 }
 
+// processWebResource scrapes the WebResource, returning the new WebArticle,
+// or nil if a wall was detected and there is no fallback. A *scrapeFailure
+// is returned when no WebArticle can be created. The outcome of a wall
+// detection is set on the WebResource.
 func (ws *WebScraper) processWebResource(
 	ctx context.Context,
 	tx *gorm.DB,
@@ -127,53 +206,79 @@ func (ws *WebScraper) processWebResource(
 ) (*models.WebArticle, error) {
 	logger := ws.Log.With().Uint("WebResource", wr.ID).Logger()
 
-	if wr.WebArticle != nil {
-		logger.Warn().Uint("WebArticle", wr.WebArticle.ID).Msg("a WebArticle already exists")
-		return nil, nil
-	}
-
 	body, err := ws.scrapeURL(ctx, logger, wr.URL)
 	if err != nil {
 		return nil, err
 	}
+	wr.ScrapeHTTPStatus = http.StatusOK
 	if len(body) == 0 {
-		logger.Debug().Msgf("empty body: skipping article")
-		return nil, nil
+		return nil, skipped(models.EmptyBodyScrapeReason, errors.New("empty body"))
 	}
 
-	article, err := ws.extractFromHTML(body, wr.URL)
+	// GoOse cleans its own document, removing the scripts, so the raw
+	// content is parsed again for the structured data and the walls.
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
 	if err != nil {
-		logger.Warn().Err(err).Msgf("error extracting article from HTML: skipping article")
-		return nil, nil
+		return nil, skipped(models.ExtractionErrorScrapeReason, fmt.Errorf("error parsing HTML: %w", err))
+	}
+
+	ex, err := ws.extractArticle(ctx, logger, body, doc, wr)
+	if err != nil {
+		return nil, skipped(models.ExtractionErrorScrapeReason, fmt.Errorf("error extracting article from HTML: %w", err))
+	}
+
+	if wall := ws.detectWall(ex, doc); wall != walldetector.NoWall {
+		setScrapeStatus(wr, models.BlockedScrape, models.ScrapeReason(wall))
+		ex = ws.feedItemExtraction(wr)
+		if ex == nil {
+			logger.Info().Str("Wall", string(wall)).Msg("wall detected: skipping article")
+			return nil, nil
+		}
+		logger.Info().Str("Wall", string(wall)).Msg("wall detected: falling back to the feed item")
+	}
+
+	if ex == nil {
+		return nil, skipped(models.NoTitleScrapeReason, errors.New("no article title found"))
 	}
+	article := ex.article
+	logger.Trace().Str("Strategy", string(ex.strategy)).Msg("article extracted")
 
 	similarExists, err := webArticleWithSameTitleExists(tx, article.Title)
 	if err != nil {
 		return nil, err
 	}
 	if similarExists {
-		logger.Debug().Str("Title", article.Title).Msgf("web article with same title already exists: article skipped")
-		return nil, nil
+		return nil, skipped(models.DuplicateTitleScrapeReason,
+			fmt.Errorf("web article with same title %#v already exists", article.Title))
 	}
 
 	lang, langOk := resolveOrDetectLanguage(wr, article)
 	if !langOk {
-		logger.Warn().Str("Title", article.Title).Msg("failed to detect language")
-		return nil, nil
+		return nil, skipped(models.UndetectedLanguageScrapeReason,
+			fmt.Errorf("failed to detect language of %#v", article.Title))
 	}
 	if !ws.languageIsAllowed(lang) {
-		logger.Debug().Str("Title", article.Title).Str("Lang", lang).Msg("language is not allowed")
-		return nil, nil
+		return nil, skipped(models.LanguageNotAllowedScrapeReason,
+			fmt.Errorf("language %#v is not allowed", lang))
 	}
 
-	webArticle := ws.newWebArticle(wr, article, lang)
+	webArticle, err := ws.newWebArticle(wr, ex, lang)
+	if err != nil {
+		return nil, err
+	}
 	return webArticle, nil
 }
 
-func (ws *WebScraper) newWebArticle(wr *models.WebResource, article *goose.Article, lang string) *models.WebArticle {
+func (ws *WebScraper) newWebArticle(wr *models.WebResource, ex *extraction, lang string) (*models.WebArticle, error) {
+	article := ex.article
 	title := article.Title
-	if wr.FeedItem != nil && len(wr.FeedItem.Title) > 0 {
+	switch {
+	case wr.FeedItem != nil && len(wr.FeedItem.Title) > 0:
 		title = wr.FeedItem.Title
+	case wr.SitemapItem != nil && len(wr.SitemapItem.Title) > 0:
+		title = wr.SitemapItem.Title
+	case wr.APIItem != nil && len(wr.APIItem.Title) > 0:
+		title = wr.APIItem.Title
 	}
 
 	wa := &models.WebArticle{
@@ -194,7 +299,41 @@ func (ws *WebScraper) newWebArticle(wr *models.WebResource, article *goose.Artic
 		}
 	}
 
-	return wa
+	content, err := newWebArticleContent(ex)
+	if err != nil {
+		return nil, err
+	}
+	wa.Content = content
+
+	return wa, nil
+}
+
+// detectWall reports whether a paywall or a consent wall was served in
+// place of the article.
+func (ws *WebScraper) detectWall(ex *extraction, doc *goquery.Document) walldetector.Wall {
+	if ex == nil {
+		return ws.detector.Detect("", "", doc)
+	}
+	return ws.detector.Detect(ex.article.Title, ex.article.CleanedText, doc)
+}
+
+// feedItemExtraction creates an extraction from the title and the
+// description (or the content) of the feed item of the WebResource. It
+// returns nil if the fallback is disabled, or there is no feed item.
+func (ws *WebScraper) feedItemExtraction(wr *models.WebResource) *extraction {
+	if !ws.conf.WallDetection.FeedItemFallback || wr.FeedItem == nil || len(wr.FeedItem.Title) == 0 {
+		return nil
+	}
+	text := strings.TrimSpace(articletext.StripHTML(wr.FeedItem.Description))
+	if len(text) == 0 {
+		text = strings.TrimSpace(articletext.StripHTML(wr.FeedItem.Content))
+	}
+	article := &goose.Article{
+		Title:           wr.FeedItem.Title,
+		MetaDescription: text,
+		CleanedText:     text,
+	}
+	return &extraction{article: article, strategy: models.FeedItemExtraction}
 }
 
 func (ws *WebScraper) languageIsAllowed(lang string) bool {
@@ -221,16 +360,61 @@ func (ws *WebScraper) extractFromHTML(content, url string) (_ *goose.Article, er
 	return ws.scraper.ExtractFromRawHTML(content, url)
 }
 
-func (ws *WebScraper) scrapeURL(ctx context.Context, logger zerolog.Logger, url string) (_ string, err error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return "", fmt.Errorf("error creating HTTP request for %#v: %w", url, err)
+// refreshPolicies reloads the domain policies from the configuration and
+// the database, if they were not loaded in the last
+// PoliciesRefreshInterval. Invalid policies are ignored.
+func (ws *WebScraper) refreshPolicies(tx *gorm.DB) error {
+	ws.policiesMu.Lock()
+	defer ws.policiesMu.Unlock()
+
+	if !ws.policiesLoadedAt.IsZero() && time.Since(ws.policiesLoadedAt) < ws.conf.PoliciesRefreshInterval {
+		return nil
+	}
+
+	var domainPolicies []models.DomainPolicy
+	res := tx.Order("id").Find(&domainPolicies)
+	if res.Error != nil {
+		return fmt.Errorf("error fetching DomainPolicies: %w", res.Error)
 	}
-	req.Header.Add("User-Agent", ws.conf.UserAgent)
 
-	resp, err := ws.client.Do(req)
+	policies := make([]scrapepolicy.Policy, 0, len(ws.conf.DomainPolicies)+len(domainPolicies))
+	for _, c := range ws.conf.DomainPolicies {
+		p := scrapepolicy.FromConfig(c)
+		if err := p.Validate(); err != nil {
+			ws.Log.Warn().Err(err).Str("Domain", c.Domain).Msg("ignoring invalid domain policy from configuration")
+			continue
+		}
+		policies = append(policies, p)
+	}
+	for _, dp := range domainPolicies {
+		p, err := scrapepolicy.FromModel(dp)
+		if err == nil {
+			err = p.Validate()
+		}
+		if err != nil {
+			ws.Log.Warn().Err(err).Uint("DomainPolicy", dp.ID).Msg("ignoring invalid domain policy")
+			continue
+		}
+		policies = append(policies, p)
+	}
+
+	ws.client.SetPolicies(policies)
+	ws.policiesLoadedAt = time.Now()
+	return nil
+}
+
+// scrapeURL returns the content of the web page, converted to UTF-8. A
+// *scrapeFailure is returned when the page cannot be scraped.
+func (ws *WebScraper) scrapeURL(ctx context.Context, logger zerolog.Logger, url string) (_ string, err error) {
+	resp, err := ws.client.Get(ctx, url)
+	if errors.Is(err, scrapepolicy.ErrDisallowed) {
+		return "", skipped(models.RobotsTxtScrapeReason, err)
+	}
 	if err != nil {
-		return "", fmt.Errorf("error performing request for %#v: %w", url, err)
+		if ctx.Err() != nil {
+			return "", err
+		}
+		return "", failed(models.NetworkErrorScrapeReason, err)
 	}
 	defer func() {
 		if e := resp.Body.Close(); e != nil && err == nil {
@@ -239,36 +423,43 @@ func (ws *WebScraper) scrapeURL(ctx context.Context, logger zerolog.Logger, url
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("request for %#v returned status code %d", url, resp.StatusCode)
+		return "", httpStatusFailure(url, resp.StatusCode)
 	}
 
 	contentType := getContentType(resp)
 
 	if !isTextOrHTMLContent(contentType) {
 		logger.Debug().Msgf("ignoring content type %#v", contentType)
-		return "", nil
+		return "", skipped(models.ContentTypeScrapeReason, fmt.Errorf("unsupported content type %#v", contentType))
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("error reading response body for %#v: %w", url, err)
+		return "", failed(models.NetworkErrorScrapeReason, fmt.Errorf("error reading response body for %#v: %w", url, err))
 	}
 
 	enc := determineEncoding(body, contentType)
 	transformReader := transform.NewReader(bytes.NewReader(body), enc.NewDecoder())
 	transformedBody, err := io.ReadAll(transformReader)
 	if err != nil {
-		return "", fmt.Errorf("error transforming body to UTF-8: %w", err)
+		return "", skipped(models.ExtractionErrorScrapeReason, fmt.Errorf("error transforming body to UTF-8: %w", err))
 	}
 
 	return strings.TrimSpace(string(transformedBody)), nil
 }
 
 func resolveOrDetectLanguage(wr *models.WebResource, article *goose.Article) (string, bool) {
-	// Only FeedItems already have a recognized language
+	// Only FeedItems, and some SitemapItems and APIItems, already have a
+	// recognized language
 	if wr.FeedItem != nil {
 		return wr.FeedItem.Language, true
 	}
+	if wr.SitemapItem != nil && len(wr.SitemapItem.Language) > 0 {
+		return wr.SitemapItem.Language, true
+	}
+	if wr.APIItem != nil && len(wr.APIItem.Language) > 0 {
+		return wr.APIItem.Language, true
+	}
 	return languagerecognition.RecognizeLanguage(article.Title)
 }
 
@@ -276,8 +467,14 @@ func resolveArticleDate(wr *models.WebResource, article *goose.Article) time.Tim
 	switch {
 	case wr.FeedItem != nil && wr.FeedItem.PublishedAt.Valid:
 		return wr.FeedItem.PublishedAt.Time.UTC()
+	case wr.SitemapItem != nil && wr.SitemapItem.PublishedAt.Valid:
+		return wr.SitemapItem.PublishedAt.Time.UTC()
+	case wr.APIItem != nil && wr.APIItem.PublishedAt.Valid:
+		return wr.APIItem.PublishedAt.Time.UTC()
 	case wr.GDELTEvent != nil:
 		return wr.GDELTEvent.DateAdded.UTC()
+	case wr.GDELTGKGRecord != nil:
+		return wr.GDELTGKGRecord.Date.UTC()
 	case article.PublishDate != nil:
 		return article.PublishDate.UTC()
 	default:
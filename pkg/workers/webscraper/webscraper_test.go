@@ -0,0 +1,121 @@
+// Copyright 2021 SpecializedGeneralist. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package webscraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestWebScraper(t *testing.T) *WebScraper {
+	t.Helper()
+	ws, err := New(config.WebScraper{
+		RequestTimeout:   5 * time.Second,
+		UserAgent:        "WhatsNew/1.0",
+		RespectRobotsTxt: true,
+		RobotsTxtTTL:     time.Hour,
+	}, nil, nil)
+	require.NoError(t, err)
+	ws.Log = zerolog.Nop()
+	return ws
+}
+
+// TestWebScraper_processWebResource_Unsuccessful covers the scrapings which
+// end before any database access.
+func TestWebScraper_processWebResource_Unsuccessful(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			_, _ = fmt.Fprint(w, "User-agent: *\nDisallow: /private\n")
+		case "/not-found":
+			w.WriteHeader(http.StatusNotFound)
+		case "/unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/image":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte{0x89, 'P', 'N', 'G'})
+		case "/empty":
+			w.Header().Set("Content-Type", "text/html")
+		case "/untitled":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = fmt.Fprint(w, "<html><body><p>Just a paragraph.</p></body></html>")
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	testCases := []struct {
+		name       string
+		url        string
+		status     models.ScrapeStatus
+		reason     models.ScrapeReason
+		httpStatus int
+	}{
+		{"robots.txt", server.URL + "/private/1", models.SkippedScrape, models.RobotsTxtScrapeReason, 0},
+		{"client error", server.URL + "/not-found", models.SkippedScrape, models.HTTPStatusScrapeReason, http.StatusNotFound},
+		{"server error", server.URL + "/unavailable", models.FailedScrape, models.HTTPStatusScrapeReason, http.StatusServiceUnavailable},
+		{"content type", server.URL + "/image", models.SkippedScrape, models.ContentTypeScrapeReason, 0},
+		{"empty body", server.URL + "/empty", models.SkippedScrape, models.EmptyBodyScrapeReason, 0},
+		{"no title", server.URL + "/untitled", models.SkippedScrape, models.NoTitleScrapeReason, 0},
+		{"network error", closed.URL + "/news", models.FailedScrape, models.NetworkErrorScrapeReason, 0},
+	}
+
+	ws := newTestWebScraper(t)
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			wr := &models.WebResource{URL: tc.url}
+			wa, err := ws.processWebResource(context.Background(), nil, wr)
+			assert.Nil(t, wa)
+
+			var failure *scrapeFailure
+			require.True(t, errors.As(err, &failure), "%v", err)
+			assert.Equal(t, tc.status, failure.status)
+			assert.Equal(t, tc.reason, failure.reason)
+			assert.Equal(t, tc.httpStatus, failure.httpStatus)
+		})
+	}
+}
+
+func TestWebScraper_extractArticle_FeedItemTitle(t *testing.T) {
+	t.Parallel()
+
+	const content = "<html><body><p>Just a paragraph.</p></body></html>"
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	require.NoError(t, err)
+
+	ws := newTestWebScraper(t)
+	wr := &models.WebResource{
+		URL:      "https://example.com/news/1",
+		FeedItem: &models.FeedItem{Title: "Feed Item Title"},
+	}
+	ex, err := ws.extractArticle(context.Background(), ws.Log, content, doc, wr)
+	require.NoError(t, err)
+	require.NotNil(t, ex)
+	assert.Equal(t, models.FeedItemExtraction, ex.strategy)
+	assert.Equal(t, "Feed Item Title", ex.article.Title)
+
+	wr.FeedItem = nil
+	ex, err = ws.extractArticle(context.Background(), ws.Log, content, doc, wr)
+	require.NoError(t, err)
+	assert.Nil(t, ex)
+}
@@ -8,24 +8,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/articletext"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/config"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/grpcconn"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/jobscheduler"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/models"
+	"github.com/SpecializedGeneralist/whatsnew/pkg/stagenotifier"
 	"github.com/SpecializedGeneralist/whatsnew/pkg/workers/basemodelworker"
 	"github.com/contribsys/faktory_worker_go"
 	"github.com/nlpodyssey/spago/pkg/nlp/transformers/bart/server/grpcapi"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
-	"strings"
+	"sort"
 )
 
 // ZeroShotClassifier implements a Faktory worker for classifying existing
 // WebArticles with spaGO BART zero-shot classification service.
 type ZeroShotClassifier struct {
 	basemodelworker.Worker
-	conf config.ZeroShotClassifier
+	conf         config.ZeroShotClassifier
+	textSelector *articletext.Selector
 }
 
 // New creates a new ZeroShotClassifier.
@@ -35,7 +38,8 @@ func New(
 	fk *faktory_worker.Manager,
 ) *ZeroShotClassifier {
 	zsc := &ZeroShotClassifier{
-		conf: conf,
+		conf:         conf,
+		textSelector: articletext.NewSelector(conf.TextSelection),
 	}
 
 	zsc.Worker = basemodelworker.Worker{
@@ -77,6 +81,11 @@ func (zsc *ZeroShotClassifier) perform(ctx context.Context, webArticleID uint) e
 			}
 		}
 
+		err := stagenotifier.Notify(tx, stagenotifier.ZeroShotClassifier, wa.ID)
+		if err != nil {
+			return err
+		}
+
 		return js.AddJobsAndCreatePendingJobs(tx, zsc.conf.ProcessedWebArticleJobs, wa.ID)
 	})
 	if err != nil {
@@ -107,13 +116,12 @@ func (zsc *ZeroShotClassifier) processWebArticle(
 		return nil, errSkip
 	}
 
-	title := strings.TrimSpace(wa.Title)
-	if wa.TranslatedTitle.Valid {
-		title = strings.TrimSpace(wa.TranslatedTitle.String)
+	text, err := zsc.textSelector.Select(tx, wa)
+	if err != nil {
+		return nil, err
 	}
-
-	if len(title) == 0 {
-		logger.Debug().Msg("empty title - web article skipped")
+	if text.IsEmpty() {
+		logger.Debug().Msg("empty text - web article skipped")
 		return nil, errSkip
 	}
 
@@ -124,7 +132,7 @@ func (zsc *ZeroShotClassifier) processWebArticle(
 
 	var classes []*models.ZeroShotClass
 	for _, template := range templates {
-		newClasses, err := zsc.classify(ctx, wa.ID, title, template)
+		newClasses, err := zsc.classify(ctx, wa.ID, text.Chunks, template)
 		if err != nil {
 			return nil, err
 		}
@@ -143,10 +151,13 @@ func (zsc *ZeroShotClassifier) getHypotheses(tx *gorm.DB) ([]models.ZeroShotHypo
 	return templates, nil
 }
 
+// classify classifies the text with the given template. If the text is
+// split into more chunks, each chunk is classified separately, and the
+// highest confidence of each label is kept.
 func (zsc *ZeroShotClassifier) classify(
 	ctx context.Context,
 	webArticleID uint,
-	text string,
+	chunks []string,
 	template models.ZeroShotHypothesisTemplate,
 ) ([]*models.ZeroShotClass, error) {
 	if len(template.Labels) == 0 {
@@ -175,32 +186,50 @@ func (zsc *ZeroShotClassifier) classify(
 		labelToID[l.Text] = l.ID
 	}
 
-	reply, err := bartClient.ClassifyNLI(ctx, &grpcapi.ClassifyNLIRequest{
-		Text:               text,
-		HypothesisTemplate: template.Text,
-		PossibleLabels:     possibleLabels,
-		MultiClass:         template.MultiClass,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("BART ClassifyNLI error: %w", err)
-	}
-	distribution := reply.GetDistribution()
-	if len(distribution) == 0 {
-		return nil, fmt.Errorf("BART ClassifyNLI returned an empty distribution")
-	}
+	var labels []string
+	confidences := make(map[string]float64, len(template.Labels))
 
-	classes := make([]*models.ZeroShotClass, len(distribution))
-	for i, pair := range distribution {
-		labelID, ok := labelToID[pair.Class]
-		if !ok {
-			return nil, fmt.Errorf("ClassifyNLI returned an unknown class: %#v", pair.Class)
+	for _, chunk := range chunks {
+		reply, err := bartClient.ClassifyNLI(ctx, &grpcapi.ClassifyNLIRequest{
+			Text:               chunk,
+			HypothesisTemplate: template.Text,
+			PossibleLabels:     possibleLabels,
+			MultiClass:         template.MultiClass,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("BART ClassifyNLI error: %w", err)
 		}
+		distribution := reply.GetDistribution()
+		if len(distribution) == 0 {
+			return nil, fmt.Errorf("BART ClassifyNLI returned an empty distribution")
+		}
+
+		for _, pair := range distribution {
+			if _, ok := labelToID[pair.Class]; !ok {
+				return nil, fmt.Errorf("ClassifyNLI returned an unknown class: %#v", pair.Class)
+			}
+			c, seen := confidences[pair.Class]
+			if !seen {
+				labels = append(labels, pair.Class)
+			}
+			if !seen || pair.Confidence > c {
+				confidences[pair.Class] = pair.Confidence
+			}
+		}
+	}
+
+	sort.SliceStable(labels, func(i, j int) bool {
+		return confidences[labels[i]] > confidences[labels[j]]
+	})
+
+	classes := make([]*models.ZeroShotClass, len(labels))
+	for i, label := range labels {
 		classes[i] = &models.ZeroShotClass{
 			WebArticleID:                 webArticleID,
-			ZeroShotHypothesisLabelID:    labelID,
+			ZeroShotHypothesisLabelID:    labelToID[label],
 			ZeroShotHypothesisTemplateID: template.ID,
 			Best:                         i == 0,
-			Confidence:                   float32(pair.Confidence),
+			Confidence:                   float32(confidences[label]),
 		}
 	}
 	return classes, nil